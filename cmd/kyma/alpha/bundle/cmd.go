@@ -0,0 +1,18 @@
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new bundle command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manages bundles for the deployment of Kyma without internet access.",
+		Long: `Use this command to manage bundles, which contain everything required to deploy Kyma on clusters without outbound internet access.
+
+A bundle contains the Kyma sources, the components file, the values files, and the list of all container images referenced by the Kyma components.
+Optionally, it also contains the tarball of all container images.`,
+	}
+	return cmd
+}
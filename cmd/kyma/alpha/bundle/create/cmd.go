@@ -0,0 +1,194 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
+	"github.com/kyma-project/cli/internal/bundle"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/images"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const imagesTimeout = 60 * time.Minute
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new create command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Creates a bundle for the deployment of Kyma without internet access.",
+		Long: `Use this command to create a bundle that contains everything required to deploy Kyma on a cluster without outbound internet access.
+
+The bundle contains the Kyma sources, the components file, the values files, and the list of all container images referenced by the rendered Kyma components.
+To include the tarball of all container images, use the --include-images flag. This requires a running Docker daemon. When you deploy the bundle with the --image-registry flag, the images are pushed to that registry.
+
+Usage Examples:
+  Create a bundle of Kyma 2.0.0:
+		kyma alpha bundle create --source 2.0.0 -o kyma-2.0.0.tgz
+  Deploy Kyma from the bundle:
+		kyma alpha deploy --bundle kyma-2.0.0.tgz
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", "main", `Kyma source of the bundle, for example, a release version ("2.0.0"), a branch ("main"), a commit ("34edf09a"), a pull request ("PR-9486"), or the local sources ("local")`)
+	cobraCmd.Flags().StringVarP(&o.WorkspacePath, "workspace", "w", "", `Path to the local Kyma sources (required if "--source=local" is used)`)
+	cobraCmd.Flags().StringVarP(&o.ComponentsFile, "components-file", "c", "", "Path to the components file (default: the components file of the Kyma sources)")
+	cobraCmd.Flags().StringSliceVarP(&o.OverridesFiles, "values-file", "f", []string{}, "Path(s) to one or more JSON or YAML files with configuration values")
+	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "p", "", "Kyma deployment profile used to render the components")
+	cobraCmd.Flags().BoolVar(&o.IncludeImages, "include-images", false, "Pulls all container images and adds their tarball to the bundle")
+	cobraCmd.Flags().StringVarP(&o.Output, "output", "o", "", `Path of the bundle file (default "kyma-{SOURCE}.tgz")`)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	if cmd.opts.Verbose {
+		cmd.Factory.UseLogger = true
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kyma-bundle-")
+	if err != nil {
+		return errors.Wrap(err, "Could not create temporary folder")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content := bundle.Content{
+		Metadata: bundle.Metadata{
+			Source:  cmd.opts.Source,
+			Created: time.Now(),
+		},
+	}
+
+	if err := cmd.resolveSources(&content); err != nil {
+		return err
+	}
+
+	filesStep := cmd.NewStep("Collecting components file and values files")
+	if content.ComponentsFile, err = cmd.componentsFile(content.SourcesPath, tmpDir); err != nil {
+		filesStep.Failure()
+		return err
+	}
+	if content.ValuesFiles, err = download.GetFiles(cmd.opts.OverridesFiles, tmpDir); err != nil {
+		filesStep.Failure()
+		return err
+	}
+	filesStep.Successf("Collected components file and %d values files", len(content.ValuesFiles))
+
+	imagesStep := cmd.NewStep("Collecting container images of Kyma components")
+	if content.Images, err = cmd.collectImages(content.SourcesPath, content.ComponentsFile, content.ValuesFiles); err != nil {
+		imagesStep.Failure()
+		return err
+	}
+	imagesStep.Successf("Found %d container images", len(content.Images))
+
+	if cmd.opts.IncludeImages {
+		if content.ImagesArchive, err = cmd.saveImages(content.Images, tmpDir); err != nil {
+			return err
+		}
+	}
+
+	bundleStep := cmd.NewStep(fmt.Sprintf("Creating bundle '%s'", cmd.opts.outputFile()))
+	if err := bundle.Create(cmd.opts.outputFile(), content); err != nil {
+		bundleStep.Failure()
+		return err
+	}
+	bundleStep.Successf("Bundle '%s' created", cmd.opts.outputFile())
+	return nil
+}
+
+//resolveSources makes the Kyma sources available (remote sources are taken from the local source cache)
+func (cmd *command) resolveSources(content *bundle.Content) error {
+	if cmd.opts.Source == localSource {
+		content.SourcesPath = cmd.opts.WorkspacePath
+		return nil
+	}
+
+	cacheDir, err := sources.DefaultDir()
+	if err != nil {
+		return err
+	}
+	sourcesStep := cmd.NewStep(fmt.Sprintf("Resolving Kyma sources (%s)", cmd.opts.Source))
+	src, _, err := sources.NewCache(cacheDir, sources.KymaRepositoryURL).Get(cmd.opts.Source)
+	if err != nil {
+		sourcesStep.Failure()
+		return err
+	}
+	sourcesStep.Successf("Using Kyma sources of commit %s", src.Commit[:8])
+	content.SourcesPath = src.Path
+	content.Commit = src.Commit
+	return nil
+}
+
+//componentsFile returns the components file of the bundle
+func (cmd *command) componentsFile(sourcesPath, tmpDir string) (string, error) {
+	if cmd.opts.ComponentsFile == "" {
+		return filepath.Join(sourcesPath, "installation", "resources", "components.yaml"), nil
+	}
+	return download.GetFile(cmd.opts.ComponentsFile, tmpDir)
+}
+
+//collectImages renders all components and returns the referenced container images
+func (cmd *command) collectImages(sourcesPath, componentsFile string, valuesFiles []string) ([]string, error) {
+	return images.CollectFromSources(sourcesPath, componentsFile, valuesFiles, cmd.opts.Profile)
+}
+
+//saveImages pulls all images and stores them as one tarball
+func (cmd *command) saveImages(imgs []string, tmpDir string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), imagesTimeout)
+	defer cancel()
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not create Docker client")
+	}
+	dockerClient.NegotiateAPIVersion(ctx)
+
+	for _, image := range imgs {
+		pullStep := cmd.NewStep(fmt.Sprintf("Pulling image '%s'", image))
+		if err := docker.PullImage(ctx, dockerClient, image); err != nil {
+			pullStep.Failure()
+			return "", err
+		}
+		pullStep.Success()
+	}
+
+	saveStep := cmd.NewStep("Saving container images")
+	archive := filepath.Join(tmpDir, "images.tar")
+	out, err := os.Create(archive)
+	if err != nil {
+		saveStep.Failure()
+		return "", errors.Wrap(err, "Could not create image tarball")
+	}
+	defer out.Close()
+	if err := docker.SaveImages(ctx, dockerClient, imgs, out); err != nil {
+		saveStep.Failure()
+		return "", errors.Wrap(err, "Could not save container images")
+	}
+	saveStep.Successf("Saved %d container images", len(imgs))
+	return archive, nil
+}
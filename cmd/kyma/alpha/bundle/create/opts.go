@@ -0,0 +1,45 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+const localSource = "local"
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	Source         string
+	WorkspacePath  string
+	ComponentsFile string
+	OverridesFiles []string
+	Profile        string
+	IncludeImages  bool
+	Output         string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//outputFile returns the path of the bundle file
+func (o *Options) outputFile() string {
+	if o.Output != "" {
+		return o.Output
+	}
+	return fmt.Sprintf("kyma-%s.tgz", o.Source)
+}
+
+// validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if o.Source == "" {
+		return fmt.Errorf("Provide the Kyma source of the bundle")
+	}
+	if o.Source == localSource && o.WorkspacePath == "" {
+		return fmt.Errorf(`Provide the path to the local Kyma sources with the "workspace" flag`)
+	}
+	return nil
+}
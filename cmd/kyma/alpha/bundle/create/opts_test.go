@@ -0,0 +1,26 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Remote source", func(t *testing.T) {
+		opts := &Options{Source: "2.0.0"}
+		require.NoError(t, opts.validateFlags())
+		require.Equal(t, "kyma-2.0.0.tgz", opts.outputFile())
+	})
+	t.Run("Custom output file", func(t *testing.T) {
+		opts := &Options{Source: "2.0.0", Output: "/tmp/bundle.tgz"}
+		require.NoError(t, opts.validateFlags())
+		require.Equal(t, "/tmp/bundle.tgz", opts.outputFile())
+	})
+	t.Run("Local source requires workspace", func(t *testing.T) {
+		opts := &Options{Source: localSource}
+		require.Error(t, opts.validateFlags())
+		opts.WorkspacePath = "/path/to/kyma"
+		require.NoError(t, opts.validateFlags())
+	})
+}
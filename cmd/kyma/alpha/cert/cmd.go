@@ -0,0 +1,18 @@
+package cert
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new cert command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert",
+		Short: "Manages the self-signed certificates of custom Kyma domains.",
+		Long: `Use this command to manage the self-signed certificates of custom Kyma domains.
+
+For each domain, a local certificate authority (CA) and a wildcard certificate signed by the CA are stored in the "$HOME/.kyma/certs/{DOMAIN}" folder.
+The certificates are used by "kyma alpha deploy --generate-cert".`,
+	}
+	return cmd
+}
@@ -0,0 +1,98 @@
+package create
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/internal/certs"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new create command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Creates a self-signed wildcard certificate for a custom Kyma domain.",
+		Long: `Use this command to create a local certificate authority (CA) and a wildcard certificate for a custom Kyma domain, which is signed by the CA.
+The certificates are stored in the "$HOME/.kyma/certs/{DOMAIN}" folder. An existing certificate of the domain is reused until it expires, unless "--force" is set.
+A new certificate is signed by the existing CA of the domain, so that an imported CA stays valid.
+
+Usage Examples:
+  Create the certificate and deploy Kyma with it:
+		kyma alpha cert create --domain {DOMAIN} --import
+		kyma alpha deploy --domain {DOMAIN} --generate-cert`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Custom domain of the certificate (required)")
+	cobraCmd.Flags().DurationVar(&o.Validity, "validity", certs.DefaultValidity, "Validity of the certificate")
+	cobraCmd.Flags().BoolVar(&o.Force, "force", false, "Creates a new certificate even if the existing certificate of the domain is still valid")
+	cobraCmd.Flags().BoolVar(&o.Import, "import", false, "Imports the CA into the trusted certificates of the OS")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	dir, err := certs.DefaultDir()
+	if err != nil {
+		return err
+	}
+	store := certs.NewStore(dir)
+
+	certStep := cmd.NewStep(fmt.Sprintf("Creating certificate for domain '%s'", cmd.opts.Domain))
+	cert, err := store.Get(cmd.opts.Domain)
+	if err != nil {
+		certStep.Failure()
+		return err
+	}
+	if cert != nil && !cmd.opts.Force && time.Until(cert.NotAfter) > certs.RenewBefore {
+		certStep.Successf("Certificate for domain '%s' exists already (use --force to replace it)", cert.Domain)
+	} else {
+		if cert, err = store.Create(cmd.opts.Domain, cmd.opts.Validity); err != nil {
+			certStep.Failure()
+			return err
+		}
+		certStep.Successf("Created certificate for domain '%s'", cert.Domain)
+	}
+
+	fmt.Printf("CA certificate:  %s\n", cert.CAFile)
+	fmt.Printf("Certificate:     %s\n", cert.CrtFile)
+	fmt.Printf("Key:             %s\n", cert.KeyFile)
+	fmt.Printf("Valid until:     %s\n", cert.NotAfter.Format("2006-01-02 15:04:05"))
+
+	if cmd.opts.Import {
+		// create a simple step to print certificate import steps without a spinner (spinner overwrites sudo prompt)
+		f := step.Factory{
+			NonInteractive: true,
+		}
+		s := f.NewStep("Importing the CA of the certificate")
+		// the CA is stored locally: the certifier does not access the cluster
+		if err := trust.NewCertifier(nil).StoreCertificate(cert.CAFile, s); err != nil {
+			return err
+		}
+		s.Successf("CA of domain '%s' imported", cert.Domain)
+	}
+
+	fmt.Printf("\nTo deploy Kyma with the certificate, run: kyma alpha deploy --domain %s --generate-cert\n", cert.Domain)
+	return nil
+}
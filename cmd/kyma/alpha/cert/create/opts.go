@@ -0,0 +1,33 @@
+package create
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	Domain   string
+	Validity time.Duration
+	Force    bool
+	Import   bool
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if o.Domain == "" {
+		return fmt.Errorf(`Provide the domain of the certificate ("domain" flag)`)
+	}
+	if o.Validity <= 0 {
+		return fmt.Errorf("Validity must be greater than 0")
+	}
+	return nil
+}
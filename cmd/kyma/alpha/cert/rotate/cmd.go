@@ -0,0 +1,208 @@
+package rotate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/cmd/kyma/alpha/deploy"
+	"github.com/kyma-project/cli/internal/certs"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+)
+
+const defaultComponentTimeout = 6 * time.Minute
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new rotate command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replaces the certificate of the Kyma gateway on a running cluster.",
+		Long: `Use this command to replace the certificate of the Kyma gateway (the "global.tlsCrt" and "global.tlsKey" configuration values) on a running cluster.
+The new certificate is either read from files or generated as self-signed wildcard certificate, which is signed by the local CA of the domain.
+Only the components whose charts use the certificate are deployed again, with the sources of the installed Kyma version and the configuration values of the installed components.
+
+Usage Examples:
+  Replace the certificate with your own certificate:
+		kyma alpha cert rotate --tls-crt crt.pem --tls-key key.pem
+  Replace the certificate with a new self-signed certificate:
+		kyma alpha cert rotate --generate`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.TLSCrtFile, "tls-crt", "", "TLS certificate file of the new certificate")
+	cobraCmd.Flags().StringVar(&o.TLSKeyFile, "tls-key", "", "TLS key file of the new certificate")
+	cobraCmd.Flags().BoolVar(&o.Generate, "generate", false, `Generates a new self-signed wildcard certificate for the domain (see "kyma alpha cert create")`)
+	cobraCmd.Flags().DurationVar(&o.Validity, "validity", certs.DefaultValidity, "Validity of the generated certificate")
+	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Domain of the Kyma installation. If not set, the domain of the current certificate is used")
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", "", "Kyma version whose charts are deployed. If not set, the installed Kyma version is used")
+	cobraCmd.Flags().StringVarP(&o.WorkspacePath, "workspace", "w", "", "Path to download Kyma sources. If not set, the sources are kept in the local source cache")
+	cobraCmd.Flags().StringSliceVar(&o.Components, "component", []string{}, "Components which are deployed instead of the components which use the certificate (e.g. --component componentName@namespace)")
+	cobraCmd.Flags().DurationVar(&o.Timeout, "timeout", 20*time.Minute, "Maximum time for the deployment")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	oldCert, err := certs.Gateway(cmd.K8s.Static())
+	if err != nil {
+		return err
+	}
+	domain := cmd.opts.Domain
+	if domain == "" {
+		if domain = certs.Domain(oldCert); domain == "" {
+			return fmt.Errorf(`The current certificate is not a wildcard certificate: provide the domain ("domain" flag)`)
+		}
+	}
+
+	crtFile, keyFile, importFile, err := cmd.newCertificate(domain)
+	if err != nil {
+		return err
+	}
+	newCert, err := loadCertificate(crtFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	source := cmd.opts.Source
+	if source == "" {
+		if source, err = cmd.installedVersion(); err != nil {
+			return err
+		}
+	}
+
+	deployOpts := deploy.NewOptions(cmd.Options)
+	deployOpts.Source = source
+	deployOpts.WorkspacePath = cmd.opts.WorkspacePath
+	deployOpts.Components = cmd.opts.Components
+	deployOpts.Domain = domain
+	deployOpts.TLSCrtFile = crtFile
+	deployOpts.TLSKeyFile = keyFile
+	deployOpts.Timeout = cmd.opts.Timeout
+	deployOpts.TimeoutComponent = defaultComponentTimeout
+	if deployOpts.Timeout < deployOpts.TimeoutComponent {
+		deployOpts.TimeoutComponent = deployOpts.Timeout
+	}
+	deployOpts.Concurrency = 4
+	deployOpts.Validate = true
+	// keep the configuration values of the installed components, only the certificate changes
+	deployOpts.ReuseHelmValues = true
+
+	deployed, err := deploy.DeployConsumers(deployOpts, "global.tlsCrt", "global.tlsKey")
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.importCertificate(importFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCertificate of domain '%s' rotated\n", domain)
+	fmt.Printf("Deployed components:  %s\n", strings.Join(deployed, ", "))
+	fmt.Printf("Old certificate:      valid until %s\n", oldCert.NotAfter.Format("2006-01-02 15:04:05"))
+	fmt.Printf("New certificate:      valid until %s\n", newCert.NotAfter.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+//newCertificate returns the certificate and key files of the new certificate and the certificate file which is imported locally
+func (cmd *command) newCertificate(domain string) (crtFile, keyFile, importFile string, err error) {
+	if !cmd.opts.Generate {
+		return cmd.opts.TLSCrtFile, cmd.opts.TLSKeyFile, cmd.opts.TLSCrtFile, nil
+	}
+	dir, err := certs.DefaultDir()
+	if err != nil {
+		return "", "", "", err
+	}
+	certStep := cmd.NewStep(fmt.Sprintf("Generating certificate for domain '%s'", domain))
+	cert, err := certs.NewStore(dir).Create(domain, cmd.opts.Validity)
+	if err != nil {
+		certStep.Failure()
+		return "", "", "", err
+	}
+	certStep.Successf("Generated certificate for domain '%s'", domain)
+	// the CA makes all certificates of the domain trusted, also the following ones
+	return cert.CrtFile, cert.KeyFile, cert.CAFile, nil
+}
+
+//installedVersion returns the Kyma version of the installed components
+func (cmd *command) installedVersion() (string, error) {
+	provider, err := helm.NewKymaMetadataProvider(installConfig.KubeconfigSource{
+		Path: kube.KubeconfigPath(cmd.KubeconfigPath),
+	})
+	if err != nil {
+		return "", err
+	}
+	versionSet, err := provider.Versions()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get the installed Kyma versions")
+	}
+	if versionSet.Empty() {
+		return "", fmt.Errorf("No Kyma installation found")
+	}
+	if versionSet.Count() > 1 {
+		return "", fmt.Errorf(`Components of several Kyma versions are installed ('%s'): provide the Kyma version ("source" flag)`,
+			strings.Join(versionSet.Names(), "', '"))
+	}
+	return versionSet.Versions[0].Version, nil
+}
+
+//importCertificate imports the certificate into the trusted certificates of the OS if the user approves it
+func (cmd *command) importCertificate(file string) error {
+	approveStep := cmd.NewStep("Install Kyma certificate locally")
+	if cmd.NonInteractive || !approveStep.PromptYesNo("Should the new Kyma certificate be installed locally?") {
+		approveStep.Success()
+		return nil
+	}
+	approveStep.Success()
+
+	// create a simple step to print certificate import steps without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+	}
+	s := f.NewStep("Importing Kyma certificate")
+	if err := trust.NewCertifier(cmd.K8s).StoreCertificate(file, s); err != nil {
+		return err
+	}
+	s.Successf("Kyma root certificate imported")
+	return nil
+}
+
+//loadCertificate verifies that the certificate and the key match and returns the certificate
+func loadCertificate(crtFile, keyFile string) (*x509.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(crtFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load the new certificate")
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
@@ -0,0 +1,42 @@
+package rotate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	Domain        string
+	TLSCrtFile    string
+	TLSKeyFile    string
+	Generate      bool
+	Validity      time.Duration
+	Source        string
+	WorkspacePath string
+	Components    []string
+	Timeout       time.Duration
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	filesProvided := o.TLSCrtFile != "" || o.TLSKeyFile != ""
+	if filesProvided == o.Generate {
+		return fmt.Errorf(`Provide either "tls-crt" and "tls-key" or "generate" flag`)
+	}
+	if filesProvided && (o.TLSCrtFile == "" || o.TLSKeyFile == "") {
+		return fmt.Errorf(`Provide both "tls-crt" and "tls-key" flags`)
+	}
+	if o.Validity <= 0 {
+		return fmt.Errorf("Validity must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package rotate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Certificate files", func(t *testing.T) {
+		opts := &Options{TLSCrtFile: "crt.pem", TLSKeyFile: "key.pem", Validity: time.Hour}
+		require.NoError(t, opts.validateFlags())
+		opts.TLSKeyFile = ""
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Generated certificate", func(t *testing.T) {
+		opts := &Options{Generate: true, Validity: time.Hour}
+		require.NoError(t, opts.validateFlags())
+		opts.TLSCrtFile = "crt.pem"
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("No certificate", func(t *testing.T) {
+		opts := &Options{Validity: time.Hour}
+		require.Error(t, opts.validateFlags())
+	})
+}
@@ -0,0 +1,80 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/certs"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new status command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the validity of the Kyma gateway certificate.",
+		Long: `Use this command to show the domain, the issuer, and the validity of the certificate which the Kyma gateway presents.
+A warning is shown if the certificate expires within the given number of days. If the certificate has expired, the command fails.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().IntVar(&o.WarnDays, "warn-days", 30, "Shows a warning if the certificate expires within the given number of days")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	statusStep := cmd.NewStep("Checking the certificate of the Kyma gateway")
+	cert, err := certs.Gateway(cmd.K8s.Static())
+	if err != nil {
+		statusStep.Failure()
+		return err
+	}
+
+	remaining := time.Until(cert.NotAfter)
+	days := int(remaining.Hours() / 24)
+	switch {
+	case remaining <= 0:
+		statusStep.Failuref("The certificate expired on %s", cert.NotAfter.Format("2006-01-02"))
+	case days < cmd.opts.WarnDays:
+		statusStep.Failuref("The certificate expires in %d days, rotate it with \"kyma alpha cert rotate\"", days)
+	default:
+		statusStep.Successf("The certificate is valid for %d more days", days)
+	}
+
+	fmt.Printf("Domain:       %s\n", strings.Join(cert.DNSNames, ", "))
+	fmt.Printf("Issuer:       %s\n", cert.Issuer.CommonName)
+	fmt.Printf("Valid from:   %s\n", cert.NotBefore.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Valid until:  %s\n", cert.NotAfter.Format("2006-01-02 15:04:05"))
+
+	if remaining <= 0 {
+		return fmt.Errorf("The certificate of the Kyma gateway has expired")
+	}
+	return nil
+}
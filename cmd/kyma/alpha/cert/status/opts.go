@@ -0,0 +1,26 @@
+package status
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	WarnDays int
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if o.WarnDays < 0 {
+		return fmt.Errorf("The number of days cannot be negative")
+	}
+	return nil
+}
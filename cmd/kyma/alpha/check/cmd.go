@@ -0,0 +1,104 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/preflight"
+	"github.com/kyma-project/cli/internal/profiles"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new check command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verifies that the cluster fulfills the requirements of a Kyma deployment.",
+		Long: `Use this command to verify that your cluster is ready for a Kyma deployment, before you deploy Kyma.
+
+The following checks are executed:
+  - The Kubernetes version of the cluster is supported by the Kyma version defined with --source.
+  - The schedulable nodes provide enough CPU and memory for the profile defined with --profile.
+    With "--profile auto", the command reports the profile which "kyma alpha deploy" would select.
+  - A default StorageClass exists.
+  - The admission plugins and API groups which Kyma components rely on are available.
+  - No Istio installation exists which was not installed by Kyma.
+
+Each check passes, warns, or fails. For warnings and failures, the command describes how to fix the problem.
+Only missing admission plugins and API groups fail a check, all other problems are warnings.
+The command fails if at least one check fails. The same checks run at the start of "kyma alpha deploy".
+
+Usage Examples:
+  Check the cluster for a Kyma release with the production profile:
+		kyma alpha check --source 2.0.0 --profile production
+
+  Use the check as a gate in a pipeline:
+		kyma alpha check --ci -o json > preflight.json
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", "main", "Kyma version, branch, or pull request (e.g. PR-9486) which will be deployed")
+	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "p", "", fmt.Sprintf(`Kyma deployment profile which will be used. One of: "%s", "%s", or a user-defined profile. If not specified, the requirements of the default configuration are verified. User-defined profiles have no resource requirements.`,
+		profiles.Auto, strings.Join(profiles.BuiltIn, `", "`)))
+	cobraCmd.Flags().StringVarP(&o.Output, "output", "o", "", `Writes the results of all checks as JSON document to stdout if set to "json"`)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	if cmd.opts.Profile == profiles.Auto {
+		profile, capacity, err := profiles.Select(cmd.K8s.Static())
+		if err != nil {
+			return err
+		}
+		cmd.opts.Profile = profile
+		if cmd.opts.Output != jsonOutput {
+			cmd.NewStep("Selecting profile").Successf("Selected profile '%s' for a cluster with %s", profile, capacity)
+		}
+	}
+
+	report, err := preflight.Run(cmd.K8s.Static(), preflight.Config{Source: cmd.opts.Source, Profile: cmd.opts.Profile})
+	if err != nil {
+		return err
+	}
+
+	if cmd.opts.Output == jsonOutput {
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		report.PrintSteps(cmd.NewStep)
+	}
+
+	if report.Failed() {
+		return fmt.Errorf("At least one pre-flight check failed")
+	}
+	return nil
+}
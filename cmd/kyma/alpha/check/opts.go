@@ -0,0 +1,38 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/profiles"
+)
+
+const jsonOutput = "json"
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	Source  string
+	Profile string
+	Output  string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+// validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if o.Output != "" && o.Output != jsonOutput {
+		return fmt.Errorf("Output format '%s' is not supported. Supported output format is: %s", o.Output, jsonOutput)
+	}
+	if profiles.IsUserDefined(o.Profile) {
+		dir, err := profiles.DefaultDir()
+		if err != nil {
+			return err
+		}
+		return profiles.NewStore(dir).Validate(o.Profile)
+	}
+	return nil
+}
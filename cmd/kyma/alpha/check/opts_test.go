@@ -0,0 +1,26 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Human-readable output", func(t *testing.T) {
+		opts := &Options{}
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("JSON output", func(t *testing.T) {
+		opts := &Options{Output: "json"}
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("Built-in and automatic profiles", func(t *testing.T) {
+		require.NoError(t, (&Options{Profile: "production"}).validateFlags())
+		require.NoError(t, (&Options{Profile: "auto"}).validateFlags())
+	})
+	t.Run("Unsupported output", func(t *testing.T) {
+		opts := &Options{Output: "yaml"}
+		require.Error(t, opts.validateFlags())
+	})
+}
@@ -2,6 +2,8 @@ package uninstall
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -9,8 +11,13 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/hooks"
+	"github.com/kyma-project/cli/internal/hosts"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/kyma-project/cli/pkg/asyncui"
+	"github.com/kyma-project/cli/pkg/jsonevents"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/kyma-project/cli/pkg/timings"
 	"github.com/spf13/cobra"
 
 	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
@@ -33,9 +40,12 @@ func NewCmd(o *Options) *cobra.Command {
 	}
 
 	cobraCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Deletes Kyma from a running Kubernetes cluster.",
-		Long:    `Use this command to delete Kyma from a running Kubernetes cluster.`,
+		Use:   "delete",
+		Short: "Deletes Kyma from a running Kubernetes cluster.",
+		Long: `Use this command to delete Kyma from a running Kubernetes cluster.
+
+To delete only some components, select them with the "component" flag or a components file. Then, only the Helm releases of the selected components and the namespaces which no other component uses are deleted.
+Before anything is deleted, the command lists the Helm releases, namespaces, and CustomResourceDefinitions which will be deleted and asks for confirmation, unless you use the "ci" flag. To only list them, use the "dry-run" flag.`,
 		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 		Aliases: []string{"d"},
 	}
@@ -44,6 +54,12 @@ func NewCmd(o *Options) *cobra.Command {
 	cobraCmd.Flags().DurationVarP(&o.TimeoutComponent, "timeout-component", "", 360*time.Second, "Maximum time to delete the component")
 	cobraCmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Number of parallel processes")
 	cobraCmd.Flags().BoolVarP(&o.KeepCRDs, "keep-crds", "", false, "Flag specifying whether to keep CRDs on deletion")
+	cobraCmd.Flags().StringVar(&o.TimingsFile, "timings-file", "", "Path to a file where the start and end time of all deletion phases and components are written in the Chrome trace event format (viewable in chrome://tracing)")
+	cobraCmd.Flags().StringVar(&o.HooksFile, "hooks-file", "", `Path to a YAML file with hooks which run before ("pre-delete") or after ("post-delete") a component is deleted. A hook is either a shell command or the manifest of a Kubernetes Job`)
+	cobraCmd.Flags().StringSliceVar(&o.Components, "component", []string{}, "Provide one or more components to delete instead of all Kyma components (e.g. --component componentName@namespace)")
+	cobraCmd.Flags().StringVarP(&o.ComponentsFile, "components-file", "c", "", "Path to a components file which lists the components to delete instead of all Kyma components")
+	cobraCmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Lists the Helm releases, namespaces, and CustomResourceDefinitions which would be deleted without deleting them")
+	cobraCmd.Flags().StringVarP(&o.Output, "output", "o", "", `Output format of the deletion progress. Set "json-events" to write one JSON object per deletion event to stdout instead of displaying the progress steps. Requires the "ci" flag, because the deletion cannot be confirmed interactively.`)
 	return cobraCmd
 }
 
@@ -60,16 +76,40 @@ func (cmd *command) Run() error {
 	if cmd.opts.Verbose {
 		cmd.Factory.UseLogger = true
 	}
+	if cmd.opts.jsonEvents() {
+		// keep stdout free for the events: steps are logged to stderr
+		cmd.Factory.NonInteractive = true
+		cmd.Factory.UseLogger = true
+	}
 
 	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
 		return errors.Wrap(err, "Cannot initialize the Kubernetes client. Make sure your kubeconfig is valid")
 	}
 
 	//get list of installed Kyma components
-	compList, err := cmd.kymaComponentList()
+	installed, err := cmd.kymaComponentList()
+	if err != nil {
+		return err
+	}
+	compList := installed
+	if cmd.opts.selectsComponents() {
+		if compList, err = cmd.selectedComponents(installed); err != nil {
+			return err
+		}
+	}
+
+	plan, err := cmd.planDeletion(installed, compList)
 	if err != nil {
 		return err
 	}
+	if cmd.opts.DryRun {
+		fmt.Printf("The following resources would be deleted from cluster '%s':\n", cmd.K8s.RestConfig().Host)
+		plan.print(os.Stdout)
+		return nil
+	}
+	if !cmd.approveDeletion(plan) {
+		return fmt.Errorf("User decided not to delete the Kyma resources")
+	}
 
 	installCfg := &installConfig.Config{
 		WorkersCount:                  cmd.opts.Concurrency,
@@ -86,14 +126,25 @@ func (cmd *command) Run() error {
 		},
 	}
 
-	// if not verbose, use asyncui for clean output
+	// write JSON events for machines, otherwise use asyncui for clean output if not verbose:
+	// interactive terminals show the live view of the running components, all others one line per step
+	// (steps which run during the deletion, e.g. hooks, are created by the step factory of the live view)
 	var callback func(deployment.ProcessUpdate)
-	if !cmd.Verbose {
+	var stepFactory step.FactoryInterface = &cmd.Factory
+	switch {
+	case cmd.opts.jsonEvents():
+		callback = jsonevents.NewStream(os.Stdout).Callback()
+	case !cmd.Verbose && !cmd.NonInteractive && asyncui.IsTerminal():
+		ui := asyncui.NewLiveUI(os.Stdout, map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+			deployment.UninstallComponents: compList.Components,
+		})
+		ui.Tracker = asyncui.HelmTracker(cmd.K8s.Static())
+		defer ui.Stop()
+		callback = ui.Callback()
+		stepFactory = ui
+	case !cmd.Verbose:
 		ui := asyncui.AsyncUI{StepFactory: &cmd.Factory}
 		callback = ui.Callback()
-		if err != nil {
-			return err
-		}
 	}
 
 	commonRetryOpts := []retry.Option{
@@ -102,19 +153,114 @@ func (cmd *command) Run() error {
 		retry.DelayType(retry.FixedDelay),
 	}
 
-	installer, err := deployment.NewDeletion(installCfg, &overrides.Builder{}, callback, commonRetryOpts)
-	if err != nil {
-		return err
+	// record the duration of each component to report where the deletion spends its time
+	// (pre-requisites are deleted one after another, so the order in which they finished is sufficient)
+	recorder := timings.NewRecorder(cmd.opts.Concurrency, map[deployment.InstallationPhase][]string{
+		deployment.UninstallComponents: componentNames(compList.Components),
+	})
+
+	deletionCallback := recorder.Callback(callback)
+
+	// run the hooks of the components: failed hooks are reported as failed components
+	var hooksRunner *hooks.Runner
+	if cmd.opts.HooksFile != "" {
+		hooksFile, err := hooks.Load(cmd.opts.HooksFile)
+		if err != nil {
+			return err
+		}
+		hooksRunner = hooks.NewRunner(hooksFile, kube.KubeconfigPath(cmd.KubeconfigPath), cmd.K8s.Static())
+		hooksRunner.StepFactory = stepFactory
 	}
 
-	uninstallErr := installer.StartKymaUninstallation()
+	var uninstallErr error
+	if cmd.opts.selectsComponents() {
+		// the pre-delete hooks run right before their component is deleted
+		if hooksRunner != nil {
+			deletionCallback = hooksRunner.Callback(map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+				deployment.UninstallPreRequisites: compList.Prerequisites,
+				deployment.UninstallComponents:    compList.Components,
+			}, deletionCallback)
+		}
+		uninstallErr = cmd.deleteComponents(compList, plan, hooksRunner, deletionCallback)
+	} else {
+		uninstall := func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+			installer, err := deployment.NewDeletion(installCfg, &overrides.Builder{}, callback, commonRetryOpts)
+			if err != nil {
+				return err
+			}
+			return installer.StartKymaUninstallation()
+		}
+		if hooksRunner != nil {
+			uninstallErr = hooksRunner.Delete(compList, uninstall, deletionCallback)
+		} else {
+			uninstallErr = uninstall(compList, deletionCallback)
+		}
+	}
+	if uninstallErr == nil && hooksRunner != nil {
+		uninstallErr = hooksRunner.Err()
+	}
 
-	if uninstallErr == nil {
+	// the hostnames point to the remaining components if only some components were deleted
+	if uninstallErr == nil && !cmd.opts.selectsComponents() {
+		cmd.removeHostsEntries()
+	}
+	if uninstallErr == nil && !cmd.opts.jsonEvents() {
 		cmd.showSuccessMessage()
 	}
+	if err := cmd.reportTimings(recorder.Report()); err != nil {
+		if uninstallErr != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return uninstallErr
+		}
+		return err
+	}
 	return uninstallErr
 }
 
+//approveDeletion lists the resources of the plan and asks the user whether they should be deleted
+func (cmd *command) approveDeletion(plan *deletionPlan) bool {
+	if cmd.opts.CI {
+		return true
+	}
+	w := cmd.messageWriter()
+	fmt.Fprintf(w, "The following resources will be deleted from cluster '%s':\n", cmd.K8s.RestConfig().Host)
+	plan.print(w)
+	qDeleteStep := cmd.NewStep("Confirm the deletion")
+	defer qDeleteStep.Success()
+	return qDeleteStep.PromptYesNo("Do you want to delete these resources? ")
+}
+
+//messageWriter returns the writer for messages to the user (stdout is reserved for the JSON events)
+func (cmd *command) messageWriter() io.Writer {
+	if cmd.opts.jsonEvents() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+//reportTimings prints the timings of the deletion phases and components and writes them to the timings file (if set)
+func (cmd *command) reportTimings(report *timings.Report) error {
+	if len(report.Phases) == 0 {
+		return nil
+	}
+	if !cmd.opts.jsonEvents() {
+		report.Print(os.Stdout)
+	}
+	if cmd.opts.TimingsFile == "" {
+		return nil
+	}
+	return report.WriteTraceFile(cmd.opts.TimingsFile)
+}
+
+//componentNames returns the names of the components of the component list
+func componentNames(compDefs []installConfig.ComponentDefinition) []string {
+	var names []string
+	for _, compDef := range compDefs {
+		names = append(names, compDef.Name)
+	}
+	return names
+}
+
 func (cmd *command) kymaComponentList() (*installConfig.ComponentList, error) {
 	kymaCompStep := cmd.NewStep("Get Kyma components")
 	metaProv, err := helm.NewKymaMetadataProvider(installConfig.KubeconfigSource{
@@ -149,6 +295,26 @@ func (cmd *command) kymaComponentList() (*installConfig.ComponentList, error) {
 	return compList, nil
 }
 
+//removeHostsEntries removes the Kyma entries of the hosts file, which point to the deleted Kyma installation
+func (cmd *command) removeHostsEntries() {
+	hostsFile := hosts.NewFile()
+	if ip, _, err := hostsFile.Managed(); err != nil || ip == "" {
+		return
+	}
+	// create a simple step without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+		UseLogger:      cmd.UseLogger,
+	}
+	s := f.NewStep("Removing the Kyma entries of the hosts file")
+	if _, err := hostsFile.Remove(); err != nil {
+		// the deletion succeeded anyway: the entries can be removed later
+		s.Failuref("Could not remove the Kyma entries of the hosts file, run \"kyma alpha hosts remove\": %s", err)
+		return
+	}
+	s.Successf("Kyma entries removed from '%s'", hostsFile.Path)
+}
+
 func (cmd *command) showSuccessMessage() {
 	// TODO: show processing summary
 	fmt.Println("Kyma successfully removed.")
@@ -0,0 +1,310 @@
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-project/cli/internal/hooks"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	helmKube "helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	releaseNameAnnotation      = "meta.helm.sh/release-name"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+//namespaces which are never deleted, even if Kyma components were installed into them
+var protectedNamespaces = map[string]bool{
+	"":                true,
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+//deletionPlan lists the resources which a deletion removes from the cluster
+type deletionPlan struct {
+	//Releases are the Helm releases of the deleted components in the order of their deletion
+	Releases   []installConfig.ComponentDefinition
+	Namespaces []string
+	CRDs       []string
+}
+
+//print writes the resources of the plan
+func (p *deletionPlan) print(w io.Writer) {
+	fmt.Fprintf(w, "Helm releases (%d):\n", len(p.Releases))
+	for _, release := range p.Releases {
+		fmt.Fprintf(w, "  %s/%s\n", release.Namespace, release.Name)
+	}
+	fmt.Fprintf(w, "Namespaces (%d):\n", len(p.Namespaces))
+	for _, namespace := range p.Namespaces {
+		fmt.Fprintf(w, "  %s\n", namespace)
+	}
+	fmt.Fprintf(w, "CustomResourceDefinitions (%d):\n", len(p.CRDs))
+	for _, crd := range p.CRDs {
+		fmt.Fprintf(w, "  %s\n", crd)
+	}
+}
+
+//planDeletion determines the releases, namespaces, and CRDs which the deletion of the components removes
+func (cmd *command) planDeletion(installed, compList *installConfig.ComponentList) (*deletionPlan, error) {
+	plan := &deletionPlan{}
+	// components are deleted before the pre-requisites
+	plan.Releases = append(plan.Releases, compList.Components...)
+	for i := len(compList.Prerequisites) - 1; i >= 0; i-- {
+		plan.Releases = append(plan.Releases, compList.Prerequisites[i])
+	}
+	plan.Namespaces = orphanedNamespaces(plan.Releases, remainingComponents(installed, compList))
+	if cmd.opts.KeepCRDs {
+		return plan, nil
+	}
+
+	crds, err := cmd.K8s.Dynamic().Resource(crdGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the CustomResourceDefinitions of the cluster")
+	}
+	releases := make(map[string]bool)
+	for _, release := range plan.Releases {
+		releases[release.Namespace+"/"+release.Name] = true
+	}
+	for _, crd := range crds.Items {
+		annotations := crd.GetAnnotations()
+		if releases[annotations[releaseNamespaceAnnotation]+"/"+annotations[releaseNameAnnotation]] {
+			plan.CRDs = append(plan.CRDs, crd.GetName())
+		}
+	}
+	sort.Strings(plan.CRDs)
+	return plan, nil
+}
+
+//selectedComponents returns the installed components which are selected with the "component" or "components-file" flag.
+//It fails if a selected component is not installed, so that a typo does not go unnoticed.
+func (cmd *command) selectedComponents(installed *installConfig.ComponentList) (*installConfig.ComponentList, error) {
+	selectors := &installConfig.ComponentList{}
+	if cmd.opts.ComponentsFile != "" {
+		var err error
+		if selectors, err = installConfig.NewComponentList(cmd.opts.ComponentsFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, comp := range cmd.opts.Components {
+		// component can be provided in the following format: componentName@namespace
+		compDef := strings.Split(comp, "@")
+		namespace := ""
+		if len(compDef) > 1 {
+			namespace = compDef[1]
+		}
+		selectors.Components = append(selectors.Components, installConfig.ComponentDefinition{Name: compDef[0], Namespace: namespace})
+	}
+	return selectComponents(installed, append(selectors.Prerequisites, selectors.Components...))
+}
+
+//selectComponents returns the installed components which match the selectors. A selector without namespace matches the component in any namespace.
+func selectComponents(installed *installConfig.ComponentList, selectors []installConfig.ComponentDefinition) (*installConfig.ComponentList, error) {
+	matches := func(compDef installConfig.ComponentDefinition, selector installConfig.ComponentDefinition) bool {
+		return compDef.Name == selector.Name && (selector.Namespace == "" || compDef.Namespace == selector.Namespace)
+	}
+
+	selected := &installConfig.ComponentList{}
+	var missing []string
+	for _, selector := range selectors {
+		found := false
+		for _, compDef := range installed.Prerequisites {
+			if matches(compDef, selector) {
+				found = true
+				if !containsComponent(selected.Prerequisites, compDef) {
+					selected.Prerequisites = append(selected.Prerequisites, compDef)
+				}
+			}
+		}
+		for _, compDef := range installed.Components {
+			if matches(compDef, selector) {
+				found = true
+				if !containsComponent(selected.Components, compDef) {
+					selected.Components = append(selected.Components, compDef)
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, selector.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Components not installed: %s", strings.Join(missing, ", "))
+	}
+	return selected, nil
+}
+
+//remainingComponents returns the installed components which are not deleted
+func remainingComponents(installed, deleted *installConfig.ComponentList) []installConfig.ComponentDefinition {
+	var remaining []installConfig.ComponentDefinition
+	for _, compDef := range append(append([]installConfig.ComponentDefinition{}, installed.Prerequisites...), installed.Components...) {
+		if !containsComponent(deleted.Prerequisites, compDef) && !containsComponent(deleted.Components, compDef) {
+			remaining = append(remaining, compDef)
+		}
+	}
+	return remaining
+}
+
+//orphanedNamespaces returns the namespaces of the deleted components which no remaining component uses, sorted by name
+func orphanedNamespaces(deleted, remaining []installConfig.ComponentDefinition) []string {
+	used := make(map[string]bool)
+	for _, compDef := range remaining {
+		used[compDef.Namespace] = true
+	}
+	var namespaces []string
+	for _, compDef := range deleted {
+		if used[compDef.Namespace] || protectedNamespaces[compDef.Namespace] {
+			continue
+		}
+		used[compDef.Namespace] = true
+		namespaces = append(namespaces, compDef.Namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func containsComponent(compDefs []installConfig.ComponentDefinition, compDef installConfig.ComponentDefinition) bool {
+	for _, c := range compDefs {
+		if c.Name == compDef.Name && c.Namespace == compDef.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+//deleteComponents uninstalls the Helm releases of the plan and deletes the namespaces of the plan afterwards.
+//The components are uninstalled in parallel by the number of workers set with the "concurrency" flag,
+//the pre-requisites one after another in the reverse order of their deployment.
+//Unlike the deletion of all Kyma components, it does not touch any other resources of the cluster.
+//The pre-delete hooks of a component (if any) run right before it is uninstalled; if they fail, the component is kept.
+//The progress is reported as process updates of the deletion phases.
+func (cmd *command) deleteComponents(compList *installConfig.ComponentList, plan *deletionPlan, hooksRunner *hooks.Runner, callback func(deployment.ProcessUpdate)) error {
+	phases := []struct {
+		phase    deployment.InstallationPhase
+		compDefs []installConfig.ComponentDefinition
+		workers  int
+	}{
+		{phase: deployment.UninstallComponents, compDefs: compList.Components, workers: cmd.opts.Concurrency},
+		{phase: deployment.UninstallPreRequisites, compDefs: plan.Releases[len(compList.Components):], workers: 1},
+	}
+	for _, p := range phases {
+		if len(p.compDefs) == 0 {
+			continue
+		}
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: p.phase})
+		phase := p.phase
+		uninstall := func(compDef installConfig.ComponentDefinition) error {
+			if hooksRunner != nil {
+				if err := hooksRunner.Before(phase, compDef); err != nil {
+					return err
+				}
+			}
+			return cmd.uninstallRelease(compDef)
+		}
+		failed := uninstallAll(p.compDefs, p.workers, uninstall, func(compDef installConfig.ComponentDefinition, err error) {
+			status := components.StatusUninstalled
+			if err != nil {
+				status = components.StatusError
+			}
+			callback(deployment.ProcessUpdate{
+				Event: deployment.ProcessRunning,
+				Phase: phase,
+				Component: components.KymaComponent{
+					Name:      compDef.Name,
+					Namespace: compDef.Namespace,
+					Status:    status,
+					Error:     err,
+				},
+			})
+		})
+		if len(failed) > 0 {
+			err := fmt.Errorf("Could not delete components: %s", strings.Join(failed, ", "))
+			callback(deployment.ProcessUpdate{Event: deployment.ProcessExecutionFailure, Phase: p.phase, Error: err})
+			return err
+		}
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessFinished, Phase: p.phase})
+	}
+
+	for _, namespace := range plan.Namespaces {
+		err := cmd.K8s.Static().CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Could not delete namespace '%s'", namespace)
+		}
+	}
+	return nil
+}
+
+//uninstallAll uninstalls the components with the given number of workers and reports each finished component.
+//The reports are not called concurrently. It returns the names of the components which could not be uninstalled, in their order.
+func uninstallAll(compDefs []installConfig.ComponentDefinition, workers int, uninstall func(installConfig.ComponentDefinition) error, report func(installConfig.ComponentDefinition, error)) []string {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(compDefs))
+	queue := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				err := uninstall(compDefs[i])
+				mu.Lock()
+				errs[i] = err
+				report(compDefs[i], err)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range compDefs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, compDefs[i].Name)
+		}
+	}
+	return failed
+}
+
+//uninstallRelease uninstalls the Helm release of a component (a release which does not exist anymore is ignored)
+func (cmd *command) uninstallRelease(compDef installConfig.ComponentDefinition) error {
+	cfg := &action.Configuration{}
+	restGetter := helmKube.GetConfig(kube.KubeconfigPath(cmd.KubeconfigPath), "", compDef.Namespace)
+	if err := cfg.Init(restGetter, compDef.Namespace, "secrets", func(string, ...interface{}) {}); err != nil {
+		return errors.Wrap(err, "Could not initialize the Helm client")
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.Timeout = cmd.opts.TimeoutComponent
+	_, err := uninstall.Run(compDef.Name)
+	if errors.Cause(err) == driver.ErrReleaseNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Could not uninstall the release of component '%s'", compDef.Name)
+	}
+	return nil
+}
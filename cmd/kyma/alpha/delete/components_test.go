@@ -0,0 +1,106 @@
+package uninstall
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectComponents(t *testing.T) {
+	installed := &installConfig.ComponentList{
+		Prerequisites: []installConfig.ComponentDefinition{
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+			{Name: "istio", Namespace: "istio-system"},
+		},
+		Components: []installConfig.ComponentDefinition{
+			{Name: "serverless", Namespace: "kyma-system"},
+			{Name: "eventing", Namespace: "kyma-system"},
+			{Name: "application-connector", Namespace: "kyma-integration"},
+		},
+	}
+
+	t.Run("Select by name and namespace", func(t *testing.T) {
+		selected, err := selectComponents(installed, []installConfig.ComponentDefinition{
+			{Name: "application-connector"},
+			{Name: "istio", Namespace: "istio-system"},
+			{Name: "application-connector", Namespace: "kyma-integration"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &installConfig.ComponentList{
+			Prerequisites: []installConfig.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}},
+			Components:    []installConfig.ComponentDefinition{{Name: "application-connector", Namespace: "kyma-integration"}},
+		}, selected)
+	})
+
+	t.Run("Component not installed", func(t *testing.T) {
+		_, err := selectComponents(installed, []installConfig.ComponentDefinition{
+			{Name: "serverles"},
+			{Name: "eventing", Namespace: "kyma-integration"},
+		})
+		require.EqualError(t, err, "Components not installed: serverles, eventing")
+	})
+
+	t.Run("Orphaned namespaces", func(t *testing.T) {
+		selected, err := selectComponents(installed, []installConfig.ComponentDefinition{{Name: "application-connector"}, {Name: "serverless"}})
+		require.NoError(t, err)
+		remaining := remainingComponents(installed, selected)
+		require.Len(t, remaining, 3)
+		require.Equal(t, []string{"kyma-integration"}, orphanedNamespaces(selected.Components, remaining))
+		require.Equal(t, []string{"istio-system", "kyma-integration", "kyma-system"},
+			orphanedNamespaces(append(installed.Prerequisites, installed.Components...), nil))
+	})
+}
+
+func TestPrintPlan(t *testing.T) {
+	plan := &deletionPlan{
+		Releases:   []installConfig.ComponentDefinition{{Name: "serverless", Namespace: "kyma-system"}},
+		Namespaces: []string{},
+		CRDs:       []string{"functions.serverless.kyma-project.io"},
+	}
+	var buf bytes.Buffer
+	plan.print(&buf)
+	require.Equal(t, `Helm releases (1):
+  kyma-system/serverless
+Namespaces (0):
+CustomResourceDefinitions (1):
+  functions.serverless.kyma-project.io
+`, buf.String())
+}
+
+func TestUninstallAll(t *testing.T) {
+	compDefs := []installConfig.ComponentDefinition{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	uninstall := func(compDef installConfig.ComponentDefinition) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if compDef.Name == "b" || compDef.Name == "d" {
+			return fmt.Errorf("Could not uninstall %s", compDef.Name)
+		}
+		return nil
+	}
+	reported := make(map[string]error)
+	failed := uninstallAll(compDefs, 2, uninstall, func(compDef installConfig.ComponentDefinition, err error) {
+		reported[compDef.Name] = err
+	})
+
+	require.Equal(t, []string{"b", "d"}, failed)
+	require.Equal(t, 2, maxRunning)
+	require.Len(t, reported, 5)
+	require.EqualError(t, reported["b"], "Could not uninstall b")
+	require.NoError(t, reported["a"])
+}
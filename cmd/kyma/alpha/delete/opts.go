@@ -9,6 +9,7 @@ import (
 
 const (
 	quitTimeoutFactor = 1.25
+	jsonEventsOutput  = "json-events"
 )
 
 //Options defines available options for the command
@@ -19,6 +20,12 @@ type Options struct {
 	TimeoutComponent time.Duration
 	Concurrency      int
 	KeepCRDs         bool
+	Output           string
+	TimingsFile      string
+	HooksFile        string
+	Components       []string
+	ComponentsFile   string
+	DryRun           bool
 }
 
 //NewOptions creates options with default values
@@ -31,10 +38,33 @@ func (o *Options) QuitTimeout() time.Duration {
 	return time.Duration((o.Timeout.Seconds() * quitTimeoutFactor)) * time.Second
 }
 
+//jsonEvents returns true if the deletion events are written as JSON objects instead of rendering the UI
+func (o *Options) jsonEvents() bool {
+	return o.Output == jsonEventsOutput
+}
+
+//selectsComponents returns true if only selected components are deleted instead of all Kyma components
+func (o *Options) selectsComponents() bool {
+	return len(o.Components) > 0 || o.ComponentsFile != ""
+}
+
 // validateFlags applies a sanity check on provided options
 func (o *Options) validateFlags() error {
 	if o.Timeout < o.TimeoutComponent {
 		return fmt.Errorf("Timeout (%v) cannot be smaller than component timeout (%v)", o.Timeout, o.TimeoutComponent)
 	}
+	if o.Output != "" && !o.jsonEvents() {
+		return fmt.Errorf("Output format '%s' is not supported. Supported output formats are: %s", o.Output, jsonEventsOutput)
+	}
+	// the confirmation prompt would corrupt the events on stdout
+	if o.jsonEvents() && !o.CI && !o.DryRun {
+		return fmt.Errorf(`The "output" flag requires the "ci" flag, because the deletion cannot be confirmed interactively`)
+	}
+	if o.ComponentsFile != "" && len(o.Components) > 0 {
+		return fmt.Errorf(`Provide either "components-file" or "component" flag`)
+	}
+	if o.KeepCRDs && o.selectsComponents() {
+		return fmt.Errorf(`The "keep-crds" flag cannot be combined with selected components: Helm deletes the CRDs of their releases`)
+	}
 	return nil
 }
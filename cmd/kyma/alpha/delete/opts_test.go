@@ -0,0 +1,24 @@
+package uninstall
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("JSON events require CI mode", func(t *testing.T) {
+		opts := &Options{Options: &cli.Options{}, Output: jsonEventsOutput}
+		err := opts.validateFlags()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `requires the "ci" flag`)
+
+		opts.CI = true
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("JSON events of a dry run", func(t *testing.T) {
+		opts := &Options{Options: &cli.Options{}, Output: jsonEventsOutput, DryRun: true}
+		require.NoError(t, opts.validateFlags())
+	})
+}
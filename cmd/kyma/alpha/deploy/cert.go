@@ -0,0 +1,35 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/certs"
+)
+
+//generateCert creates a self-signed wildcard certificate for the custom domain, or reuses the certificate which was created before,
+//and deploys Kyma with this certificate. The CA of the certificate is imported instead of the certificate of the cluster.
+func (cmd *command) generateCert() error {
+	if cmd.opts.Domain == "" {
+		return fmt.Errorf(`A certificate can only be generated for a custom domain ("domain" flag)`)
+	}
+	dir, err := certs.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	certStep := cmd.NewStep(fmt.Sprintf("Generating certificate for domain '%s'", cmd.opts.Domain))
+	cert, created, err := certs.NewStore(dir).Ensure(cmd.opts.Domain)
+	if err != nil {
+		certStep.Failure()
+		return err
+	}
+	if created {
+		certStep.Successf("Created certificate for domain '%s' (valid until %s)", cert.Domain, cert.NotAfter.Format("2006-01-02"))
+	} else {
+		certStep.Successf("Reusing certificate for domain '%s' (valid until %s)", cert.Domain, cert.NotAfter.Format("2006-01-02"))
+	}
+
+	cmd.opts.TLSCrtFile, cmd.opts.TLSKeyFile = cert.CrtFile, cert.KeyFile
+	cmd.caFile = cert.CAFile
+	return nil
+}
@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+//cluster is a cluster Kyma is deployed to if several clusters are deployed at once.
+//Its settings take precedence over the settings of the command.
+type cluster struct {
+	Context     string   `yaml:"context,omitempty"`
+	Kubeconfig  string   `yaml:"kubeconfig,omitempty"`
+	Domain      string   `yaml:"domain,omitempty"`
+	TLSCrtFile  string   `yaml:"tlsCrt,omitempty"`
+	TLSKeyFile  string   `yaml:"tlsKey,omitempty"`
+	Profile     string   `yaml:"profile,omitempty"`
+	ValuesFiles []string `yaml:"valuesFiles,omitempty"`
+	Values      []string `yaml:"values,omitempty"`
+}
+
+//clustersFile is the file which defines the clusters of a multi-cluster deployment
+type clustersFile struct {
+	Clusters []cluster `yaml:"clusters"`
+}
+
+//name returns the name which identifies the cluster in the output
+func (c cluster) name() string {
+	if c.Context != "" {
+		return c.Context
+	}
+	return c.Kubeconfig
+}
+
+//clusters returns the clusters Kyma is deployed to, either defined by the kubeconfig contexts or by the clusters file
+func (o *Options) clusters() ([]cluster, error) {
+	if o.ClustersFile == "" {
+		var result []cluster
+		for _, context := range o.KubeContexts {
+			result = append(result, cluster{Context: context})
+		}
+		return checkClusters(result)
+	}
+
+	data, err := ioutil.ReadFile(o.ClustersFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read clusters file '%s'", o.ClustersFile)
+	}
+	file := &clustersFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse clusters file '%s'", o.ClustersFile)
+	}
+
+	// file paths in the clusters file are relative to the clusters file
+	dir := filepath.Dir(o.ClustersFile)
+	for i := range file.Clusters {
+		c := &file.Clusters[i]
+		c.Kubeconfig = relativeTo(dir, c.Kubeconfig)
+		c.TLSCrtFile = relativeTo(dir, c.TLSCrtFile)
+		c.TLSKeyFile = relativeTo(dir, c.TLSKeyFile)
+		for j := range c.ValuesFiles {
+			c.ValuesFiles[j] = relativeTo(dir, c.ValuesFiles[j])
+		}
+		if (c.TLSCrtFile == "") != (c.TLSKeyFile == "") {
+			return nil, fmt.Errorf("Provide both TLS certificate and key of cluster '%s'", c.name())
+		}
+		if c.TLSCrtFile != "" {
+			if err := o.pathExists(c.TLSCrtFile, "TLS certificate"); err != nil {
+				return nil, err
+			}
+			if err := o.pathExists(c.TLSKeyFile, "TLS key"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return checkClusters(file.Clusters)
+}
+
+//checkClusters verifies that each cluster is defined and deployed only once
+func checkClusters(clusters []cluster) ([]cluster, error) {
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("No clusters defined")
+	}
+	names := make(map[string]bool)
+	for _, c := range clusters {
+		if c.name() == "" {
+			return nil, fmt.Errorf("Each cluster requires a kubeconfig context or a kubeconfig file")
+		}
+		if names[c.name()] {
+			return nil, fmt.Errorf("Cluster '%s' is defined more than once", c.name())
+		}
+		names[c.name()] = true
+	}
+	return clusters, nil
+}
+
+//relativeTo resolves a local file path relative to the directory (absolute paths and URLs are not changed)
+func relativeTo(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) || strings.Contains(path, "://") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusters(t *testing.T) {
+	t.Run("Clusters of kubeconfig contexts", func(t *testing.T) {
+		opts := &Options{KubeContexts: []string{"staging-1", "staging-2"}}
+		clusters, err := opts.clusters()
+		require.NoError(t, err)
+		require.Equal(t, []cluster{{Context: "staging-1"}, {Context: "staging-2"}}, clusters)
+
+		opts.KubeContexts = []string{"staging-1", "staging-1"}
+		_, err = opts.clusters()
+		require.EqualError(t, err, "Cluster 'staging-1' is defined more than once")
+	})
+
+	t.Run("Clusters file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "clusters-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "clusters.yaml")
+		require.NoError(t, ioutil.WriteFile(file, []byte(`clusters:
+- context: staging-1
+  domain: staging-1.example.com
+  valuesFiles: [staging-1.yaml, https://example.com/values.yaml]
+  values: [a.b=c]
+- kubeconfig: /kube/staging-2
+`), 0600))
+
+		opts := &Options{ClustersFile: file}
+		clusters, err := opts.clusters()
+		require.NoError(t, err)
+		require.Equal(t, []cluster{
+			{
+				Context:     "staging-1",
+				Domain:      "staging-1.example.com",
+				ValuesFiles: []string{filepath.Join(dir, "staging-1.yaml"), "https://example.com/values.yaml"},
+				Values:      []string{"a.b=c"},
+			},
+			{Kubeconfig: "/kube/staging-2"},
+		}, clusters)
+		require.Equal(t, "/kube/staging-2", clusters[1].name())
+
+		require.NoError(t, ioutil.WriteFile(file, []byte("clusters:\n- context: staging-1\n  tlsCrt: crt.pem\n"), 0600))
+		_, err = opts.clusters()
+		require.EqualError(t, err, "Provide both TLS certificate and key of cluster 'staging-1'")
+
+		require.NoError(t, ioutil.WriteFile(file, []byte("clusters:\n- domain: staging-1.example.com\n"), 0600))
+		_, err = opts.clusters()
+		require.EqualError(t, err, "Each cluster requires a kubeconfig context or a kubeconfig file")
+	})
+}
+
+func TestPrintClusterResults(t *testing.T) {
+	results := []clusterResult{
+		{
+			cluster:  cluster{Context: "staging-1"},
+			summary:  &nice.Summary{Version: "2.0.0", URL: "staging-1.example.com", Console: "https://console.staging-1.example.com"},
+			duration: 5 * time.Minute,
+		},
+		{
+			cluster:  cluster{Context: "staging-2"},
+			duration: time.Minute,
+			err:      fmt.Errorf("At least one pre-flight check failed"),
+		},
+	}
+
+	var buf bytes.Buffer
+	err := printClusterResults(&buf, results)
+	require.EqualError(t, err, "Kyma deployment failed on 1 of 2 clusters: staging-2")
+	require.Regexp(t, `staging-1\s+Deployed\s+2\.0\.0\s+staging-1\.example\.com\s+https://console\.staging-1\.example\.com\s+5m0s`, buf.String())
+	require.Regexp(t, `staging-2\s+Failed\s+1m0s`, buf.String())
+	require.Contains(t, buf.String(), "[staging-2] At least one pre-flight check failed\n")
+
+	buf.Reset()
+	require.NoError(t, printClusterResults(&buf, results[:1]))
+}
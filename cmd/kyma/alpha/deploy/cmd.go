@@ -1,8 +1,8 @@
 package deploy
 
 import (
-	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -13,22 +13,23 @@ import (
 
 	"github.com/pkg/errors"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/kyma-project/cli/cmd/kyma/version"
+	"github.com/kyma-project/cli/internal/bundle"
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/hooks"
 	"github.com/kyma-project/cli/internal/hosts"
 	"github.com/kyma-project/cli/internal/kube"
-	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/profiles"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/kyma-project/cli/internal/summary"
 	"github.com/kyma-project/cli/internal/trust"
 	"github.com/kyma-project/cli/pkg/asyncui"
 	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/kyma-project/cli/pkg/jsonevents"
 	"github.com/kyma-project/cli/pkg/step"
-	"github.com/magiconair/properties"
+	"github.com/kyma-project/cli/pkg/timings"
 	"github.com/spf13/cobra"
 
-	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
-
 	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
@@ -40,10 +41,26 @@ type command struct {
 	opts *Options
 	cli.Command
 	duration time.Duration
+	// state of the failed deployment which gets resumed
+	state *deploymentState
+	// commit the remote Kyma sources were resolved to (empty for local sources)
+	commit string
+	// flagChanged returns true if the flag was set explicitly on the command line
+	flagChanged func(name string) bool
+	// extracted bundle the deployment uses
+	bundle *bundle.Bundle
+	// timings of the deployment phases and components
+	timings *timings.Recorder
+	// cluster the command deploys to if Kyma is deployed to several clusters at once
+	cluster *cluster
+	// components which are deployed instead of the components of the options (e.g. the changed components in watch mode)
+	components *installConfig.ComponentList
+	// CA of the generated certificate, which is imported instead of the certificate of the cluster
+	caFile string
 }
 
 const (
-	kymaURL            = "https://github.com/kyma-project/kyma"
+	kymaURL            = sources.KymaRepositoryURL
 	kyma2OverridesPath = "/installation/resources/values.yaml"
 )
 
@@ -62,11 +79,10 @@ func NewCmd(o *Options) *cobra.Command {
 
 Usage Examples:
   Deploy Kyma using your own domain name
-    You must provide the certificate and key as files.
-    If you don't have a certificate yet, you can create a self-signed certificate and key:
-		openssl req -x509 -newkey rsa:4096 -keyout key.pem -out crt.pem -days 365
-    Then, pass the certificate files to the deploy command:
-		kyma alpha deploy --domain {DOMAIN} --tls-cert crt.pem --tls-key key.pem
+    Pass the certificate and key files to the deploy command:
+		kyma alpha deploy --domain {DOMAIN} --tls-crt crt.pem --tls-key key.pem
+    If you don't have a certificate yet, generate a self-signed wildcard certificate for the domain:
+		kyma alpha deploy --domain {DOMAIN} --generate-cert
 
   Deploy Kyma from specific source:
     - Deploy from a specific version, such as 1.19.1:
@@ -88,6 +104,47 @@ Usage Examples:
     Then run:
 		kyma alpha deploy --components {COMPONENTS_FILE_PATH}
 
+  Render the Kubernetes manifests without deploying them:
+    Kyma components are rendered with all configuration values applied and stored as one YAML file per component:
+		kyma alpha deploy --render-to {DIRECTORY}
+
+  Preview the changes of a deployment:
+    Kyma components are rendered and compared with the installed Kyma components. The differences are printed per component:
+		kyma alpha deploy --diff --value ory.hydra.deployment.resources.limits.cpu=153m
+
+  Resume a failed deployment:
+    Only the components that failed or were not deployed are deployed again, using the same source and configuration values:
+		kyma alpha deploy --resume
+
+  Re-deploy changed components while developing local charts:
+    After the deployment, the charts in the local Kyma sources are watched. Every change re-deploys only the affected components:
+		kyma alpha deploy --source=local --workspace {KYMA_SOURCES} --component eventing@kyma-system --watch
+
+  Deploy Kyma to several clusters in parallel:
+    The clusters are deployed with the same source, components, and configuration values. The results of all clusters are shown at the end:
+		kyma alpha deploy --kube-context staging-1,staging-2,staging-3
+    To use a different domain or configuration values per cluster, define the clusters in a clusters file:
+		kyma alpha deploy --clusters-file clusters.yaml
+
+  Deploy Kyma without internet access:
+    Create a bundle of the Kyma sources, components file, and values files on a machine with internet access:
+		kyma alpha bundle create --source 2.0.0 -o kyma-2.0.0.tgz
+    Then, deploy Kyma from the bundle:
+		kyma alpha deploy --bundle kyma-2.0.0.tgz
+
+  Pull all container images from a private registry:
+    Copy the images to your registry first, then deploy Kyma with all image references rewritten to the registry:
+		kyma alpha images mirror --source 2.0.0 --target-registry registry.corp.local/kyma
+		kyma alpha deploy --source 2.0.0 --image-registry registry.corp.local/kyma
+
+  Capture the deployment summary in a pipeline:
+    The summary is written as JSON or YAML document to stdout, all other messages are written to stderr. To include the admin password, add the --show-secrets flag:
+		kyma alpha deploy --ci --output json > summary.json
+
+  Track the deployment progress in a pipeline:
+    Each deployment event is written as one JSON object per line to stdout, all other messages are written to stderr:
+		kyma alpha deploy --ci --output json-events
+
   Change Kyma settings:
     To change your Kyma configuration, use the alpha deploy command and deploy the same Kyma version that you're currently using,
     just with different settings.
@@ -96,6 +153,11 @@ Usage Examples:
     - Using specific values instead of file:
 		kyma deploy --value ory.hydra.deployment.resources.limits.cpu=153m \
 		--value ory.hydra.deployment.resources.requests.cpu=53m
+    - Using typed values, lists, and values which are read from files:
+		kyma alpha deploy --value istio.global.proxy.holdApplicationUntilProxyStarts=true \
+		--value-string serverless.dockerRegistry.password=0123 \
+		--value-file ory.hydra.config.secrets.system=secret.txt \
+		--value-json 'monitoring.alertmanager.alertmanagerSpec.tolerations=[{"key":"dedicated","operator":"Exists"}]'
 
 Debugging:
   The alpha commands support troubleshooting in several ways, for example:
@@ -108,26 +170,38 @@ Debugging:
     With atomic deployment active, any component that hasn't been installed successfully is rolled back,
     which may make it hard to find out what went wrong. By disabling the flag, the failed components are not rolled back.
 	`,
-		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			// the verification is on by default in CI mode, where nobody watches the deployment
+			if !cobraCmd.Flags().Changed("verify") {
+				o.Verify = o.CI
+			}
+			cmd.flagChanged = cobraCmd.Flags().Changed
+			return cmd.Run()
+		},
 		Aliases: []string{"d"},
 	}
 
 	// default value for workspace flag is set in validateFlags()
 	// to avoid having the actual home directory shown in the auto-generated docs
-	cobraCmd.Flags().StringVarP(&o.WorkspacePath, "workspace", "w", "", `Path to download Kyma sources (default "$HOME/.kyma/sources" or ".kyma-sources")`)
+	cobraCmd.Flags().StringVarP(&o.WorkspacePath, "workspace", "w", "", `Path to download Kyma sources. If not set, the sources are kept in the local source cache "$HOME/.kyma/cache/sources" and reused by later deployments`)
 	cobraCmd.Flags().BoolVarP(&o.Atomic, "atomic", "a", false, "Set --atomic=true to use atomic deployment, which rolls back any component that could not be installed successfully.")
 	// default value for components-file flag is set in validateFlags()
 	// to avoid having the actual home directory shown in the auto-generated docs
-	cobraCmd.Flags().StringVarP(&o.ComponentsFile, "components-file", "c", "", `Path to the components file (default "$HOME/.kyma/sources/installation/resources/components.yaml" or ".kyma-sources/installation/resources/components.yaml")`)
+	cobraCmd.Flags().StringVarP(&o.ComponentsFile, "components-file", "c", "", `Path to the components file (default "installation/resources/components.yaml" of the Kyma sources in the workspace or in the local source cache)`)
 	cobraCmd.Flags().StringSliceVarP(&o.Components, "component", "", []string{}, "Provide one or more components to deploy (e.g. --component componentName@namespace)")
 	cobraCmd.Flags().StringSliceVarP(&o.OverridesFiles, "values-file", "f", []string{}, "Path(s) to one or more JSON or YAML files with configuration values")
-	cobraCmd.Flags().StringSliceVarP(&o.Overrides, "value", "", []string{}, "Set configuration values. Can specify one or more values, also as a comma-separated list (e.g. --value component.a='1' --value component.b='2' or --value component.a='1',component.b='2').")
+	cobraCmd.Flags().StringArrayVarP(&o.Overrides, "value", "", []string{}, `Set configuration values like Helm's --set flag. Can specify one or more values, also as a comma-separated list (e.g. --value component.a='1' --value component.b='2' or --value component.a='1',component.b='2').
+Booleans, integers, and null are typed (e.g. --value component.enabled=false). Lists are set with curly braces or indexes (e.g. --value component.hosts={a,b} or --value component.ports[0].name=http).`)
+	cobraCmd.Flags().StringArrayVarP(&o.StringOverrides, "value-string", "", []string{}, "Set configuration values which are always strings (e.g. --value-string component.version=1.10)")
+	cobraCmd.Flags().StringArrayVarP(&o.FileOverrides, "value-file", "", []string{}, "Set configuration values to the content of files (e.g. --value-file component.config=config.txt)")
+	cobraCmd.Flags().StringArrayVarP(&o.JSONOverrides, "value-json", "", []string{}, `Set configuration values to JSON literals (e.g. --value-json 'component.resources={"limits":{"cpu":"100m"}}')`)
 	cobraCmd.Flags().DurationVarP(&o.Timeout, "timeout", "", 20*time.Minute, "Maximum time for the deployment")
 	cobraCmd.Flags().DurationVarP(&o.TimeoutComponent, "timeout-component", "", 6*time.Minute, "Maximum time to deploy the component")
 	cobraCmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Number of parallel processes")
 	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Custom domain used for installation")
 	cobraCmd.Flags().StringVarP(&o.TLSCrtFile, "tls-crt", "", "", "TLS certificate file for the domain used for installation")
 	cobraCmd.Flags().StringVarP(&o.TLSKeyFile, "tls-key", "", "", "TLS key file for the domain used for installation")
+	cobraCmd.Flags().BoolVar(&o.GenerateCert, "generate-cert", false, `Generates a self-signed wildcard certificate for the custom domain, signed by a local CA, and deploys Kyma with it. The certificate is stored in "$HOME/.kyma/certs/{DOMAIN}" and reused by later deployments until it expires. If the Kyma certificate is installed locally, the CA is imported`)
 	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", defaultSource, `Installation source:
 	- Deploy a specific release, for example: "kyma alpha deploy --source=1.17.1"
 	- Deploy a specific branch of the Kyma repository on kyma-project.org: "kyma alpha deploy --source=<my-branch-name>"
@@ -136,8 +210,41 @@ Debugging:
 	- Deploy the local sources: "kyma alpha deploy --source=local"`)
 	setSource(cobraCmd.Flags().Changed("source"), &o.Source)
 	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "p", "",
-		fmt.Sprintf("Kyma deployment profile. If not specified, Kyma uses its default configuration. The supported profiles are: \"%s\".", strings.Join(kymaProfiles, "\", \"")))
+		fmt.Sprintf(`Kyma deployment profile. If not specified, Kyma uses its default configuration. The supported profiles are: "%s", "%s", and the user-defined profiles.
+	- "%s" selects "%s" or "%s" based on the allocatable resources of the cluster nodes.
+	- A user-defined profile is a values file stored as "$HOME/.kyma/profiles/{PROFILE}.yaml", which is applied on top of the default configuration.`,
+			profiles.Auto, strings.Join(profiles.BuiltIn, `", "`), profiles.Auto, profiles.Production, profiles.Evaluation))
 	cobraCmd.Flags().BoolVarP(&o.ReuseHelmValues, "reuse-values", "r", true, "Set --reuse-values=false to prevent the reusage during component upgrade")
+	cobraCmd.Flags().BoolVar(&o.Validate, "validate", true, "Set --validate=false to skip the validation of the configuration values against the default values and the values schemas of the component charts")
+	cobraCmd.Flags().StringVar(&o.RenderTo, "render-to", "", "Path to a directory where the rendered Kubernetes manifests of all components are stored. If set, Kyma is not deployed and the cluster is not accessed.")
+	cobraCmd.Flags().BoolVar(&o.Diff, "diff", false, "Compares the rendered components with the installed Kyma components and prints the differences. If set, Kyma is not deployed.")
+	cobraCmd.Flags().StringVar(&o.Bundle, "bundle", "", `Path to a bundle created with "kyma alpha bundle create". Kyma is deployed with the source, components file, and values files of the bundle, without downloading anything.`)
+	cobraCmd.Flags().StringVar(&o.ImageRegistry, "image-registry", "", `Registry all container images are pulled from, for example, "registry.corp.local/kyma". The images must be copied to this registry with "kyma alpha images mirror", or they are pushed from a bundle that includes them.`)
+	cobraCmd.Flags().StringVarP(&o.Output, "output", "o", "", `Machine-readable output written to stdout instead of the progress steps and the summary. One of:
+	- "json" or "yaml": Writes the deployment summary as JSON or YAML document.
+	- "json-events": Writes one JSON object per deployment event.`)
+	cobraCmd.Flags().BoolVar(&o.ShowSecrets, "show-secrets", false, `Includes the admin password in the deployment summary written with "--output json" or "--output yaml"`)
+	cobraCmd.Flags().BoolVar(&o.SkipChecks, "skip-checks", false, `Skips the pre-flight checks of the cluster which "kyma alpha check" runs`)
+	cobraCmd.Flags().BoolVar(&o.Verify, "verify", false, `Waits after the deployment until the workloads of all deployed components are ready, their CRDs are established, and their webhooks respond. Enabled by default if "--ci" is set`)
+	cobraCmd.Flags().DurationVar(&o.VerifyTimeout, "verify-timeout", 10*time.Minute, "Maximum time to wait until all deployed components are healthy")
+	cobraCmd.Flags().StringVar(&o.TimingsFile, "timings-file", "", "Path to a file where the start and end time of all deployment phases and components are written in the Chrome trace event format (viewable in chrome://tracing)")
+	cobraCmd.Flags().StringSliceVar(&o.KubeContexts, "kube-context", []string{}, "Deploys Kyma in parallel to all clusters of the given kubeconfig contexts (e.g. --kube-context staging-1,staging-2)")
+	cobraCmd.Flags().StringVar(&o.ClustersFile, "clusters-file", "", `Path to a YAML file with the clusters Kyma is deployed to in parallel. Each cluster can define its own kubeconfig, domain, TLS certificate, profile, and configuration values:
+	clusters:
+	- context: staging-1
+	  domain: staging-1.example.com
+	  valuesFiles: [staging-1.yaml]
+	  values: [ory.hydra.deployment.resources.limits.cpu=153m]`)
+	cobraCmd.Flags().StringVar(&o.HooksFile, "hooks-file", "", `Path to a YAML file with hooks which run before ("pre-deploy") or after ("post-deploy") a component is deployed. A hook is either a shell command or the manifest of a Kubernetes Job:
+	hooks:
+	- component: istio
+	  when: post-deploy
+	  command: ./patch-secrets.sh
+	- component: my-app
+	  when: pre-deploy
+	  job: seed-data.yaml`)
+	cobraCmd.Flags().BoolVar(&o.Resume, "resume", false, "Resumes the last failed deployment. Only the failed and not yet deployed components are deployed, using the source commit and configuration values of the failed deployment. Flags set explicitly take precedence over the settings of the failed deployment.")
+	cobraCmd.Flags().BoolVar(&o.Watch, "watch", false, `Keeps watching the local charts in the "resources" directory and the components file after the deployment. Whenever a chart or the components file changes, only the affected components are re-deployed, reusing the configuration values and skipping the pre-flight checks. Requires "--source=local"`)
 	return cobraCmd
 }
 
@@ -157,6 +264,12 @@ func (cmd *command) Run() error {
 	var err error
 
 	start := time.Now()
+	// restore the settings of the failed deployment
+	if cmd.opts.Resume {
+		if err = cmd.loadState(); err != nil {
+			return err
+		}
+	}
 	// verify input parameters
 	if err = cmd.opts.validateFlags(); err != nil {
 		return err
@@ -167,60 +280,112 @@ func (cmd *command) Run() error {
 	if cmd.opts.Verbose {
 		cmd.Factory.UseLogger = true
 	}
+	if cmd.opts.Output != "" {
+		// keep stdout free for the machine-readable output: steps are logged to stderr
+		cmd.Factory.NonInteractive = true
+		cmd.Factory.UseLogger = true
+	}
+
+	// use the sources, components file and values files of the bundle
+	if cmd.opts.Bundle != "" {
+		bundleDir, err := cmd.extractBundle()
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(bundleDir)
 
-	// initialize Kubernetes client
-	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
-		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+		// the images are pushed only once, also if several clusters are deployed
+		if cmd.bundle.ImagesIncluded && cmd.opts.ImageRegistry != "" && !cmd.opts.renderOnly() && !cmd.opts.Diff {
+			if err := cmd.pushBundleImages(); err != nil {
+				return err
+			}
+		}
 	}
 
-	// only download if not from local sources
-	if cmd.opts.Source != localSource {
-		if err := cmd.isCompatibleVersion(); err != nil {
+	// deploy to several clusters in parallel
+	if cmd.opts.multiCluster() {
+		return cmd.deployClusters()
+	}
+
+	if cmd.opts.GenerateCert {
+		if err := cmd.generateCert(); err != nil {
 			return err
 		}
+	}
+
+	// initialize Kubernetes client (not required if the manifests are only rendered)
+	if !cmd.opts.renderOnly() {
+		if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+			return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+		}
+	}
+	if cmd.opts.Resume && cmd.state.Cluster != cmd.K8s.RestConfig().Host {
+		return fmt.Errorf("The failed deployment ran on cluster '%s', but the current kubeconfig points to cluster '%s'",
+			cmd.state.Cluster, cmd.K8s.RestConfig().Host)
+	}
 
-		//if workspace already exists ask user for deletion-approval
-		_, err := os.Stat(cmd.opts.WorkspacePath)
-		approvalRequired := !os.IsNotExist(err)
+	// select the profile which fits the cluster capacity
+	if cmd.opts.Profile == profiles.Auto {
+		if err := cmd.selectProfile(); err != nil {
+			return err
+		}
+	}
 
-		downloadStep := cmd.NewStep(fmt.Sprintf("Downloading Kyma (%s) into workspace folder ", cmd.opts.Source))
-		if err := git.CloneRepo(kymaURL, cmd.opts.WorkspacePath, cmd.opts.Source); err != nil {
-			downloadStep.Failure()
+	// verify that the cluster fulfills the requirements before anything is deployed
+	if !cmd.opts.renderOnly() && !cmd.opts.Diff && !cmd.opts.SkipChecks {
+		if err := cmd.runPreflightChecks(); err != nil {
 			return err
 		}
-		downloadStep.Successf("Kyma downloaded into workspace folder")
+	}
 
-		// delete workspace folder
-		if approvalRequired && !cmd.avoidUserInteraction() {
-			userApprovalStep := cmd.NewStep("Workspace folder already exists")
-			if userApprovalStep.PromptYesNo(fmt.Sprintf("Delete workspace folder '%s' after Kyma deployment? ", cmd.opts.WorkspacePath)) {
-				defer os.RemoveAll(cmd.opts.WorkspacePath)
+	// only download if not from local sources
+	if cmd.opts.Source != localSource {
+		if !cmd.opts.renderOnly() && !cmd.opts.Diff && !cmd.opts.Resume {
+			if err := cmd.isCompatibleVersion(); err != nil {
+				return err
 			}
-			userApprovalStep.Success()
-		} else {
-			defer os.RemoveAll(cmd.opts.WorkspacePath)
 		}
 
+		removeWorkspace, err := cmd.resolveSources()
+		if err != nil {
+			return err
+		}
+		if removeWorkspace {
+			defer os.RemoveAll(cmd.opts.WorkspacePath)
+		}
 	}
 
-	overrides, err := cmd.overrides()
+	overrides, err := cmd.kymaOverrides()
 	if err != nil {
 		return err
 	}
 
-	// TODO remove this block when default component values are migrated to kyma 2.0
-	if err := overrides.AddFile(filepath.Join(cmd.opts.WorkspacePath, kyma2OverridesPath)); err != nil {
-		return errors.Wrap(err, "Could not add overrides for Kyma 2.0")
+	if cmd.opts.renderOnly() {
+		return cmd.renderKyma(overrides)
+	}
+	if cmd.opts.Diff {
+		return cmd.diffKyma(overrides)
 	}
 
 	err = cmd.deployKyma(overrides)
 	if err != nil {
+		if timingsErr := cmd.reportTimings(); timingsErr != nil {
+			fmt.Fprintln(cmd.messageWriter(), timingsErr)
+		}
 		return err
 	}
 	cmd.duration = time.Since(start)
 
-	if err := cmd.importCertificate(); err != nil {
-		return err
+	if cmd.opts.jsonEvents() {
+		// the events already reported the outcome of the deployment
+		return cmd.reportTimings()
+	}
+
+	// importing the certificate requires user interaction, which is not possible if the summary is processed by machines
+	if !cmd.opts.summaryOutput() {
+		if err := cmd.importCertificate(); err != nil {
+			return err
+		}
 	}
 
 	// print summary
@@ -233,7 +398,138 @@ func (cmd *command) Run() error {
 		return err
 	}
 
-	return cmd.printSummary(o)
+	if err := cmd.printSummary(o); err != nil {
+		return err
+	}
+	if err := cmd.reportTimings(); err != nil {
+		return err
+	}
+
+	// re-deploy the components of the local sources whenever their charts change
+	if cmd.opts.Watch {
+		return cmd.watchSources(overrides)
+	}
+	return nil
+}
+
+//resolveSources makes the Kyma sources available in the workspace.
+//It returns true if the workspace has to be removed after the deployment.
+func (cmd *command) resolveSources() (bool, error) {
+	switch {
+	case cmd.opts.Bundle != "":
+		// sources were extracted from the bundle
+		return false, nil
+	case cmd.opts.WorkspacePath == defaultWorkspacePath:
+		// no custom workspace defined: use the sources from the local cache
+		return false, cmd.useCachedSources()
+	}
+
+	//if workspace already exists ask user for deletion-approval
+	_, err := os.Stat(cmd.opts.WorkspacePath)
+	approvalRequired := !os.IsNotExist(err)
+
+	downloadStep := cmd.NewStep(fmt.Sprintf("Downloading Kyma (%s) into workspace folder ", cmd.opts.Source))
+	if err := git.CloneRepo(kymaURL, cmd.opts.WorkspacePath, cmd.sourceRevision()); err != nil {
+		downloadStep.Failure()
+		return false, err
+	}
+	downloadStep.Successf("Kyma downloaded into workspace folder")
+	// the commit is only needed to resume the deployment, so a workspace without git metadata is no error
+	cmd.commit, _ = sources.HeadCommit(cmd.opts.WorkspacePath)
+
+	// delete workspace folder
+	if approvalRequired && !cmd.avoidUserInteraction() {
+		userApprovalStep := cmd.NewStep("Workspace folder already exists")
+		defer userApprovalStep.Success()
+		return userApprovalStep.PromptYesNo(fmt.Sprintf("Delete workspace folder '%s' after Kyma deployment? ", cmd.opts.WorkspacePath)), nil
+	}
+	return true, nil
+}
+
+//kymaOverrides returns the configuration values of the deployment, rewritten to the image registry and validated (if enabled)
+func (cmd *command) kymaOverrides() (*overrides.Builder, error) {
+	overrides, err := cmd.overrides()
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO remove this block when default component values are migrated to kyma 2.0
+	if err := overrides.AddFile(filepath.Join(cmd.opts.WorkspacePath, kyma2OverridesPath)); err != nil {
+		return nil, errors.Wrap(err, "Could not add overrides for Kyma 2.0")
+	}
+
+	if cmd.opts.ImageRegistry != "" {
+		if err := cmd.rewriteImages(overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.opts.Validate {
+		if err := cmd.validateOverrides(overrides); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
+
+//extractBundle extracts the bundle into a temporary folder and uses its content for the deployment
+func (cmd *command) extractBundle() (string, error) {
+	dir, err := ioutil.TempDir("", "kyma-bundle-")
+	if err != nil {
+		return "", errors.Wrap(err, "Could not create temporary folder for bundle")
+	}
+
+	bundleStep := cmd.NewStep(fmt.Sprintf("Extracting bundle '%s'", cmd.opts.Bundle))
+	b, err := bundle.Extract(cmd.opts.Bundle, dir)
+	if err != nil {
+		bundleStep.Failure()
+		os.RemoveAll(dir)
+		return "", err
+	}
+	if b.ImagesIncluded && cmd.opts.ImageRegistry == "" {
+		bundleStep.LogInfo(`The container images of the bundle are not used. Set the "image-registry" flag to push them to the registry of your cluster.`)
+	}
+	bundleStep.Successf("Using Kyma (%s) from bundle", b.Source)
+
+	cmd.bundle = b
+	cmd.opts.Source = b.Source
+	cmd.opts.WorkspacePath = b.SourcesPath()
+	if len(cmd.opts.Components) == 0 && cmd.opts.ComponentsFile == defaultComponentsFile {
+		cmd.opts.ComponentsFile = b.ComponentsFile()
+	}
+	return dir, nil
+}
+
+//useCachedSources makes the Kyma sources available in the local source cache and uses them as workspace
+func (cmd *command) useCachedSources() error {
+	cacheDir, err := sources.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	sourcesStep := cmd.NewStep(fmt.Sprintf("Resolving Kyma sources (%s)", cmd.opts.Source))
+	src, downloaded, err := sources.NewCache(cacheDir, kymaURL).Get(cmd.sourceRevision())
+	if err != nil {
+		sourcesStep.Failure()
+		return err
+	}
+	cmd.commit = src.Commit
+	if downloaded {
+		sourcesStep.Successf("Kyma (%s) downloaded into source cache", cmd.opts.Source)
+	} else {
+		sourcesStep.Successf("Using cached Kyma sources (%s)", cmd.opts.Source)
+	}
+	cmd.opts.WorkspacePath = src.Path
+	return nil
+}
+
+//sourceRevision returns the revision of the Kyma sources to deploy.
+//A resumed deployment uses the commit of the failed deployment, so that a branch which moved on in the meantime does not change the deployed sources.
+func (cmd *command) sourceRevision() string {
+	if cmd.opts.Resume && cmd.state.Commit != "" && cmd.opts.Source == cmd.state.Source {
+		return cmd.state.Commit
+	}
+	return cmd.opts.Source
 }
 
 func (cmd *command) isCompatibleVersion() error {
@@ -329,7 +625,7 @@ func (cmd *command) deployKyma(overrides *overrides.Builder) error {
 		BackoffInitialIntervalSeconds: 3,
 		BackoffMaxElapsedTimeSeconds:  60 * 5,
 		Log:                           cli.NewHydroformLoggerAdapter(cli.NewLogger(cmd.Verbose)),
-		Profile:                       cmd.opts.Profile,
+		Profile:                       cmd.opts.chartProfile(),
 		ComponentList:                 compList,
 		ResourcePath:                  resourcePath,
 		InstallationResourcePath:      installResourcePath,
@@ -340,27 +636,149 @@ func (cmd *command) deployKyma(overrides *overrides.Builder) error {
 		},
 		ReuseHelmValues: cmd.opts.ReuseHelmValues,
 	}
-	// if not verbose, use asyncui for clean output
+	// write JSON events for machines, otherwise use asyncui for clean output if not verbose:
+	// interactive terminals show the live view of the running components, all others one line per step
+	// (steps which run during the deployment, e.g. hooks, are created by the step factory of the live view)
 	var callback func(deployment.ProcessUpdate)
-	if !cmd.Verbose {
+	var stepFactory step.FactoryInterface = &cmd.Factory
+	switch {
+	case cmd.opts.jsonEvents():
+		callback = jsonevents.NewStream(os.Stdout).Callback()
+	case !cmd.Verbose && !cmd.NonInteractive && asyncui.IsTerminal():
+		ui := asyncui.NewLiveUI(os.Stdout, map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+			deployment.InstallPreRequisites: compList.Prerequisites,
+			deployment.InstallComponents:    compList.Components,
+		})
+		ui.Tracker = asyncui.HelmTracker(cmd.K8s.Static())
+		defer ui.Stop()
+		callback = ui.Callback()
+		stepFactory = ui
+	case !cmd.Verbose:
 		ui := asyncui.AsyncUI{StepFactory: &cmd.Factory}
 		callback = ui.Callback()
+	}
+
+	// record the duration of each component to report where the deployment spends its time
+	cmd.timings = timings.NewRecorder(cmd.opts.Concurrency, map[deployment.InstallationPhase][]string{
+		deployment.InstallPreRequisites: componentNames(compList.Prerequisites),
+		deployment.InstallComponents:    componentNames(compList.Components),
+	})
+	deployCallback := cmd.timings.Callback(callback)
+
+	// record the outcome of each component to be able to resume a failed deployment
+	// (deployments to several clusters cannot be resumed)
+	var state *deploymentState
+	if cmd.cluster == nil {
+		if state, err = cmd.deploymentState(compList); err != nil {
+			return err
+		}
+		deployCallback = state.callback(deployCallback)
+	}
+
+	deploy := func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+		cfg := *installationCfg
+		cfg.ComponentList = list
+		installer, err := deployment.NewDeployment(&cfg, overrides, callback)
 		if err != nil {
 			return err
 		}
+		if cmd.cluster != nil {
+			// deployments to several clusters run in parallel: stderr is silenced once for all of them
+			return installer.StartKymaDeployment()
+		}
+		return silenceStderr(cmd.Options.Verbose, installer.StartKymaDeployment)
 	}
 
-	installer, err := deployment.NewDeployment(installationCfg, overrides, callback)
+	// run the hooks of the components before the outcome of a component is recorded: failed hooks are reported as failed components
+	if cmd.opts.HooksFile != "" {
+		var hooksRunner *hooks.Runner
+		if hooksRunner, err = cmd.hooksRunner(stepFactory); err != nil {
+			return err
+		}
+		if err = hooksRunner.Deploy(compList, deploy, deployCallback); err == nil {
+			err = hooksRunner.Err()
+		}
+	} else {
+		err = deploy(compList, deployCallback)
+	}
 	if err != nil {
+		if state == nil {
+			return err
+		}
+		if saveErr := state.save(); saveErr == nil {
+			fmt.Fprintln(cmd.messageWriter(), "To deploy only the failed components, run: kyma alpha deploy --resume")
+		}
 		return err
 	}
+	if state != nil {
+		if err := state.remove(); err != nil {
+			return err
+		}
+	}
+
+	if cmd.opts.Verify {
+		return cmd.verifyKyma(compList, callback)
+	}
+	return nil
+}
+
+//hooksRunner loads the hooks file and creates the runner for the hooks of the deployed components,
+//which shows each hook and its output as a step of the given factory
+func (cmd *command) hooksRunner(stepFactory step.FactoryInterface) (*hooks.Runner, error) {
+	hooksFile, err := hooks.Load(cmd.opts.HooksFile)
+	if err != nil {
+		return nil, err
+	}
+	runner := hooks.NewRunner(hooksFile, kube.KubeconfigPath(cmd.KubeconfigPath), cmd.K8s.Static())
+	runner.StepFactory = stepFactory
+	return runner, nil
+}
+
+//loadState reads the state of the failed deployment and restores its settings
+func (cmd *command) loadState() error {
+	file, err := deploymentStateFile()
+	if err != nil {
+		return err
+	}
+	if cmd.state, err = loadDeploymentState(file); err != nil {
+		return err
+	}
+	compList := cmd.state.remainingComponents()
+	if len(compList.Prerequisites) == 0 && len(compList.Components) == 0 {
+		return fmt.Errorf("All components of the last deployment were deployed successfully, nothing to resume")
+	}
+	changed := cmd.flagChanged
+	if changed == nil {
+		changed = func(string) bool { return false }
+	}
+	if conflicts := cmd.state.restoreOptions(cmd.opts, changed); len(conflicts) > 0 {
+		restoreStep := cmd.NewStep("Restoring the settings of the failed deployment")
+		restoreStep.LogInfof("The flags --%s override the settings of the failed deployment", strings.Join(conflicts, ", --"))
+		restoreStep.Success()
+	}
+	return nil
+}
 
-	return silenceStderr(cmd.Options.Verbose, installer.StartKymaDeployment)
+//deploymentState returns the state of the resumed deployment or creates a new state for the given component list
+func (cmd *command) deploymentState(compList *installConfig.ComponentList) (*deploymentState, error) {
+	if cmd.opts.Resume {
+		return cmd.state, nil
+	}
+	file, err := deploymentStateFile()
+	if err != nil {
+		return nil, err
+	}
+	return newDeploymentState(file, cmd.K8s.RestConfig().Host, cmd.commit, cmd.opts, compList), nil
 }
 
 func (cmd *command) createCompList() (*installConfig.ComponentList, error) {
+	if cmd.components != nil {
+		return cmd.components, nil
+	}
 	var compList *installConfig.ComponentList
-	if len(cmd.opts.Components) > 0 {
+	if cmd.opts.Resume {
+		compList = cmd.state.remainingComponents()
+	} else if len(cmd.opts.Components) > 0 {
 		compList = &installConfig.ComponentList{}
 		for _, comp := range cmd.opts.Components {
 			// component should be provided in the following format: componentName@namespace
@@ -389,6 +807,26 @@ func (cmd *command) createCompList() (*installConfig.ComponentList, error) {
 func (cmd *command) overrides() (*overrides.Builder, error) {
 	ob := &overrides.Builder{}
 
+	// add values file of a user-defined profile (all other values take precedence)
+	profileFile, err := cmd.opts.profileValuesFile()
+	if err != nil {
+		return ob, err
+	}
+	if profileFile != "" {
+		if err := ob.AddFile(profileFile); err != nil {
+			return ob, err
+		}
+	}
+
+	// add values files of the bundle (values files provided by the user take precedence)
+	if cmd.bundle != nil {
+		for _, valuesFile := range cmd.bundle.ValuesFilePaths() {
+			if err := ob.AddFile(valuesFile); err != nil {
+				return ob, err
+			}
+		}
+	}
+
 	// add override files
 	overridesFiles, err := cmd.opts.ResolveOverridesFiles()
 	if err != nil {
@@ -406,30 +844,13 @@ func (cmd *command) overrides() (*overrides.Builder, error) {
 	}
 
 	// add overrides provided as CLI params
-	for _, overrideList := range cmd.opts.Overrides {
-		overrideList := strings.Split(overrideList, ",")
-		for _, override := range overrideList {
-			keyValuePairs := properties.MustLoadString(override)
-			if keyValuePairs.Len() < 1 {
-				return ob, fmt.Errorf("Override has wrong format: Provide overrides in 'key=value' format")
-			}
-
-			// process key-value pair
-			for _, key := range keyValuePairs.Keys() {
-				value, ok := keyValuePairs.Get(key)
-				if !ok || value == "" {
-					return ob, fmt.Errorf("Cannot read value of override '%s'", key)
-				}
-
-				comp, overridesMap, err := cmd.convertToOverridesMap(key, value)
-				if err != nil {
-					return ob, err
-				}
-
-				if err := ob.AddOverrides(comp, overridesMap); err != nil {
-					return ob, err
-				}
-			}
+	values, err := cmd.opts.parseValues()
+	if err != nil {
+		return ob, err
+	}
+	for comp, compValues := range values {
+		if err := ob.AddOverrides(comp, compValues.(map[string]interface{})); err != nil {
+			return ob, err
 		}
 	}
 
@@ -471,42 +892,12 @@ func (cmd *command) setGlobalOverrides(overrides *overrides.Builder) error {
 	return nil
 }
 
-// convertToOverridesMap parses the override key and converts it into an nested map.
-// First element of the key is returned as component name, all other elements are used as key/sub-key in the nested map.
-func (cmd *command) convertToOverridesMap(key, value string) (string, map[string]interface{}, error) {
-	var comp string
-	var latestOverrideMap map[string]interface{}
-
-	keyTokens := strings.Split(key, ".")
-	if len(keyTokens) < 2 {
-		return comp, latestOverrideMap, fmt.Errorf("Override key must contain at least the chart name "+
-			"and one override: chart.override[.suboverride]=value (given was '%s=%s')", key, value)
+//messageWriter returns the writer for messages to the user (stdout is reserved for machine-readable output)
+func (cmd *command) messageWriter() io.Writer {
+	if cmd.opts.Output != "" {
+		return os.Stderr
 	}
-
-	// first token in key is the chart name
-	comp = keyTokens[0]
-
-	// use the remaining key-tokens to build the nested overrides map
-	// processing starts from last element to the beginning
-	for idx := range keyTokens[1:] {
-		overrideMap := make(map[string]interface{})     // current override-map
-		overrideName := keyTokens[len(keyTokens)-1-idx] // get last token element
-		if idx == 0 {
-			// this is the last key-token, use it value
-			overrideMap[overrideName] = value
-		} else {
-			// the latest override map has to become a sub-map of the current override-map
-			overrideMap[overrideName] = latestOverrideMap
-		}
-		//set the current override map as latest override map
-		latestOverrideMap = overrideMap
-	}
-
-	if len(latestOverrideMap) < 1 {
-		return comp, latestOverrideMap, fmt.Errorf("Failed to extract overrides map from '%s=%s'", key, value)
-	}
-
-	return comp, latestOverrideMap, nil
+	return os.Stdout
 }
 
 //avoidUserInteraction returns true if user won't provide input
@@ -515,71 +906,25 @@ func (cmd *command) avoidUserInteraction() bool {
 }
 
 func (cmd *command) printSummary(o overrides.Overrides) error {
-	kymaVersionNames, err := cmd.installedKymaVersions()
-	if err != nil {
-		return err
-	}
-
 	domain, ok := o.Find("global.domainName")
 	if !ok {
 		return errors.New("Domain not found in overrides")
 	}
 
-	var consoleURL string
-	vs, err := cmd.K8s.Istio().NetworkingV1alpha3().VirtualServices("kyma-system").Get(context.Background(), "console-web", metav1.GetOptions{})
-	switch {
-	case k8sErrors.IsNotFound(err):
-		consoleURL = "not installed"
-	case err != nil:
-		return err
-	case vs != nil && len(vs.Spec.Hosts) > 0:
-		consoleURL = fmt.Sprintf("https://%s", vs.Spec.Hosts[0])
-	default:
-		return errors.New("console host could not be obtained")
-	}
-
-	var email, pass string
-	adm, err := cmd.K8s.Static().CoreV1().Secrets("kyma-system").Get(context.Background(), "admin-user", metav1.GetOptions{})
-	switch {
-	case k8sErrors.IsNotFound(err):
-		break
-	case err != nil:
+	sum, err := summary.Collect(cmd.K8s, cmd.KubeconfigPath, domain.(string))
+	if err != nil {
 		return err
-	case adm != nil:
-		email = string(adm.Data["email"])
-		pass = string(adm.Data["password"])
-	default:
-		return errors.New("admin credentials could not be obtained")
 	}
+	sum.NonInteractive = cmd.NonInteractive
+	sum.Duration = cmd.duration
 
-	sum := nice.Summary{
-		NonInteractive: cmd.NonInteractive,
-		Version:        strings.Join(kymaVersionNames, ", "),
-		URL:            domain.(string),
-		Console:        consoleURL,
-		Duration:       cmd.duration,
-		Email:          string(email),
-		Password:       string(pass),
+	if cmd.opts.summaryOutput() {
+		return sum.PrintStructured(cmd.opts.Output, cmd.opts.ShowSecrets)
 	}
-
 	return sum.Print()
 }
 
-func (cmd *command) installedKymaVersions() ([]string, error) {
-	provider, err := helm.NewKymaMetadataProvider(installConfig.KubeconfigSource{
-		Path: kube.KubeconfigPath(cmd.KubeconfigPath),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	kymaVersionSet, err := provider.Versions()
-	if err != nil {
-		return nil, err
-	}
-	return kymaVersionSet.Names(), nil
-}
-
+//checkDevDomain refreshes the Kyma entries of the hosts file if the file has Kyma entries already or the dev domain cannot be resolved
 func (cmd *command) checkDevDomain(o overrides.Overrides) error {
 	domainOverride, ok := o.Find("global.domainName")
 	if !ok {
@@ -587,28 +932,69 @@ func (cmd *command) checkDevDomain(o overrides.Overrides) error {
 	}
 	domain := fmt.Sprintf("%v", domainOverride)
 
-	if domain != "local.kyma.dev" {
-		// No dev domain, nothing to check
+	hostsFile := hosts.NewFile()
+	ip, _, err := hostsFile.Managed()
+	managed := err == nil && ip != ""
+	devDomainUnresolvable := domain == "local.kyma.dev" && !checkDNS(domain)
+	if !managed && !devDomainUnresolvable {
+		// nothing to refresh
 		return nil
 	}
+	if !managed {
+		ip = hosts.DefaultIP
+	}
 
-	requireEtcHostsHint := !checkDNS(domain)
-	if requireEtcHostsHint {
-		hosts, err := hosts.GetVirtualServiceHostnames(cmd.K8s)
-		if err != nil {
-			return err
-		}
-		hosts = append(hosts, "local.kyma.dev")
+	hostnames, err := hosts.GetVirtualServiceHostnames(cmd.K8s)
+	if err != nil {
+		return err
+	}
+	hostnames = hosts.Hostnames(append(hostnames, domain))
 
-		fmt.Println()
-		fmt.Printf("The configured Kyma domain %s is not resolvable. This could be due to activated rebind protection of your DNS resolver. Please add virtual service domains to your hosts file.\n", domain)
-		fmt.Printf("E.g., execute\n\nsudo  /bin/sh -c 'echo \"127.0.0.1 %s\" >> /etc/hosts'\n\non Linux or macOS.\n", strings.Join(hosts, " "))
-		fmt.Println()
+	if cmd.approveSyncHosts(hostsFile, devDomainUnresolvable) {
+		// create a simple step without a spinner (spinner overwrites sudo prompt)
+		f := step.Factory{
+			NonInteractive: true,
+		}
+		if syncHosts(f.NewStep("Updating the Kyma entries of the hosts file"), hostsFile, ip, hostnames) {
+			return nil
+		}
 	}
 
+	w := cmd.messageWriter()
+	fmt.Fprintln(w)
+	if devDomainUnresolvable {
+		fmt.Fprintf(w, "The configured Kyma domain %s is not resolvable. This could be due to activated rebind protection of your DNS resolver. Please add virtual service domains to your hosts file.\n", domain)
+	} else {
+		fmt.Fprintf(w, "The Kyma entries of your hosts file '%s' might be outdated.\n", hostsFile.Path)
+	}
+	fmt.Fprintf(w, "To update them, run:\n\nkyma alpha hosts sync --ip %s --hostname %s\n", ip, domain)
+	fmt.Fprintln(w)
 	return nil
 }
 
+//syncHosts maps the hostnames to the IP in the hosts file and reports the result on the step. It returns false if the file could not be updated.
+func syncHosts(s step.Step, hostsFile *hosts.File, ip string, hostnames []string) bool {
+	if _, err := hostsFile.Sync(ip, hostnames); err != nil {
+		s.Failuref("Could not update the hosts file: %s", err)
+		return false
+	}
+	s.Successf("Mapped %d hostnames to %s", len(hostnames), ip)
+	return true
+}
+
+//approveSyncHosts asks the user whether the Kyma entries of the hosts file should be updated
+func (cmd *command) approveSyncHosts(hostsFile *hosts.File, devDomainUnresolvable bool) bool {
+	if cmd.avoidUserInteraction() { //changing the hosts file can require a sudo password
+		return false
+	}
+	qSyncHostsStep := cmd.NewStep("Update Kyma entries of the hosts file")
+	defer qSyncHostsStep.Success()
+	if devDomainUnresolvable {
+		return qSyncHostsStep.PromptYesNo(fmt.Sprintf("The Kyma domain is not resolvable. Should the Kyma hostnames be added to '%s'?", hostsFile.Path))
+	}
+	return qSyncHostsStep.PromptYesNo(fmt.Sprintf("Should the Kyma entries of '%s' be updated?", hostsFile.Path))
+}
+
 func checkDNS(domain string) bool {
 	records, err := net.LookupHost(domain)
 	if err != nil || len(records) == 0 {
@@ -626,23 +1012,28 @@ func (cmd *command) importCertificate() error {
 		return nil
 	}
 
-	// get cert from cluster
-	cert, err := ca.CertificateAlpha()
-	if err != nil {
-		return err
-	}
+	// the CA of a generated certificate is imported, which makes all certificates of the domain trusted
+	certFile := cmd.caFile
+	if certFile == "" {
+		// get cert from cluster
+		cert, err := ca.CertificateAlpha()
+		if err != nil {
+			return err
+		}
 
-	tmpFile, err := ioutil.TempFile(os.TempDir(), "kyma-*.crt")
-	if err != nil {
-		return errors.Wrap(err, "Cannot create temporary file for Kyma certificate")
-	}
-	defer os.Remove(tmpFile.Name())
+		tmpFile, err := ioutil.TempFile(os.TempDir(), "kyma-*.crt")
+		if err != nil {
+			return errors.Wrap(err, "Cannot create temporary file for Kyma certificate")
+		}
+		defer os.Remove(tmpFile.Name())
 
-	if _, err = tmpFile.Write(cert); err != nil {
-		return errors.Wrap(err, "Failed to write the kyma certificate")
-	}
-	if err := tmpFile.Close(); err != nil {
-		return err
+		if _, err = tmpFile.Write(cert); err != nil {
+			return errors.Wrap(err, "Failed to write the kyma certificate")
+		}
+		if err := tmpFile.Close(); err != nil {
+			return err
+		}
+		certFile = tmpFile.Name()
 	}
 
 	// create a simple step to print certificate import steps without a spinner (spinner overwrites sudo prompt)
@@ -652,7 +1043,7 @@ func (cmd *command) importCertificate() error {
 	}
 	s := f.NewStep("Importing Kyma certificate")
 
-	if err := ca.StoreCertificate(tmpFile.Name(), s); err != nil {
+	if err := ca.StoreCertificate(certFile, s); err != nil {
 		return err
 	}
 	s.Successf("Kyma root certificate imported")
@@ -2,8 +2,13 @@ package deploy
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/kyma-project/cli/internal/hosts"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -140,3 +145,28 @@ func TestCreateCompList(t *testing.T) {
 		require.Equal(t, "test-namespace", compList.Components[1].Namespace)
 	})
 }
+
+func TestSyncHosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sync-hosts-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("Hosts file updated", func(t *testing.T) {
+		path := filepath.Join(dir, "hosts")
+		require.NoError(t, ioutil.WriteFile(path, []byte("127.0.0.1 localhost\n"), 0644))
+		s := &stepMocks.Step{}
+		require.True(t, syncHosts(s, &hosts.File{Path: path}, hosts.DefaultIP, []string{"console.local.kyma.dev"}))
+		require.True(t, s.IsSuccessful())
+		require.Equal(t, []string{"Mapped 1 hostnames to 127.0.0.1"}, s.Statuses())
+	})
+
+	t.Run("Failure shows the cause", func(t *testing.T) {
+		path := filepath.Join(dir, "missing")
+		s := &stepMocks.Step{}
+		require.False(t, syncHosts(s, &hosts.File{Path: path}, hosts.DefaultIP, []string{"console.local.kyma.dev"}))
+		require.False(t, s.IsSuccessful())
+		require.Len(t, s.Statuses(), 1)
+		require.Contains(t, s.Statuses()[0], "Could not read the hosts file '"+path+"'")
+		require.NotContains(t, s.Statuses()[0], "nil")
+	})
+}
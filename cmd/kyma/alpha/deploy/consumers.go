@@ -0,0 +1,179 @@
+package deploy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/pkg/errors"
+)
+
+//DeployConsumers deploys the components whose charts use one of the given configuration values (e.g. "global.tlsCrt"),
+//so that changed values are applied without deploying all components. If the options select components, these are deployed instead.
+//The pre-flight checks, the version compatibility check, the certificate import, and the summary are skipped.
+//It returns the names of the deployed components.
+func DeployConsumers(o *Options, values ...string) ([]string, error) {
+	cmd := &command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+	if err := o.validateFlags(); err != nil {
+		return nil, err
+	}
+	if o.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	if o.Verbose {
+		cmd.Factory.UseLogger = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return nil, errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	if o.Source != localSource {
+		removeWorkspace, err := cmd.resolveSources()
+		if err != nil {
+			return nil, err
+		}
+		if removeWorkspace {
+			defer os.RemoveAll(o.WorkspacePath)
+		}
+	}
+
+	compList, err := cmd.createCompList()
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Components) == 0 {
+		if compList, err = consumers(compList, filepath.Join(o.WorkspacePath, "resources"), values); err != nil {
+			return nil, err
+		}
+	}
+	names := append(componentNames(compList.Prerequisites), componentNames(compList.Components)...)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("No component uses the configuration values '%s'", strings.Join(values, "', '"))
+	}
+	cmd.components = compList
+
+	overrides, err := cmd.kymaOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.deployKyma(overrides); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+//consumers returns the components of the list whose charts (including their sub-charts) use one of the configuration values
+func consumers(compList *installConfig.ComponentList, resourcesDir string, values []string) (*installConfig.ComponentList, error) {
+	result := &installConfig.ComponentList{}
+	for _, compDef := range compList.Prerequisites {
+		uses, err := chartUses(filepath.Join(resourcesDir, compDef.Name), values)
+		if err != nil {
+			return nil, err
+		}
+		if uses {
+			result.Prerequisites = append(result.Prerequisites, compDef)
+		}
+	}
+	for _, compDef := range compList.Components {
+		uses, err := chartUses(filepath.Join(resourcesDir, compDef.Name), values)
+		if err != nil {
+			return nil, err
+		}
+		if uses {
+			result.Components = append(result.Components, compDef)
+		}
+	}
+	return result, nil
+}
+
+//chartUses returns true if a template of the chart refers to one of the configuration values.
+//The values of a sub-chart are the ones below its name, except the global values, which all charts share.
+func chartUses(chartDir string, values []string) (bool, error) {
+	uses := false
+	err := filepath.Walk(chartDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || uses {
+			return err
+		}
+		if info.IsDir() || !isTemplate(path) {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		refs, err := valueRefs(path, string(content))
+		if err != nil {
+			// a template which cannot be parsed is checked by the last key of each value:
+			// deploying a component which does not use the values is better than missing one which does
+			for _, value := range values {
+				keys := strings.Split(value, ".")
+				if strings.Contains(string(content), keys[len(keys)-1]) {
+					uses = true
+				}
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		prefix := subChartPrefix(rel)
+		for _, ref := range refs {
+			for _, value := range values {
+				if subChartRef(ref, prefix).uses(value) || (prefix != "" && ref.path == "" && !ref.scope && isGlobal(value)) {
+					uses = true
+				}
+			}
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not read the chart '%s'", chartDir)
+	}
+	return uses, nil
+}
+
+//subChartPrefix returns the path of the values of the sub-chart which contains the template, e.g. "oathkeeper." for "charts/oathkeeper/templates/certs.tpl"
+func subChartPrefix(templatePath string) string {
+	var prefix string
+	parts := strings.Split(filepath.ToSlash(templatePath), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "charts" {
+			prefix += parts[i+1] + "."
+			i++
+		}
+	}
+	return prefix
+}
+
+//subChartRef returns the reference of a sub-chart template as reference to the values of the parent chart
+func subChartRef(ref valueRef, prefix string) valueRef {
+	if prefix == "" || isGlobal(ref.path) {
+		return ref
+	}
+	ref.path = strings.TrimSuffix(prefix+ref.path, ".")
+	return ref
+}
+
+//isGlobal returns true for the global values, which are shared by a chart and its sub-charts
+func isGlobal(path string) bool {
+	return path == "global" || strings.HasPrefix(path, "global.")
+}
+
+//isTemplate returns true if the file is part of the templates of a chart
+func isTemplate(path string) bool {
+	ext := filepath.Ext(path)
+	return strings.Contains(filepath.ToSlash(path), "/templates/") && (ext == ".yaml" || ext == ".yml" || ext == ".tpl" || ext == ".txt")
+}
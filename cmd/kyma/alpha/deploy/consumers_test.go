@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consumers-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeChartFile := func(path, content string) {
+		file := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	}
+	writeChartFile("istio-resources/templates/gateway-secret.yaml", "tls.crt: {{ .Values.global.tlsCrt }}")
+	writeChartFile("istio-resources/values.yaml", "global:\n  tlsCrt: \"\"")
+	writeChartFile("ory/charts/oathkeeper/templates/certs.tpl", "{{ $.Values.global.tlsKey | b64dec }}")
+	writeChartFile("eventing/templates/deployment.yaml", "domain: {{ .Values.global.domainName }}")
+	writeChartFile("eventing/values.yaml", "# .Values.global.tlsCrt is not used")
+
+	compList := &installConfig.ComponentList{
+		Prerequisites: []installConfig.ComponentDefinition{
+			{Name: "istio-resources", Namespace: "istio-system"},
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+		},
+		Components: []installConfig.ComponentDefinition{
+			{Name: "eventing", Namespace: "kyma-system"},
+			{Name: "ory", Namespace: "kyma-system"},
+		},
+	}
+	result, err := consumers(compList, dir, []string{"global.tlsCrt", "global.tlsKey"})
+	require.NoError(t, err)
+	require.Equal(t, []installConfig.ComponentDefinition{{Name: "istio-resources", Namespace: "istio-system"}}, result.Prerequisites)
+	require.Equal(t, []installConfig.ComponentDefinition{{Name: "ory", Namespace: "kyma-system"}}, result.Components)
+}
+
+func TestChartUses(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uses     bool
+	}{
+		{name: "Field", template: `{{ .Values.global.tlsCrt | b64enc }}`, uses: true},
+		{name: "Field of the root context", template: `{{ $.Values.global.tlsCrt }}`, uses: true},
+		{name: "Other field", template: `{{ .Values.global.domainName }}`, uses: false},
+		{name: "Index", template: `{{ index .Values.global "tlsCrt" | b64enc }}`, uses: true},
+		{name: "Index with all keys", template: `{{ index .Values "global" "tlsCrt" }}`, uses: true},
+		{name: "Index of other key", template: `{{ index .Values.global "domainName" }}`, uses: false},
+		{name: "Index with variable key", template: `{{ $key := "tlsCrt" }}{{ index .Values.global $key }}`, uses: true},
+		{name: "With block", template: "{{ with .Values.global }}\ntls.crt: {{ .tlsCrt }}\n{{ end }}", uses: true},
+		{name: "Nested with blocks", template: "{{ with .Values.global }}{{ with .tlsCrt }}{{ . | quote }}{{ end }}{{ end }}", uses: true},
+		{name: "With block of other key", template: "{{ with .Values.global }}\ndomain: {{ .domainName }}\n{{ end }}", uses: false},
+		{name: "Variable", template: `{{ $global := .Values.global }}{{ $global.tlsCrt }}`, uses: true},
+		{name: "Whole map", template: `{{ toYaml .Values.global | nindent 2 }}`, uses: true},
+		{name: "Range over map", template: `{{ range $key, $value := .Values.global }}{{ $key }}: {{ $value }}{{ end }}`, uses: true},
+		{name: "Named template", template: `{{ define "tls" }}{{ .Values.global.tlsCrt }}{{ end }}`, uses: true},
+		{name: "Comment", template: `{{/* .Values.global.tlsCrt is set by the CLI */}}`, uses: false},
+		{name: "Template which cannot be parsed", template: `{{ unknownFunc .Values.global.tlsCrt }}`, uses: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "chart-uses-test")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0700))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "templates", "secret.yaml"), []byte(tt.template), 0600))
+
+			uses, err := chartUses(dir, []string{"global.tlsCrt"})
+			require.NoError(t, err)
+			require.Equal(t, tt.uses, uses)
+		})
+	}
+
+	t.Run("Values of sub-charts", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "chart-uses-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		templates := filepath.Join(dir, "charts", "oathkeeper", "templates")
+		require.NoError(t, os.MkdirAll(templates, 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(templates, "secret.yaml"), []byte(`{{ .Values.tlsCrt }}{{ .Values.global.tlsKey }}`), 0600))
+
+		for value, expected := range map[string]bool{
+			"tlsCrt":            false,
+			"oathkeeper.tlsCrt": true,
+			"global.tlsKey":     true,
+			"global.tlsCrt":     false,
+		} {
+			uses, err := chartUses(dir, []string{value})
+			require.NoError(t, err)
+			require.Equal(t, expected, uses, value)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/render"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	helmKube "helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+//diffKyma renders all components and compares them with the live objects of the installed Kyma components in the cluster
+func (cmd *command) diffKyma(overrides *overrides.Builder) error {
+	compList, err := cmd.createCompList()
+	if err != nil {
+		return err
+	}
+
+	renderer, err := cmd.renderer(overrides)
+	if err != nil {
+		return err
+	}
+
+	changes := make(map[render.Change]int)
+	var restarts []string
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(cmd.K8s.Static().Discovery()))
+
+	comps := append([]installConfig.ComponentDefinition{}, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+	for _, comp := range comps {
+		diffStep := cmd.NewStep(fmt.Sprintf("Comparing component '%s'", comp.Name))
+		rel, err := cmd.installedRelease(comp)
+		if err != nil {
+			diffStep.Failure()
+			return err
+		}
+
+		// the deployment merges the values of the installed release into the overrides, so the diff must do so as well
+		values := render.ComponentValues(renderer.Overrides, comp.Name)
+		installed := ""
+		if rel != nil {
+			installed = render.ReleaseContent(rel)
+			if cmd.opts.ReuseHelmValues {
+				values = render.ReuseValues(values, rel.Config)
+			}
+		}
+		rendered, err := renderer.RenderWithValues(comp, values)
+		if err != nil {
+			diffStep.Failure()
+			return err
+		}
+
+		diffs, err := render.DiffLive(installed, rendered, cmd.liveGetter(mapper, comp.Namespace))
+		if err != nil {
+			diffStep.Failure()
+			return errors.Wrapf(err, "Could not compare component '%s'", comp.Name)
+		}
+
+		if len(diffs) == 0 {
+			diffStep.Successf("Component '%s' is unchanged", comp.Name)
+			continue
+		}
+		diffStep.Successf("Component '%s' has %d changed resources", comp.Name, len(diffs))
+		for _, d := range diffs {
+			fmt.Print(d.Diff)
+			changes[d.Change]++
+			if d.Restart {
+				restarts = append(restarts, d.Resource)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Summary: %d resources added, %d changed, %d removed\n",
+		changes[render.Added], changes[render.Changed], changes[render.Removed])
+	if len(restarts) > 0 {
+		fmt.Printf("Workloads restarted by the deployment:\n  %s\n", strings.Join(restarts, "\n  "))
+	}
+	return nil
+}
+
+//installedRelease returns the installed Helm release of a component (nil if the component is not installed)
+func (cmd *command) installedRelease(comp installConfig.ComponentDefinition) (*release.Release, error) {
+	cfg := &action.Configuration{}
+	restGetter := helmKube.GetConfig(kube.KubeconfigPath(cmd.KubeconfigPath), "", comp.Namespace)
+	if err := cfg.Init(restGetter, comp.Namespace, "secrets", func(string, ...interface{}) {}); err != nil {
+		return nil, errors.Wrap(err, "Could not initialize the Helm client")
+	}
+
+	rel, err := action.NewGet(cfg).Run(comp.Name)
+	if errors.Cause(err) == driver.ErrReleaseNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not get the installed release of component '%s'", comp.Name)
+	}
+	return rel, nil
+}
+
+//liveGetter returns a getter for the live objects of the resources of a component.
+//Resources without namespace are looked up in the namespace of the component, as Helm installs them there.
+func (cmd *command) liveGetter(mapper meta.RESTMapper, namespace string) render.LiveGetter {
+	return func(res map[string]interface{}) (map[string]interface{}, error) {
+		obj := &unstructured.Unstructured{Object: res}
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// the CRD of the resource is not installed yet
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		client := cmd.K8s.Dynamic().Resource(mapping.Resource)
+		var live *unstructured.Unstructured
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ns := obj.GetNamespace()
+			if ns == "" {
+				ns = namespace
+			}
+			live, err = client.Namespace(ns).Get(context.Background(), obj.GetName(), metav1.GetOptions{})
+		} else {
+			live, err = client.Get(context.Background(), obj.GetName(), metav1.GetOptions{})
+		}
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return live.Object, nil
+	}
+}
@@ -0,0 +1,105 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/images"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/pkg/errors"
+)
+
+const pushImagesTimeout = 60 * time.Minute
+
+//rewriteImages adds the overrides which make all components pull their container images from the image registry
+//and verifies that the rendered components do not reference images of other registries
+func (cmd *command) rewriteImages(overrides *overrides.Builder) error {
+	compList, err := cmd.createCompList()
+	if err != nil {
+		return err
+	}
+
+	rewriteStep := cmd.NewStep(fmt.Sprintf("Rewriting container images to registry '%s'", cmd.opts.ImageRegistry))
+	renderer, err := cmd.renderer(overrides)
+	if err != nil {
+		rewriteStep.Failure()
+		return err
+	}
+	registryOverrides, err := images.RegistryOverrides(renderer, compList, cmd.opts.ImageRegistry)
+	if err != nil {
+		rewriteStep.Failure()
+		return err
+	}
+	for key, values := range registryOverrides {
+		if err := overrides.AddOverrides(key, values.(map[string]interface{})); err != nil {
+			rewriteStep.Failure()
+			return err
+		}
+	}
+
+	// render again to detect images which are not configurable by chart values
+	if renderer, err = cmd.renderer(overrides); err != nil {
+		rewriteStep.Failure()
+		return err
+	}
+	imgs, err := images.Collect(renderer, compList)
+	if err != nil {
+		rewriteStep.Failure()
+		return err
+	}
+	if foreign := images.NotInRegistry(imgs, cmd.opts.ImageRegistry); len(foreign) > 0 {
+		rewriteStep.Failure()
+		return fmt.Errorf("The following images are not pulled from registry '%s'. Set their image values with the \"value\" or \"values-file\" flag:\n  %s",
+			cmd.opts.ImageRegistry, strings.Join(foreign, "\n  "))
+	}
+	rewriteStep.Successf("All %d container images are pulled from registry '%s'", len(imgs), cmd.opts.ImageRegistry)
+	return nil
+}
+
+//pushBundleImages loads the container images of the bundle into the Docker daemon and pushes them to the image registry,
+//so the cluster can pull them without internet access
+func (cmd *command) pushBundleImages() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pushImagesTimeout)
+	defer cancel()
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return errors.Wrap(err, "Could not create Docker client")
+	}
+	dockerClient.NegotiateAPIVersion(ctx)
+
+	loadStep := cmd.NewStep("Loading container images of the bundle")
+	archive, err := os.Open(cmd.bundle.ImagesArchive())
+	if err != nil {
+		loadStep.Failure()
+		return errors.Wrap(err, "Could not open image tarball of the bundle")
+	}
+	defer archive.Close()
+	if err := docker.LoadImages(ctx, dockerClient, archive); err != nil {
+		loadStep.Failure()
+		return errors.Wrap(err, "Could not load container images of the bundle")
+	}
+	loadStep.Successf("Loaded %d container images", len(cmd.bundle.Images))
+
+	for _, image := range cmd.bundle.Images {
+		if images.InRegistry(image, cmd.opts.ImageRegistry) {
+			continue
+		}
+		target := images.Rewrite(image, cmd.opts.ImageRegistry)
+		pushStep := cmd.NewStep(fmt.Sprintf("Pushing image '%s'", target))
+		if err := dockerClient.ImageTag(ctx, image, target); err != nil {
+			pushStep.Failure()
+			return errors.Wrapf(err, "Could not tag image '%s'", image)
+		}
+		if err := docker.PushImage(ctx, dockerClient, target); err != nil {
+			pushStep.Failure()
+			return err
+		}
+		pushStep.Success()
+	}
+	return nil
+}
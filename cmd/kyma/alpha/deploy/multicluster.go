@@ -0,0 +1,216 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/profiles"
+	"github.com/kyma-project/cli/internal/summary"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+)
+
+//clusterResult is the outcome of the deployment to one of several clusters
+type clusterResult struct {
+	cluster  cluster
+	summary  *nice.Summary
+	duration time.Duration
+	err      error
+}
+
+//deployClusters deploys Kyma with the same sources, components, and configuration values to several clusters in parallel.
+//The messages of each cluster are prefixed with the cluster name, the results of all clusters are printed at the end.
+func (cmd *command) deployClusters() error {
+	clusters, err := cmd.opts.clusters()
+	if err != nil {
+		return err
+	}
+
+	// the sources are resolved once for all clusters
+	if cmd.opts.Source != localSource {
+		removeWorkspace, err := cmd.resolveSources()
+		if err != nil {
+			return err
+		}
+		if removeWorkspace {
+			defer os.RemoveAll(cmd.opts.WorkspacePath)
+		}
+	}
+
+	kubeconfigDir, err := ioutil.TempDir("", "kyma-kubeconfigs-")
+	if err != nil {
+		return errors.Wrap(err, "Could not create temporary folder for kubeconfigs")
+	}
+	defer os.RemoveAll(kubeconfigDir)
+
+	var clusterCmds []*command
+	for i, c := range clusters {
+		clusterCmd, err := cmd.clusterCommand(c, filepath.Join(kubeconfigDir, fmt.Sprintf("cluster-%d.yaml", i)))
+		if err != nil {
+			return err
+		}
+		// the certificate files of a cluster take precedence over the generated certificate
+		if cmd.opts.GenerateCert && clusterCmd.opts.TLSCrtFile == "" {
+			if err := clusterCmd.generateCert(); err != nil {
+				return errors.Wrapf(err, "Could not generate the certificate of cluster '%s'", c.name())
+			}
+		}
+		clusterCmds = append(clusterCmds, clusterCmd)
+	}
+
+	results := make([]clusterResult, len(clusterCmds))
+	err = silenceStderr(cmd.Verbose, func() error {
+		var wg sync.WaitGroup
+		for i, clusterCmd := range clusterCmds {
+			wg.Add(1)
+			go func(i int, clusterCmd *command) {
+				defer wg.Done()
+				start := time.Now()
+				sum, err := clusterCmd.deployCluster()
+				results[i] = clusterResult{cluster: *clusterCmd.cluster, summary: sum, duration: time.Since(start), err: err}
+			}(i, clusterCmd)
+		}
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return printClusterResults(os.Stdout, results)
+}
+
+//clusterCommand creates the command which deploys to the cluster.
+//The kubeconfig of the cluster is written to the given file, which only contains the context of the cluster.
+func (cmd *command) clusterCommand(c cluster, kubeconfigFile string) (*command, error) {
+	kubeconfigPath := cmd.KubeconfigPath
+	if c.Kubeconfig != "" {
+		kubeconfigPath = c.Kubeconfig
+	}
+	kubeconfig, err := kube.ContextConfig(kubeconfigPath, c.Context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read the kubeconfig of cluster '%s'", c.name())
+	}
+	if err := ioutil.WriteFile(kubeconfigFile, kubeconfig, 0600); err != nil {
+		return nil, errors.Wrapf(err, "Could not write the kubeconfig of cluster '%s'", c.name())
+	}
+
+	// the steps of all clusters are printed in parallel: spinners and prompts are not possible
+	cliOpts := *cmd.opts.Options
+	cliOpts.KubeconfigPath = kubeconfigFile
+	cliOpts.Factory = step.Factory{NonInteractive: true, UseLogger: cmd.UseLogger, Prefix: fmt.Sprintf("[%s] ", c.name())}
+
+	// the settings of the cluster take precedence
+	opts := *cmd.opts
+	opts.Options = &cliOpts
+	opts.OverridesFiles = append(append([]string{}, cmd.opts.OverridesFiles...), c.ValuesFiles...)
+	opts.Overrides = append(append([]string{}, cmd.opts.Overrides...), c.Values...)
+	if c.Domain != "" {
+		opts.Domain = c.Domain
+	}
+	if c.TLSCrtFile != "" {
+		opts.TLSCrtFile, opts.TLSKeyFile = c.TLSCrtFile, c.TLSKeyFile
+	}
+	if c.Profile != "" {
+		opts.Profile = c.Profile
+		if err := opts.validateProfile(); err != nil {
+			return nil, errors.Wrapf(err, "Invalid profile of cluster '%s'", c.name())
+		}
+	}
+
+	return &command{
+		opts:    &opts,
+		Command: cli.Command{Options: &cliOpts},
+		bundle:  cmd.bundle,
+		cluster: &c,
+	}, nil
+}
+
+//deployCluster checks the cluster, deploys Kyma, and collects the deployment summary
+func (cmd *command) deployCluster() (*nice.Summary, error) {
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return nil, errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	if cmd.opts.Profile == profiles.Auto {
+		if err := cmd.selectProfile(); err != nil {
+			return nil, err
+		}
+	}
+	if !cmd.opts.SkipChecks {
+		if err := cmd.runPreflightChecks(); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.opts.Source != localSource {
+		if err := cmd.isCompatibleVersion(); err != nil {
+			return nil, err
+		}
+	}
+
+	overrides, err := cmd.kymaOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.deployKyma(overrides); err != nil {
+		return nil, err
+	}
+
+	o, err := overrides.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve overrides to collect the deployment summary")
+	}
+	domain, ok := o.Find("global.domainName")
+	if !ok {
+		return nil, errors.New("Domain not found in overrides")
+	}
+	return summary.Collect(cmd.K8s, cmd.KubeconfigPath, fmt.Sprintf("%v", domain))
+}
+
+//printClusterResults prints the outcome of the deployment to each cluster and returns an error if any deployment failed
+func printClusterResults(w io.Writer, results []clusterResult) error {
+	fmt.Fprintln(w)
+	writer := tablewriter.NewWriter(w)
+	writer.SetBorder(false)
+	writer.SetHeader([]string{"CLUSTER", "STATUS", "VERSION", "DOMAIN", "CONSOLE", "DURATION"})
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderLine(false)
+	writer.SetRowSeparator("")
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+
+	var failed []string
+	for _, result := range results {
+		duration := result.duration.Round(time.Second).String()
+		if result.err != nil {
+			failed = append(failed, result.cluster.name())
+			writer.Append([]string{result.cluster.name(), "Failed", "", "", "", duration})
+			continue
+		}
+		writer.Append([]string{result.cluster.name(), "Deployed", result.summary.Version, result.summary.URL, result.summary.Console, duration})
+	}
+	writer.Render()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(w, "[%s] %v\n", result.cluster.name(), result.err)
+		}
+	}
+	return fmt.Errorf("Kyma deployment failed on %d of %d clusters: %s", len(failed), len(results), strings.Join(failed, ", "))
+}
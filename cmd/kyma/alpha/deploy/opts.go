@@ -12,17 +12,21 @@ import (
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/files"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/profiles"
 )
 
 const (
 	quitTimeoutFactor = 1.25
+	jsonEventsOutput  = "json-events"
 )
 
+var supportedOutputs = []string{nice.JSONFormat, nice.YAMLFormat, jsonEventsOutput}
+
 var (
 	localSource           = "local"
 	defaultSource         = "main"
 	isRelease             = "false"
-	kymaProfiles          = []string{"evaluation", "production"}
 	defaultWorkspacePath  = getDefaultWorkspacePath()
 	defaultComponentsFile = filepath.Join(defaultWorkspacePath, "installation", "resources", "components.yaml")
 )
@@ -35,6 +39,9 @@ type Options struct {
 	Components       []string
 	OverridesFiles   []string
 	Overrides        []string
+	StringOverrides  []string
+	FileOverrides    []string
+	JSONOverrides    []string
 	Timeout          time.Duration
 	TimeoutComponent time.Duration
 	Concurrency      int
@@ -45,6 +52,23 @@ type Options struct {
 	Profile          string
 	Atomic           bool
 	ReuseHelmValues  bool
+	Validate         bool
+	SkipChecks       bool
+	Verify           bool
+	VerifyTimeout    time.Duration
+	TimingsFile      string
+	HooksFile        string
+	RenderTo         string
+	Diff             bool
+	Resume           bool
+	Bundle           string
+	ImageRegistry    string
+	Output           string
+	ShowSecrets      bool
+	KubeContexts     []string
+	ClustersFile     string
+	Watch            bool
+	GenerateCert     bool
 }
 
 //NewOptions creates options with default values
@@ -57,13 +81,53 @@ func (o *Options) QuitTimeout() time.Duration {
 	return time.Duration((o.Timeout.Seconds() * quitTimeoutFactor)) * time.Second
 }
 
-func (o *Options) supportedProfile(profile string) bool {
-	for _, supportedProfile := range kymaProfiles {
-		if supportedProfile == profile {
-			return true
-		}
+//validateProfile verifies that the profile is a built-in or user-defined profile, or selected automatically
+func (o *Options) validateProfile() error {
+	if o.Profile == profiles.Auto && o.renderOnly() {
+		return fmt.Errorf(`The profile "%s" is selected based on the cluster capacity and cannot be combined with the "render-to" flag`, profiles.Auto)
+	}
+	_, err := o.profileValuesFile()
+	return err
+}
+
+//chartProfile returns the built-in profile which is applied to the charts. User-defined profiles are applied on top of the default configuration.
+func (o *Options) chartProfile() string {
+	if profiles.IsBuiltIn(o.Profile) {
+		return o.Profile
+	}
+	return ""
+}
+
+//profileValuesFile returns the values file of a user-defined profile or an empty string for all other profiles
+func (o *Options) profileValuesFile() (string, error) {
+	if !profiles.IsUserDefined(o.Profile) {
+		return "", nil
+	}
+	dir, err := profiles.DefaultDir()
+	if err != nil {
+		return "", err
 	}
-	return false
+	return profiles.NewStore(dir).File(o.Profile)
+}
+
+//renderOnly returns true if the manifests are only rendered and Kyma is not deployed
+func (o *Options) renderOnly() bool {
+	return o.RenderTo != ""
+}
+
+//multiCluster returns true if Kyma is deployed to several clusters at once
+func (o *Options) multiCluster() bool {
+	return len(o.KubeContexts) > 0 || o.ClustersFile != ""
+}
+
+//jsonEvents returns true if the deployment events are written as JSON objects instead of rendering the UI
+func (o *Options) jsonEvents() bool {
+	return o.Output == jsonEventsOutput
+}
+
+//summaryOutput returns true if the deployment summary is written as JSON or YAML document
+func (o *Options) summaryOutput() bool {
+	return o.Output == nice.JSONFormat || o.Output == nice.YAMLFormat
 }
 
 //tlsCrtEnc returns the base64 encoded TLS certificate
@@ -128,12 +192,21 @@ func (o *Options) validateFlags() error {
 	if o.Timeout < o.TimeoutComponent {
 		return fmt.Errorf("Timeout (%v) cannot be smaller than component timeout (%v)", o.Timeout, o.TimeoutComponent)
 	}
-	if o.Profile != "" && !o.supportedProfile(o.Profile) {
-		return fmt.Errorf("Profile unknown or not supported. Supported profiles are: %s", strings.Join(kymaProfiles, ", "))
+	if o.Verify && o.VerifyTimeout <= 0 {
+		return fmt.Errorf("Verify timeout must be greater than 0")
+	}
+	if err := o.validateProfile(); err != nil {
+		return err
 	}
 	if _, err := o.tlsCertAndKeyProvided(); err != nil {
 		return err
 	}
+	if o.GenerateCert && (o.TLSCrtFile != "" || o.TLSKeyFile != "") {
+		return fmt.Errorf(`Provide either "generate-cert" or "tls-crt" and "tls-key" flags`)
+	}
+	if o.GenerateCert && o.Domain == "" && !o.multiCluster() {
+		return fmt.Errorf(`The "generate-cert" flag requires a custom domain ("domain" flag)`)
+	}
 	if o.WorkspacePath == "" {
 		o.WorkspacePath = defaultWorkspacePath
 	}
@@ -144,6 +217,53 @@ func (o *Options) validateFlags() error {
 	if o.ComponentsFile != defaultComponentsFile && len(o.Components) > 0 {
 		return fmt.Errorf(`Provide either "components-file" or "component" flag`)
 	}
+	if o.renderOnly() && o.Diff {
+		return fmt.Errorf(`Provide either "render-to" or "diff" flag`)
+	}
+	if o.Output != "" && !o.jsonEvents() && !o.summaryOutput() {
+		return fmt.Errorf("Output format '%s' is not supported. Supported output formats are: %s", o.Output, strings.Join(supportedOutputs, ", "))
+	}
+	if o.Output != "" && (o.renderOnly() || o.Diff) {
+		return fmt.Errorf(`The "output" flag cannot be combined with the "render-to" or "diff" flag`)
+	}
+	if strings.Contains(o.ImageRegistry, "://") {
+		return fmt.Errorf(`Provide the image registry without protocol, for example, "registry.corp.local/kyma"`)
+	}
+	if o.Resume && (o.renderOnly() || o.Diff || len(o.Components) > 0 || o.ComponentsFile != defaultComponentsFile) {
+		return fmt.Errorf(`The "resume" flag cannot be combined with the "render-to", "diff", "component", or "components-file" flag`)
+	}
+	if err := o.validateWatch(); err != nil {
+		return err
+	}
+	return o.validateMultiCluster()
+}
+
+//validateMultiCluster verifies that the options of a deployment to several clusters are consistent
+func (o *Options) validateMultiCluster() error {
+	if !o.multiCluster() {
+		return nil
+	}
+	if len(o.KubeContexts) > 0 && o.ClustersFile != "" {
+		return fmt.Errorf(`Provide either "kube-context" or "clusters-file" flag`)
+	}
+	if o.Resume || o.renderOnly() || o.Diff || o.Output != "" || o.TimingsFile != "" {
+		return fmt.Errorf(`Deploying to several clusters cannot be combined with the "resume", "render-to", "diff", "output", or "timings-file" flag`)
+	}
+	_, err := o.clusters()
+	return err
+}
+
+//validateWatch verifies that the local sources are deployed if they are watched for changes
+func (o *Options) validateWatch() error {
+	if !o.Watch {
+		return nil
+	}
+	if o.Source != localSource {
+		return fmt.Errorf(`The "watch" flag requires local Kyma sources ("--source=local")`)
+	}
+	if o.Resume || o.renderOnly() || o.Diff || o.Output != "" || o.Bundle != "" || o.multiCluster() {
+		return fmt.Errorf(`The "watch" flag cannot be combined with the "resume", "render-to", "diff", "output", "bundle", "kube-context", or "clusters-file" flag`)
+	}
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/pkg/errors"
@@ -167,6 +168,111 @@ func TestOptsValidation(t *testing.T) {
 		err := opts.validateFlags()
 		require.Error(t, err)
 	})
+	t.Run(`Only one of "render-to" and "diff" flags can be provided`, func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			RenderTo:   "path/to/manifests",
+			Diff:       true,
+		}
+		err := opts.validateFlags()
+		require.Error(t, err)
+	})
+	t.Run(`The "resume" flag cannot be combined with a component list`, func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			Components: []string{"comp1"},
+			Resume:     true,
+		}
+		err := opts.validateFlags()
+		require.Error(t, err)
+	})
+	t.Run("JSON events output", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			Output:     "json-events",
+		}
+		require.NoError(t, opts.validateFlags())
+		opts.Diff = true
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Summary output", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile:  crtFile,
+			TLSKeyFile:  keyFile,
+			Output:      "yaml",
+			ShowSecrets: true,
+		}
+		require.NoError(t, opts.validateFlags())
+		require.True(t, opts.summaryOutput())
+		require.False(t, opts.jsonEvents())
+	})
+	t.Run("Unsupported output format", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			Output:     "xml",
+		}
+		err := opts.validateFlags()
+		require.Error(t, err)
+	})
+	t.Run("Image registry with protocol", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile:    crtFile,
+			TLSKeyFile:    keyFile,
+			ImageRegistry: "https://registry.corp.local/kyma",
+		}
+		err := opts.validateFlags()
+		require.Error(t, err)
+	})
+	t.Run("Verification without timeout", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			Verify:     true,
+		}
+		require.Error(t, opts.validateFlags())
+		opts.VerifyTimeout = time.Minute
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("Multiple clusters", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile:   crtFile,
+			TLSKeyFile:   keyFile,
+			KubeContexts: []string{"staging-1", "staging-2"},
+		}
+		require.NoError(t, opts.validateFlags())
+		opts.ClustersFile = "clusters.yaml"
+		require.Error(t, opts.validateFlags())
+		opts.ClustersFile = ""
+		opts.Resume = true
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Generate certificate", func(t *testing.T) {
+		opts := &Options{
+			GenerateCert: true,
+		}
+		require.Error(t, opts.validateFlags())
+		opts.Domain = "kyma.example.com"
+		require.NoError(t, opts.validateFlags())
+		opts.TLSCrtFile, opts.TLSKeyFile = crtFile, keyFile
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Watch local sources", func(t *testing.T) {
+		opts := &Options{
+			TLSCrtFile: crtFile,
+			TLSKeyFile: keyFile,
+			Source:     "main",
+			Watch:      true,
+		}
+		require.Error(t, opts.validateFlags())
+		opts.Source = localSource
+		require.NoError(t, opts.validateFlags())
+		opts.Diff = true
+		require.Error(t, opts.validateFlags())
+	})
 }
 
 func TestComponentFile(t *testing.T) {
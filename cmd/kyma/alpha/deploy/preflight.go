@@ -0,0 +1,34 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/preflight"
+	"github.com/kyma-project/cli/internal/profiles"
+)
+
+//runPreflightChecks verifies that the cluster fulfills the requirements of the Kyma source and profile
+func (cmd *command) runPreflightChecks() error {
+	report, err := preflight.Run(cmd.K8s.Static(), preflight.Config{Source: cmd.opts.Source, Profile: cmd.opts.Profile})
+	if err != nil {
+		return err
+	}
+	report.PrintSteps(cmd.NewStep)
+	if report.Failed() {
+		return fmt.Errorf("At least one pre-flight check failed. Fix the cluster or set --skip-checks to deploy anyway")
+	}
+	return nil
+}
+
+//selectProfile replaces the "auto" profile by the built-in profile which fits the capacity of the cluster
+func (cmd *command) selectProfile() error {
+	selectStep := cmd.NewStep("Selecting profile based on the cluster capacity")
+	profile, capacity, err := profiles.Select(cmd.K8s.Static())
+	if err != nil {
+		selectStep.Failure()
+		return err
+	}
+	cmd.opts.Profile = profile
+	selectStep.Successf("Selected profile '%s' for a cluster with %s", profile, capacity)
+	return nil
+}
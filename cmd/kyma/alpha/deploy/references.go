@@ -0,0 +1,214 @@
+package deploy
+
+import (
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+//helmFuncs are the template functions which Helm adds to the sprig functions
+var helmFuncs = []string{"include", "tpl", "required", "lookup", "toYaml", "fromYaml", "fromYamlArray", "toJson", "fromJson", "fromJsonArray", "toToml"}
+
+//valueRef is a reference of a template to a configuration value, e.g. "global.tlsCrt" for ".Values.global.tlsCrt"
+type valueRef struct {
+	path string
+	//scope is true if the reference only selects the value for the following references,
+	//e.g. "with .Values.global" or "$global := .Values.global", so it does not use every value below it
+	scope bool
+}
+
+//uses returns true if the reference uses the value: the value itself, a part of it, or a map which contains it
+func (r valueRef) uses(value string) bool {
+	switch {
+	case r.path == value, strings.HasPrefix(r.path, value+"."):
+		return true
+	case r.scope:
+		return false
+	}
+	return r.path == "" || strings.HasPrefix(value, r.path+".")
+}
+
+//dotScope is what the dot or a variable refers to in a template
+type dotScope struct {
+	//root is true for the chart context, whose field "Values" contains the configuration values
+	root bool
+	//values is true for the configuration value with the path
+	values bool
+	path   string
+}
+
+//valueRefs returns the references of the template file (including its named templates) to configuration values.
+//Besides fields like ".Values.global.tlsCrt", it follows "index .Values.global "tlsCrt"" as well as the dot of "with" blocks
+//and variables, e.g. ".tlsCrt" in "with .Values.global". Named templates are assumed to be included with the chart context.
+func valueRefs(name, content string) ([]valueRef, error) {
+	funcs := sprig.TxtFuncMap()
+	for _, helmFunc := range helmFuncs {
+		funcs[helmFunc] = func(...interface{}) interface{} { return nil }
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []valueRef
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		w := &refWalker{vars: make(map[string]dotScope)}
+		w.walk(t.Tree.Root, dotScope{root: true})
+		refs = append(refs, w.refs...)
+	}
+	return refs, nil
+}
+
+//refWalker collects the references of a template to configuration values
+type refWalker struct {
+	refs []valueRef
+	vars map[string]dotScope
+}
+
+func (w *refWalker) walk(node parse.Node, dot dotScope) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		// a plain reference is written to the output, unless it is assigned to a variable
+		w.pipe(n.Pipe, dot, len(n.Pipe.Decl) == 0)
+	case *parse.IfNode:
+		w.pipe(n.Pipe, dot, false)
+		w.walk(n.List, dot)
+		w.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		inner, _ := w.pipe(n.Pipe, dot, false)
+		w.walk(n.List, inner)
+		w.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		// the dot and the variables of the loop are the items, which are used as a whole by the reference
+		w.pipe(n.Pipe, dot, true)
+		for _, decl := range n.Pipe.Decl {
+			delete(w.vars, decl.Ident[0])
+		}
+		w.walk(n.List, dotScope{})
+		w.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		w.pipe(n.Pipe, dot, true)
+	}
+}
+
+//pipe records the references of the pipeline. If the pipeline is a plain reference, e.g. ".Values.global", it returns what
+//the reference refers to; the reference uses the whole value only if it is written to the output.
+func (w *refWalker) pipe(pipe *parse.PipeNode, dot dotScope, output bool) (dotScope, bool) {
+	if pipe == nil {
+		return dotScope{}, false
+	}
+	if len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1 {
+		if target, ok := w.resolve(pipe.Cmds[0].Args[0], dot); ok {
+			if target.values {
+				w.refs = append(w.refs, valueRef{path: target.path, scope: !output})
+			}
+			w.declare(pipe.Decl, target)
+			return target, true
+		}
+	}
+	for _, cmd := range pipe.Cmds {
+		w.command(cmd, dot)
+	}
+	w.declare(pipe.Decl, dotScope{})
+	return dotScope{}, false
+}
+
+func (w *refWalker) command(cmd *parse.CommandNode, dot dotScope) {
+	args := cmd.Args
+	if ident, ok := args[0].(*parse.IdentifierNode); ok && ident.Ident == "index" && len(args) > 1 {
+		if target, ok := w.resolve(args[1], dot); ok && target.values {
+			// the keys which are string constants extend the path, all others select any value below it
+			var keys []string
+			for _, arg := range args[2:] {
+				key, ok := arg.(*parse.StringNode)
+				if !ok {
+					break
+				}
+				keys = append(keys, key.Text)
+			}
+			w.refs = append(w.refs, valueRef{path: joinPath(target.path, keys...)})
+			args = args[2:]
+		}
+	}
+	for _, arg := range args {
+		switch n := arg.(type) {
+		case *parse.PipeNode:
+			w.pipe(n, dot, true)
+		case *parse.ChainNode:
+			// e.g. "(.Values.global).tlsCrt"
+			if pipe, ok := n.Node.(*parse.PipeNode); ok {
+				if target, ok := w.pipe(pipe, dot, false); ok && target.values {
+					w.refs = append(w.refs, valueRef{path: joinPath(target.path, n.Field...)})
+				}
+			}
+		default:
+			if target, ok := w.resolve(arg, dot); ok && target.values {
+				w.refs = append(w.refs, valueRef{path: target.path})
+			}
+		}
+	}
+}
+
+//declare sets what the variables refer to
+func (w *refWalker) declare(decls []*parse.VariableNode, target dotScope) {
+	for _, decl := range decls {
+		if target.root || target.values {
+			w.vars[decl.Ident[0]] = target
+		} else {
+			delete(w.vars, decl.Ident[0])
+		}
+	}
+}
+
+//resolve returns what the dot, a field, or a variable refers to
+func (w *refWalker) resolve(node parse.Node, dot dotScope) (dotScope, bool) {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot, dot.root || dot.values
+	case *parse.FieldNode:
+		return follow(dot, n.Ident)
+	case *parse.VariableNode:
+		base, ok := w.vars[n.Ident[0]]
+		if n.Ident[0] == "$" {
+			base, ok = dotScope{root: true}, true
+		}
+		if !ok {
+			return dotScope{}, false
+		}
+		return follow(base, n.Ident[1:])
+	}
+	return dotScope{}, false
+}
+
+//follow returns what the fields of the base refer to
+func follow(base dotScope, fields []string) (dotScope, bool) {
+	switch {
+	case base.values:
+		return dotScope{values: true, path: joinPath(base.path, fields...)}, true
+	case base.root && len(fields) == 0:
+		return base, true
+	case base.root && fields[0] == "Values":
+		return dotScope{values: true, path: joinPath("", fields[1:]...)}, true
+	}
+	return dotScope{}, false
+}
+
+//joinPath appends the keys to the path of a configuration value
+func joinPath(path string, keys ...string) string {
+	if path != "" {
+		keys = append([]string{path}, keys...)
+	}
+	return strings.Join(keys, ".")
+}
@@ -0,0 +1,49 @@
+package deploy
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/render"
+	"github.com/pkg/errors"
+)
+
+//renderKyma renders the charts of all components with the merged overrides and stores the manifests locally
+func (cmd *command) renderKyma(overrides *overrides.Builder) error {
+	compList, err := cmd.createCompList()
+	if err != nil {
+		return err
+	}
+
+	renderer, err := cmd.renderer(overrides)
+	if err != nil {
+		return err
+	}
+
+	renderStep := cmd.NewStep(fmt.Sprintf("Rendering Kyma components into '%s'", cmd.opts.RenderTo))
+	manifests, err := renderer.RenderAll(compList)
+	if err != nil {
+		renderStep.Failure()
+		return err
+	}
+	if err := render.Write(cmd.opts.RenderTo, manifests); err != nil {
+		renderStep.Failure()
+		return err
+	}
+	renderStep.Successf("Rendered %d Kyma components into '%s'", len(manifests), cmd.opts.RenderTo)
+	return nil
+}
+
+//renderer creates a renderer which uses the same chart sources, profile and overrides as the deployment
+func (cmd *command) renderer(overrides *overrides.Builder) (*render.Renderer, error) {
+	o, err := overrides.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not build overrides")
+	}
+	return &render.Renderer{
+		ResourcePath: filepath.Join(cmd.opts.WorkspacePath, "resources"),
+		Profile:      cmd.opts.chartProfile(),
+		Overrides:    o.Map(),
+	}, nil
+}
@@ -0,0 +1,266 @@
+package deploy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const deploymentStateFileName = "deployment-state.yaml"
+
+type componentStatus string
+
+const (
+	componentPending  componentStatus = "pending"
+	componentDeployed componentStatus = "deployed"
+	componentFailed   componentStatus = "failed"
+)
+
+//componentState stores the outcome of a single component deployment
+type componentState struct {
+	Name         string          `yaml:"name"`
+	Namespace    string          `yaml:"namespace,omitempty"`
+	Prerequisite bool            `yaml:"prerequisite,omitempty"`
+	Status       componentStatus `yaml:"status"`
+}
+
+//deploymentState stores the settings and the per-component outcome of a deployment,
+//so that a failed deployment can be resumed with only the failed components
+type deploymentState struct {
+	Cluster        string           `yaml:"cluster"`
+	Source         string           `yaml:"source"`
+	Commit         string           `yaml:"commit,omitempty"`
+	Bundle         string           `yaml:"bundle,omitempty"`
+	WorkspacePath  string           `yaml:"workspacePath,omitempty"`
+	Profile        string           `yaml:"profile,omitempty"`
+	Domain         string           `yaml:"domain,omitempty"`
+	TLSCrtFile     string           `yaml:"tlsCrtFile,omitempty"`
+	TLSKeyFile     string           `yaml:"tlsKeyFile,omitempty"`
+	OverridesFiles []string         `yaml:"valuesFiles,omitempty"`
+	Overrides      []string         `yaml:"values,omitempty"`
+	StringValues   []string         `yaml:"stringValues,omitempty"`
+	FileValues     []string         `yaml:"fileValues,omitempty"`
+	JSONValues     []string         `yaml:"jsonValues,omitempty"`
+	ImageRegistry  string           `yaml:"imageRegistry,omitempty"`
+	HooksFile      string           `yaml:"hooksFile,omitempty"`
+	Components     []componentState `yaml:"components"`
+
+	file string
+	mu   sync.Mutex
+}
+
+//newDeploymentState creates a state where all components of the list are pending
+func newDeploymentState(file, cluster, commit string, opts *Options, compList *installConfig.ComponentList) *deploymentState {
+	state := &deploymentState{
+		Cluster:       cluster,
+		Source:        opts.Source,
+		Commit:        commit,
+		Bundle:        absPath(opts.Bundle),
+		Profile:       opts.Profile,
+		Domain:        opts.Domain,
+		TLSCrtFile:    absPath(opts.TLSCrtFile),
+		TLSKeyFile:    absPath(opts.TLSKeyFile),
+		Overrides:     opts.Overrides,
+		StringValues:  opts.StringOverrides,
+		JSONValues:    opts.JSONOverrides,
+		ImageRegistry: opts.ImageRegistry,
+		HooksFile:     absPath(opts.HooksFile),
+		file:          file,
+	}
+	if opts.Source == localSource && opts.Bundle == "" {
+		// remote sources are resolved again from the source cache
+		state.WorkspacePath = opts.WorkspacePath
+	}
+	for _, overridesFile := range opts.OverridesFiles {
+		state.OverridesFiles = append(state.OverridesFiles, absPath(overridesFile))
+	}
+	for _, fileValue := range opts.FileOverrides {
+		state.FileValues = append(state.FileValues, absFileValue(fileValue))
+	}
+	for _, comp := range compList.Prerequisites {
+		state.Components = append(state.Components, componentState{Name: comp.Name, Namespace: comp.Namespace, Prerequisite: true, Status: componentPending})
+	}
+	for _, comp := range compList.Components {
+		state.Components = append(state.Components, componentState{Name: comp.Name, Namespace: comp.Namespace, Status: componentPending})
+	}
+	return state
+}
+
+//absPath makes a local file path absolute so that it can be resolved from any working directory (URLs are not changed)
+func absPath(path string) string {
+	if path == "" || strings.Contains(path, "://") {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+//absFileValue makes the file paths of a value like 'component.key=path' absolute
+func absFileValue(value string) string {
+	var result []string
+	for _, item := range valueListSeparator.Split(strings.TrimSpace(value), -1) {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) == 2 {
+			item = parts[0] + "=" + absPath(parts[1])
+		}
+		result = append(result, item)
+	}
+	return strings.Join(result, ",")
+}
+
+//deploymentStateFile returns the path of the file which stores the state of the last deployment
+func deploymentStateFile() (string, error) {
+	kymaHome, err := files.KymaHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(kymaHome, deploymentStateFileName), nil
+}
+
+//loadDeploymentState reads the state of the last deployment from a file
+func loadDeploymentState(file string) (*deploymentState, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No failed deployment found which could be resumed")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read deployment state file '%s'", file)
+	}
+	state := &deploymentState{file: file}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse deployment state file '%s'", file)
+	}
+	return state, nil
+}
+
+//restoreOptions applies the settings of the stored deployment to the options.
+//Flags which were set explicitly on the command line are kept; the names of those whose value differs from the stored setting are returned.
+func (s *deploymentState) restoreOptions(opts *Options, changed func(flag string) bool) []string {
+	var conflicts []string
+	// stored paths are absolute, so the explicitly set values are normalized the same way before they are compared
+	restoreList := func(flag string, values *[]string, stored []string, normalize func(string) string) {
+		if !changed(flag) {
+			*values = stored
+			return
+		}
+		var current []string
+		for _, value := range *values {
+			if normalize != nil {
+				value = normalize(value)
+			}
+			current = append(current, value)
+		}
+		if strings.Join(current, "\n") != strings.Join(stored, "\n") {
+			conflicts = append(conflicts, flag)
+		}
+	}
+	restore := func(flag string, value *string, stored string, normalize func(string) string) {
+		values := []string{*value}
+		restoreList(flag, &values, []string{stored}, normalize)
+		*value = values[0]
+	}
+
+	restore("source", &opts.Source, s.Source, nil)
+	restore("bundle", &opts.Bundle, s.Bundle, absPath)
+	if s.WorkspacePath != "" {
+		restore("workspace", &opts.WorkspacePath, s.WorkspacePath, absPath)
+	}
+	restore("profile", &opts.Profile, s.Profile, nil)
+	restore("domain", &opts.Domain, s.Domain, nil)
+	restore("tls-crt", &opts.TLSCrtFile, s.TLSCrtFile, absPath)
+	restore("tls-key", &opts.TLSKeyFile, s.TLSKeyFile, absPath)
+	restoreList("values-file", &opts.OverridesFiles, s.OverridesFiles, absPath)
+	restoreList("value", &opts.Overrides, s.Overrides, nil)
+	restoreList("value-string", &opts.StringOverrides, s.StringValues, nil)
+	restoreList("value-file", &opts.FileOverrides, s.FileValues, absFileValue)
+	restoreList("value-json", &opts.JSONOverrides, s.JSONValues, nil)
+	restore("image-registry", &opts.ImageRegistry, s.ImageRegistry, nil)
+	restore("hooks-file", &opts.HooksFile, s.HooksFile, absPath)
+	return conflicts
+}
+
+//remainingComponents returns the list of all components which were not deployed successfully
+func (s *deploymentState) remainingComponents() *installConfig.ComponentList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	compList := &installConfig.ComponentList{}
+	for _, comp := range s.Components {
+		if comp.Status == componentDeployed {
+			continue
+		}
+		compDef := installConfig.ComponentDefinition{Name: comp.Name, Namespace: comp.Namespace}
+		if comp.Prerequisite {
+			compList.Prerequisites = append(compList.Prerequisites, compDef)
+		} else {
+			compList.Components = append(compList.Components, compDef)
+		}
+	}
+	return compList
+}
+
+//callback records the outcome of each deployed component and forwards the update to the next callback (if defined)
+func (s *deploymentState) callback(next func(deployment.ProcessUpdate)) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		if update.IsComponentUpdate() && (update.Phase == deployment.InstallPreRequisites || update.Phase == deployment.InstallComponents) {
+			status := componentDeployed
+			if update.Component.Status == components.StatusError {
+				status = componentFailed
+			}
+			s.setStatus(update.Component.Name, status)
+			// errors are ignored here: the state is stored again when the deployment failed
+			_ = s.save()
+		}
+		if next != nil {
+			next(update)
+		}
+	}
+}
+
+func (s *deploymentState) setStatus(name string, status componentStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.Components {
+		if s.Components[i].Name == name {
+			s.Components[i].Status = status
+		}
+	}
+}
+
+//save stores the state in its file
+func (s *deploymentState) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "Could not serialize deployment state")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0700); err != nil {
+		return errors.Wrapf(err, "Could not create directory for deployment state file '%s'", s.file)
+	}
+	if err := ioutil.WriteFile(s.file, data, 0600); err != nil {
+		return errors.Wrapf(err, "Could not write deployment state file '%s'", s.file)
+	}
+	return nil
+}
+
+//remove deletes the file of the state
+func (s *deploymentState) remove() error {
+	if err := os.Remove(s.file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Could not delete deployment state file '%s'", s.file)
+	}
+	return nil
+}
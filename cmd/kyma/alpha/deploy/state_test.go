@@ -0,0 +1,115 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-deployment-state-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, deploymentStateFileName)
+
+	compList := &installConfig.ComponentList{
+		Prerequisites: []installConfig.ComponentDefinition{
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+			{Name: "istio", Namespace: "istio-system"},
+		},
+		Components: []installConfig.ComponentDefinition{
+			{Name: "comp1", Namespace: "kyma-system"},
+			{Name: "comp2", Namespace: "kyma-system"},
+			{Name: "comp3", Namespace: "kyma-integration"},
+		},
+	}
+	opts := &Options{
+		Source:          "1.24.0",
+		Profile:         "evaluation",
+		Overrides:       []string{"comp1.a=1"},
+		StringOverrides: []string{"comp1.b=1.10"},
+		FileOverrides:   []string{"comp1.c=config.txt"},
+		ImageRegistry:   "registry.corp.local/kyma",
+	}
+
+	commit := "34edf09a1b2c3d4e5f60718293a4b5c6d7e8f901"
+	state := newDeploymentState(file, "https://cluster.example.com", commit, opts, compList)
+	callback := state.callback(nil)
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallPreRequisites,
+		Component: components.KymaComponent{Name: "cluster-essentials", Status: components.StatusInstalled},
+	})
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallPreRequisites,
+		Component: components.KymaComponent{Name: "istio", Status: components.StatusInstalled},
+	})
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallComponents,
+		Component: components.KymaComponent{Name: "comp1", Status: components.StatusInstalled},
+	})
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessExecutionFailure,
+		Phase:     deployment.InstallComponents,
+		Component: components.KymaComponent{Name: "comp2", Status: components.StatusError},
+	})
+
+	t.Run("Remaining components", func(t *testing.T) {
+		require.Equal(t, &installConfig.ComponentList{
+			Components: []installConfig.ComponentDefinition{
+				{Name: "comp2", Namespace: "kyma-system"},
+				{Name: "comp3", Namespace: "kyma-integration"},
+			},
+		}, state.remainingComponents())
+	})
+
+	t.Run("Load stored state", func(t *testing.T) {
+		loaded, err := loadDeploymentState(file)
+		require.NoError(t, err)
+		require.Equal(t, "https://cluster.example.com", loaded.Cluster)
+		require.Equal(t, state.remainingComponents(), loaded.remainingComponents())
+
+		require.Equal(t, commit, loaded.Commit)
+
+		restored := &Options{}
+		require.Empty(t, loaded.restoreOptions(restored, func(string) bool { return false }))
+		require.Equal(t, "1.24.0", restored.Source)
+		require.Equal(t, "evaluation", restored.Profile)
+		require.Equal(t, []string{"comp1.a=1"}, restored.Overrides)
+		require.Equal(t, []string{"comp1.b=1.10"}, restored.StringOverrides)
+		require.Equal(t, []string{"comp1.c=" + absPath("config.txt")}, restored.FileOverrides)
+		require.Equal(t, "registry.corp.local/kyma", restored.ImageRegistry)
+	})
+
+	t.Run("Explicitly set flags are kept", func(t *testing.T) {
+		loaded, err := loadDeploymentState(file)
+		require.NoError(t, err)
+
+		restored := &Options{
+			Profile:       "production",
+			FileOverrides: []string{"comp1.c=config.txt"},
+		}
+		changed := func(flag string) bool { return flag == "profile" || flag == "value-file" }
+		// the file value is the same as the stored one, only written with a relative path
+		require.Equal(t, []string{"profile"}, loaded.restoreOptions(restored, changed))
+		require.Equal(t, "production", restored.Profile)
+		require.Equal(t, []string{"comp1.c=config.txt"}, restored.FileOverrides)
+		require.Equal(t, "1.24.0", restored.Source)
+		require.Equal(t, []string{"comp1.a=1"}, restored.Overrides)
+	})
+
+	t.Run("Remove stored state", func(t *testing.T) {
+		require.NoError(t, state.remove())
+		_, err := loadDeploymentState(file)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No failed deployment found")
+	})
+}
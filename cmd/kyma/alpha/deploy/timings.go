@@ -0,0 +1,36 @@
+package deploy
+
+import (
+	"os"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+//reportTimings prints the timings of the deployment phases and components and writes them to the timings file (if set).
+//The table is not printed if the output is processed by machines.
+func (cmd *command) reportTimings() error {
+	if cmd.timings == nil {
+		return nil
+	}
+	report := cmd.timings.Report()
+	if len(report.Phases) == 0 {
+		// the deployment did not start
+		return nil
+	}
+	if cmd.opts.Output == "" {
+		report.Print(os.Stdout)
+	}
+	if cmd.opts.TimingsFile == "" {
+		return nil
+	}
+	return report.WriteTraceFile(cmd.opts.TimingsFile)
+}
+
+//componentNames returns the names of the components in the order they are deployed
+func componentNames(compDefs []installConfig.ComponentDefinition) []string {
+	var names []string
+	for _, compDef := range compDefs {
+		names = append(names, compDef.Name)
+	}
+	return names
+}
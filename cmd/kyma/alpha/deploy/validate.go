@@ -0,0 +1,122 @@
+package deploy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/schema"
+)
+
+//validateOverrides verifies the merged overrides against the default values and the values schemas of the component charts
+//so that wrong types are reported before the deployment starts. Unknown keys are only warnings, because templates can use keys without default values
+func (cmd *command) validateOverrides(overrides *overrides.Builder) error {
+	compList, err := cmd.createCompList()
+	if err != nil {
+		return err
+	}
+	sources, err := cmd.valueSources()
+	if err != nil {
+		return err
+	}
+
+	validateStep := cmd.NewStep("Validating configuration values")
+	renderer, err := cmd.renderer(overrides)
+	if err != nil {
+		validateStep.Failure()
+		return err
+	}
+	violations, err := schema.Validate(renderer, compList, sources)
+	if err != nil {
+		validateStep.Failure()
+		return err
+	}
+	var errs, warnings []string
+	for _, violation := range violations {
+		if violation.Warning {
+			warnings = append(warnings, violation.String())
+		} else {
+			errs = append(errs, violation.String())
+		}
+	}
+	if len(warnings) > 0 {
+		validateStep.LogInfof("The following configuration values are not defined by the component charts. Make sure they are used by the chart templates:\n  %s",
+			strings.Join(warnings, "\n  "))
+	}
+	if len(errs) > 0 {
+		validateStep.Failure()
+		return fmt.Errorf("The following configuration values do not match the component charts. Fix them or set --validate=false to skip the validation:\n  %s",
+			strings.Join(errs, "\n  "))
+	}
+	validateStep.Successf("Configuration values are valid")
+	return nil
+}
+
+//valueSources returns all sources of configuration values in the order in which they are applied
+func (cmd *command) valueSources() ([]schema.Source, error) {
+	var files []string
+	profileFile, err := cmd.opts.profileValuesFile()
+	if err != nil {
+		return nil, err
+	}
+	if profileFile != "" {
+		files = append(files, profileFile)
+	}
+	if cmd.bundle != nil {
+		files = append(files, cmd.bundle.ValuesFilePaths()...)
+	}
+	overridesFiles, err := cmd.opts.ResolveOverridesFiles()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, overridesFiles...)
+	files = append(files, filepath.Join(cmd.opts.WorkspacePath, kyma2OverridesPath))
+
+	var sources []schema.Source
+	for _, file := range files {
+		source, err := schema.FileSource(file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	if cmd.opts.Domain != "" {
+		sources = append(sources, globalSource("flag --domain", "domainName", cmd.opts.Domain))
+	}
+	//the TLS flags refer to files, the encoded file content is the value which is deployed
+	if cmd.opts.TLSCrtFile != "" {
+		tlsCrt, err := cmd.opts.tlsCrtEnc()
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, globalSource(fmt.Sprintf("flag --tls-crt (%s)", cmd.opts.TLSCrtFile), "tlsCrt", tlsCrt))
+	}
+	if cmd.opts.TLSKeyFile != "" {
+		tlsKey, err := cmd.opts.tlsKeyEnc()
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, globalSource(fmt.Sprintf("flag --tls-key (%s)", cmd.opts.TLSKeyFile), "tlsKey", tlsKey))
+	}
+
+	for _, flag := range cmd.opts.valueFlags() {
+		for _, value := range flag.values {
+			values := make(map[string]interface{})
+			if err := flag.parseInto(value, values); err != nil {
+				return nil, err
+			}
+			sources = append(sources, schema.Source{Name: fmt.Sprintf("flag --%s '%s'", flag.name, value), Values: values})
+		}
+	}
+	return sources, nil
+}
+
+//globalSource returns the source of a global value which is set by a flag
+func globalSource(name, key, value string) schema.Source {
+	return schema.Source{
+		Name:   name,
+		Values: map[string]interface{}{"global": map[string]interface{}{key: value}},
+	}
+}
@@ -0,0 +1,40 @@
+package deploy
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueSources(t *testing.T) {
+	workspace, err := ioutil.TempDir("", "kyma-validate-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workspace)
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "installation", "resources"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(workspace, kyma2OverridesPath), []byte("global: {}\n"), 0600))
+	crtFile := filepath.Join(workspace, "tls.crt")
+	require.NoError(t, ioutil.WriteFile(crtFile, []byte("certificate"), 0600))
+	keyFile := filepath.Join(workspace, "tls.key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("key"), 0600))
+
+	command := command{
+		opts: &Options{
+			WorkspacePath: workspace,
+			Domain:        "example.com",
+			TLSCrtFile:    crtFile,
+			TLSKeyFile:    keyFile,
+		},
+	}
+	sources, err := command.valueSources()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Source{
+		{Name: "flag --domain", Values: map[string]interface{}{"global": map[string]interface{}{"domainName": "example.com"}}},
+		{Name: "flag --tls-crt (" + crtFile + ")", Values: map[string]interface{}{"global": map[string]interface{}{"tlsCrt": base64.StdEncoding.EncodeToString([]byte("certificate"))}}},
+		{Name: "flag --tls-key (" + keyFile + ")", Values: map[string]interface{}{"global": map[string]interface{}{"tlsKey": base64.StdEncoding.EncodeToString([]byte("key"))}}},
+	}, sources[1:])
+}
@@ -0,0 +1,151 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+var (
+	//valueListSeparator matches the separator of comma-separated values including surrounding whitespaces
+	valueListSeparator = regexp.MustCompile(`\s*,\s*`)
+	//legacyKeyValueSeparator matches the whitespace separator of the 'key value' format supported by former versions
+	legacyKeyValueSeparator = regexp.MustCompile(`\s+`)
+)
+
+//valueFlag is a flag which sets configuration values
+type valueFlag struct {
+	name   string
+	values []string
+	parse  func(string, map[string]interface{}) error
+}
+
+//valueFlags returns the flags which set configuration values in the order in which they are applied
+func (o *Options) valueFlags() []valueFlag {
+	return []valueFlag{
+		{name: "value-json", values: o.JSONOverrides, parse: parseJSONValue},
+		{name: "value", values: o.Overrides, parse: parseTypedValue},
+		{name: "value-string", values: o.StringOverrides, parse: strvals.ParseIntoString},
+		{name: "value-file", values: o.FileOverrides, parse: o.parseFileValue},
+	}
+}
+
+//parseValues merges the values of all value flags into one map which contains a top-level key per component
+func (o *Options) parseValues() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, flag := range o.valueFlags() {
+		for _, value := range flag.values {
+			if err := flag.parseInto(value, values); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
+
+//parseInto parses a single value of the flag and merges it into the values
+func (f valueFlag) parseInto(value string, values map[string]interface{}) error {
+	if f.name == "value-json" {
+		// JSON literals are not normalized as they can contain commas and whitespaces
+		return parseValue(strings.TrimSpace(value), values, f.parse)
+	}
+	return parseValue(normalizeValue(value), values, f.parse)
+}
+
+//parseValue parses a single flag value and merges it into the values. Each key has to contain at least the
+//component name and one chart value, otherwise an error is returned.
+func parseValue(value string, values map[string]interface{}, parse func(string, map[string]interface{}) error) error {
+	if value == "" {
+		return fmt.Errorf("Value is empty: use the format 'component.key=value'")
+	}
+
+	// parse the value separately to verify its keys without being affected by other values
+	parsed := make(map[string]interface{})
+	if err := parse(value, parsed); err != nil {
+		return errors.Wrapf(err, "Could not parse value '%s'", value)
+	}
+	for comp, compValues := range parsed {
+		if _, ok := compValues.(map[string]interface{}); !ok {
+			return fmt.Errorf("Could not parse value '%s': key '%s' has to contain the component name and at least one chart value (e.g. '%s.key=value')", value, comp, comp)
+		}
+	}
+
+	return parse(value, values)
+}
+
+//normalizeValue removes whitespaces around list separators and converts the 'key value' format to 'key=value'
+func normalizeValue(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "=") {
+		if loc := legacyKeyValueSeparator.FindStringIndex(value); loc != nil {
+			value = value[:loc[0]] + "=" + value[loc[1]:]
+		}
+	}
+	return valueListSeparator.ReplaceAllString(value, ",")
+}
+
+//parseTypedValue parses values like Helm's "--set" flag: booleans, integers and null are converted to their types
+func parseTypedValue(value string, values map[string]interface{}) error {
+	parsed := make(map[string]interface{})
+	if err := strvals.ParseInto(value, parsed); err != nil {
+		return err
+	}
+	if key, ok := emptyValueKey(parsed); ok {
+		return fmt.Errorf("key '%s' has no value. Use the \"value-string\" flag to set an empty string", key)
+	}
+	return strvals.ParseInto(value, values)
+}
+
+//emptyValueKey returns the key of the first empty string value
+func emptyValueKey(values map[string]interface{}) (string, bool) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				return key, true
+			}
+		case map[string]interface{}:
+			if nested, ok := emptyValueKey(v); ok {
+				return key + "." + nested, true
+			}
+		}
+	}
+	return "", false
+}
+
+//parseJSONValue parses a value of the format 'key=JSON'. The JSON literal can be an object, a list or a scalar.
+func parseJSONValue(value string, values map[string]interface{}) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("use the format 'component.key=JSON'")
+	}
+	var literal interface{}
+	if err := json.Unmarshal([]byte(parts[1]), &literal); err != nil {
+		return errors.Wrapf(err, "key '%s' has no valid JSON value", parts[0])
+	}
+	// the key syntax (including list indexes) is resolved like for all other values
+	return strvals.ParseIntoFile(parts[0]+"=-", values, func([]rune) (interface{}, error) {
+		return literal, nil
+	})
+}
+
+//parseFileValue parses a value of the format 'key=path' and sets the content of the file as string value
+func (o *Options) parseFileValue(value string, values map[string]interface{}) error {
+	return strvals.ParseIntoFile(value, values, func(rs []rune) (interface{}, error) {
+		file, err := download.GetFile(string(rs), o.workspaceTmpDir())
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read value file '%s'", file)
+		}
+		return string(content), nil
+	})
+}
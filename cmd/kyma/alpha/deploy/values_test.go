@@ -0,0 +1,117 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValues(t *testing.T) {
+	t.Run("Typed values", func(t *testing.T) {
+		opts := &Options{
+			Overrides: []string{"comp.enabled=false,comp.replicas=3", "comp.name=test", "comp.empty=null"},
+		}
+		values, err := opts.parseValues()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"comp": map[string]interface{}{
+				"enabled":  false,
+				"replicas": int64(3),
+				"name":     "test",
+				"empty":    nil,
+			},
+		}, values)
+	})
+
+	t.Run("List values", func(t *testing.T) {
+		opts := &Options{
+			Overrides: []string{"comp.hosts={a, b}", "comp.ports[0].name=http,comp.ports[0].port=80", "comp.ports[1].name=https"},
+		}
+		values, err := opts.parseValues()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"comp": map[string]interface{}{
+				"hosts": []interface{}{"a", "b"},
+				"ports": []interface{}{
+					map[string]interface{}{"name": "http", "port": int64(80)},
+					map[string]interface{}{"name": "https"},
+				},
+			},
+		}, values)
+	})
+
+	t.Run("String values overrule typed values", func(t *testing.T) {
+		opts := &Options{
+			Overrides:       []string{"comp.version=1", "comp.enabled=true"},
+			StringOverrides: []string{"comp.version=1.10", "comp.empty="},
+		}
+		values, err := opts.parseValues()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"comp": map[string]interface{}{
+				"version": "1.10",
+				"enabled": true,
+				"empty":   "",
+			},
+		}, values)
+	})
+
+	t.Run("File values", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "kyma-value-file-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "config.txt")
+		require.NoError(t, ioutil.WriteFile(file, []byte("line1\nline2\n"), 0600))
+
+		opts := &Options{
+			FileOverrides: []string{"comp.config=" + file},
+		}
+		values, err := opts.parseValues()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"comp": map[string]interface{}{"config": "line1\nline2\n"},
+		}, values)
+
+		opts.FileOverrides = []string{"comp.config=" + filepath.Join(dir, "missing.txt")}
+		_, err = opts.parseValues()
+		require.Error(t, err)
+	})
+
+	t.Run("JSON values", func(t *testing.T) {
+		opts := &Options{
+			JSONOverrides: []string{`comp.resources={"limits": {"cpu": "100m", "memory": 128}}`, `comp.args[1]=["--a", "--b"]`},
+			Overrides:     []string{"comp.resources.limits.cpu=200m"},
+		}
+		values, err := opts.parseValues()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"comp": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "200m", "memory": float64(128)},
+				},
+				"args": []interface{}{nil, []interface{}{"--a", "--b"}},
+			},
+		}, values)
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opts *Options
+		}{
+			{"Missing chart value", &Options{Overrides: []string{"comp=true"}}},
+			{"Empty typed value", &Options{Overrides: []string{"comp.a.b="}}},
+			{"Malformed list index", &Options{Overrides: []string{"comp.ports[a]=80"}}},
+			{"Missing JSON key", &Options{JSONOverrides: []string{`={"a": 1}`}}},
+			{"Invalid JSON", &Options{JSONOverrides: []string{`comp.a={"a": 1`}}},
+			{"JSON without chart value", &Options{JSONOverrides: []string{`comp=[1, 2]`}}},
+		}
+		for _, tt := range tests {
+			_, err := tt.opts.parseValues()
+			require.Error(t, err, tt.name)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-project/cli/internal/verify"
+	"github.com/kyma-project/cli/pkg/asyncui"
+)
+
+//verifyKyma waits until the workloads, CRDs, and webhooks of all deployed components are healthy.
+//The progress is reported as additional phase of the deployment.
+func (cmd *command) verifyKyma(compList *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+	if callback == nil {
+		// in verbose mode the deployment has no UI, but the verification is rendered anyway
+		ui := asyncui.AsyncUI{StepFactory: &cmd.Factory}
+		callback = ui.Callback()
+	}
+	if cmd.timings != nil {
+		callback = cmd.timings.Callback(callback)
+	}
+
+	var releases []verify.Release
+	compDefs := append([]installConfig.ComponentDefinition{}, compList.Prerequisites...)
+	for _, comp := range append(compDefs, compList.Components...) {
+		releases = append(releases, verify.Release{Name: comp.Name, Namespace: comp.Namespace})
+	}
+
+	callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: asyncui.VerifyComponents})
+	verifier := verify.New(cmd.K8s.Static(), cmd.K8s.Dynamic())
+	results, err := verifier.Wait(releases, cmd.opts.VerifyTimeout, func(release verify.Release) {
+		callback(componentUpdate(release, components.StatusInstalled, nil))
+	})
+	if err == nil && len(results) > 0 {
+		var names []string
+		for _, result := range results {
+			var problems []string
+			for _, problem := range result.Problems {
+				problems = append(problems, problem.String())
+			}
+			callback(componentUpdate(result.Release, components.StatusError, fmt.Errorf("%s", strings.Join(problems, "\n"))))
+			names = append(names, result.Release.Name)
+		}
+		err = fmt.Errorf("Components not healthy after %s: %s", cmd.opts.VerifyTimeout, strings.Join(names, ", "))
+	}
+	if err != nil {
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessExecutionFailure, Phase: asyncui.VerifyComponents, Error: err})
+		return err
+	}
+	callback(deployment.ProcessUpdate{Event: deployment.ProcessFinished, Phase: asyncui.VerifyComponents})
+	return nil
+}
+
+//componentUpdate creates the process update which reports the health of a component
+func componentUpdate(release verify.Release, status string, err error) deployment.ProcessUpdate {
+	return deployment.ProcessUpdate{
+		Event: deployment.ProcessRunning,
+		Phase: asyncui.VerifyComponents,
+		Component: components.KymaComponent{
+			Name:      release.Name,
+			Namespace: release.Namespace,
+			Status:    status,
+			Error:     err,
+		},
+	}
+}
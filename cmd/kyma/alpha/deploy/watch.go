@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/watch"
+)
+
+//watchSources re-deploys the components whose local charts or component list entries changed until the command is interrupted.
+//The re-deployments reuse the configuration values of the first deployment and skip the pre-flight checks.
+func (cmd *command) watchSources(overrides *overrides.Builder) error {
+	watched, err := cmd.createCompList()
+	if err != nil {
+		return err
+	}
+	compFile, err := cmd.opts.ResolveComponentsFile()
+	if err != nil {
+		return err
+	}
+	resourcesDir := filepath.Join(cmd.opts.WorkspacePath, "resources")
+	watcher, err := watch.New(resourcesDir, compFile)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for {
+		fmt.Fprintf(cmd.messageWriter(), "\nWatching the charts in '%s' for changes. Press Ctrl+C to stop.\n", resourcesDir)
+		change, err := watcher.Next(context.Background())
+		if err != nil {
+			return err
+		}
+
+		// a changed component list is only relevant if the components were not selected with the "component" flag
+		var reloaded *installConfig.ComponentList
+		if change.ComponentList && len(cmd.opts.Components) == 0 {
+			if reloaded, err = installConfig.NewComponentList(compFile); err != nil {
+				fmt.Fprintf(cmd.messageWriter(), "Could not read the component list: %s\n", err)
+				continue
+			}
+		}
+		changed := changedComponents(watched, reloaded, change.Components)
+		if reloaded != nil {
+			watched = reloaded
+		}
+		if len(changed.Prerequisites) == 0 && len(changed.Components) == 0 {
+			continue
+		}
+
+		names := append(componentNames(changed.Prerequisites), componentNames(changed.Components)...)
+		fmt.Fprintf(cmd.messageWriter(), "Re-deploying %s\n", strings.Join(names, ", "))
+		cmd.components = changed
+		// a failed deployment does not stop the watch: the next change of the charts can fix it
+		if err := cmd.deployKyma(overrides); err != nil {
+			fmt.Fprintf(cmd.messageWriter(), "Deployment failed: %s\n", err)
+		}
+	}
+}
+
+//changedComponents returns the watched components whose charts changed and the components which were added to
+//or moved to another namespace in the reloaded component list (if the component list changed)
+func changedComponents(watched, reloaded *installConfig.ComponentList, charts []string) *installConfig.ComponentList {
+	changed := &installConfig.ComponentList{}
+	isChanged := func(compDef installConfig.ComponentDefinition) bool {
+		for _, chart := range charts {
+			if chart == compDef.Name {
+				return true
+			}
+		}
+		if reloaded == nil {
+			return false
+		}
+		oldDefs := append([]installConfig.ComponentDefinition{}, watched.Prerequisites...)
+		for _, oldDef := range append(oldDefs, watched.Components...) {
+			if oldDef.Name == compDef.Name {
+				return oldDef.Namespace != compDef.Namespace
+			}
+		}
+		return true
+	}
+
+	compList := watched
+	if reloaded != nil {
+		compList = reloaded
+	}
+	for _, compDef := range compList.Prerequisites {
+		if isChanged(compDef) {
+			changed.Prerequisites = append(changed.Prerequisites, compDef)
+		}
+	}
+	for _, compDef := range compList.Components {
+		if isChanged(compDef) {
+			changed.Components = append(changed.Components, compDef)
+		}
+	}
+	return changed
+}
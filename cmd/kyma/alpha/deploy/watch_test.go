@@ -0,0 +1,44 @@
+package deploy
+
+import (
+	"testing"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedComponents(t *testing.T) {
+	watched := &installConfig.ComponentList{
+		Prerequisites: []installConfig.ComponentDefinition{{Name: "cluster-essentials", Namespace: "kyma-system"}},
+		Components: []installConfig.ComponentDefinition{
+			{Name: "eventing", Namespace: "kyma-system"},
+			{Name: "ory", Namespace: "kyma-system"},
+		},
+	}
+
+	t.Run("Changed charts", func(t *testing.T) {
+		changed := changedComponents(watched, nil, []string{"cluster-essentials", "ory", "unknown"})
+		require.Equal(t, watched.Prerequisites, changed.Prerequisites)
+		require.Equal(t, []installConfig.ComponentDefinition{{Name: "ory", Namespace: "kyma-system"}}, changed.Components)
+	})
+
+	t.Run("Changed component list", func(t *testing.T) {
+		reloaded := &installConfig.ComponentList{
+			Prerequisites: watched.Prerequisites,
+			Components: []installConfig.ComponentDefinition{
+				{Name: "eventing", Namespace: "kyma-system"},
+				{Name: "ory", Namespace: "ory"},
+				{Name: "serverless", Namespace: "kyma-system"},
+			},
+		}
+		changed := changedComponents(watched, reloaded, []string{"eventing"})
+		require.Empty(t, changed.Prerequisites)
+		require.Equal(t, reloaded.Components, changed.Components)
+	})
+
+	t.Run("Nothing changed", func(t *testing.T) {
+		changed := changedComponents(watched, watched, nil)
+		require.Empty(t, changed.Prerequisites)
+		require.Empty(t, changed.Components)
+	})
+}
@@ -0,0 +1,18 @@
+package hosts
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new hosts command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hosts",
+		Short: "Manages the entries of the Kyma hostnames in the hosts file.",
+		Long: `Use this command to manage the entries which map the Kyma hostnames to the IP of the cluster in the hosts file of your machine.
+
+The entries are kept in a block which is delimited by "# BEGIN Kyma managed hosts" and "# END Kyma managed hosts" comments.
+Other entries of the hosts file are never changed. Changing the hosts file requires administrator permissions, so you might be prompted for your password.`,
+	}
+	return cmd
+}
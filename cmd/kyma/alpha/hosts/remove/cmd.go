@@ -0,0 +1,52 @@
+package remove
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/hosts"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new remove command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Removes the Kyma entries from the hosts file.",
+		Long:  `Use this command to remove the block of Kyma managed entries from the hosts file. All other entries are kept.`,
+		RunE:  func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	file := hosts.NewFile()
+	// create a simple step without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+	}
+	removeStep := f.NewStep(fmt.Sprintf("Removing the Kyma entries from '%s'", file.Path))
+	removed, err := file.Remove()
+	if err != nil {
+		removeStep.Failure()
+		return err
+	}
+	if removed {
+		removeStep.Successf("Kyma entries removed")
+	} else {
+		removeStep.Successf("The hosts file has no Kyma entries")
+	}
+	return nil
+}
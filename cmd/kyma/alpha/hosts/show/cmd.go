@@ -0,0 +1,47 @@
+package show
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/hosts"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new show command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Shows the Kyma entries of the hosts file.",
+		Long:  `Use this command to show the hostnames which are mapped in the block of Kyma managed entries of the hosts file.`,
+		RunE:  func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	file := hosts.NewFile()
+	ip, hostnames, err := file.Managed()
+	if err != nil {
+		return err
+	}
+	if ip == "" {
+		fmt.Printf("No Kyma entries found in '%s'\n", file.Path)
+		return nil
+	}
+	for _, hostname := range hostnames {
+		fmt.Printf("%s %s\n", ip, hostname)
+	}
+	return nil
+}
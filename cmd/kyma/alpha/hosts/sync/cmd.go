@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/hosts"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new sync command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Adds the hostnames of the Kyma virtual services to the hosts file.",
+		Long: `Use this command to map the hostnames of all virtual services of the cluster to an IP in the hosts file.
+The entries replace the entries which were added before, so the command can be run as often as needed, for example, after new services are exposed.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.IP, "ip", hosts.DefaultIP, "IP the hostnames are mapped to, for example, the IP of the load balancer of a remote cluster")
+	cobraCmd.Flags().StringSliceVar(&o.Hostnames, "hostname", []string{}, "Additional hostnames which are mapped to the IP (e.g. --hostname local.kyma.dev)")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	hostnames, err := hosts.GetVirtualServiceHostnames(cmd.K8s)
+	if err != nil {
+		return errors.Wrap(err, "Could not read the hostnames of the virtual services")
+	}
+	hostnames = hosts.Hostnames(append(hostnames, cmd.opts.Hostnames...))
+	if len(hostnames) == 0 {
+		return fmt.Errorf("No hostnames found in the virtual services of the cluster")
+	}
+
+	file := hosts.NewFile()
+	// create a simple step without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+	}
+	syncStep := f.NewStep(fmt.Sprintf("Updating the Kyma entries in '%s'", file.Path))
+	changed, err := file.Sync(cmd.opts.IP, hostnames)
+	if err != nil {
+		syncStep.Failure()
+		return err
+	}
+	if changed {
+		syncStep.Successf("Mapped %d hostnames to %s", len(hostnames), cmd.opts.IP)
+	} else {
+		syncStep.Successf("The hosts file is up to date (%d hostnames mapped to %s)", len(hostnames), cmd.opts.IP)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package sync
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	IP        string
+	Hostnames []string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if net.ParseIP(o.IP) == nil {
+		return fmt.Errorf("'%s' is not a valid IP address", o.IP)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package images
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new images command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "Manages the container images of Kyma.",
+		Long: `Use this command to list the container images referenced by the Kyma components and to copy them to a private registry.
+
+To deploy Kyma with the images of a private registry, use "kyma alpha deploy --image-registry".`,
+	}
+	return cmd
+}
@@ -0,0 +1,59 @@
+package list
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new list command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the container images of Kyma.",
+		Long: `Use this command to list all container images referenced by the Kyma components of the given source.
+
+The components are rendered with the given components file, values files, and profile, so the list contains exactly the images a deployment with the same settings would pull.
+
+Usage Examples:
+  List the images of Kyma 2.0.0:
+		kyma alpha images list --source 2.0.0
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	o.SourceOptions.AddFlags(cobraCmd)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.SourceOptions.Validate(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	if cmd.opts.Verbose {
+		cmd.Factory.UseLogger = true
+	}
+
+	imgs, err := cmd.opts.SourceOptions.CollectImages(&cmd.Command)
+	if err != nil {
+		return err
+	}
+	for _, image := range imgs {
+		fmt.Println(image)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package list
+
+import (
+	"github.com/kyma-project/cli/cmd/kyma/alpha/images"
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	images.SourceOptions
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
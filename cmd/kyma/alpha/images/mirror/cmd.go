@@ -0,0 +1,100 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/images"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const mirrorTimeout = 60 * time.Minute
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new mirror command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Copies the container images of Kyma to a private registry.",
+		Long: `Use this command to copy all container images referenced by the Kyma components of the given source to a private registry.
+
+The images are pulled, tagged with the target registry, and pushed. The registry domain of each image is replaced by the target registry and the repository path is kept,
+for example, "eu.gcr.io/kyma-project/foo:1.0" is copied to "registry.corp.local/kyma/kyma-project/foo:1.0".
+This requires a running Docker daemon that is logged in to the target registry.
+
+Usage Examples:
+  Copy the images of Kyma 2.0.0 to a private registry and deploy Kyma with these images:
+		kyma alpha images mirror --source 2.0.0 --target-registry registry.corp.local/kyma
+		kyma alpha deploy --source 2.0.0 --image-registry registry.corp.local/kyma
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	o.SourceOptions.AddFlags(cobraCmd)
+	cobraCmd.Flags().StringVarP(&o.TargetRegistry, "target-registry", "r", "", `Registry the images are copied to, for example, "registry.corp.local/kyma"`)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	if cmd.opts.Verbose {
+		cmd.Factory.UseLogger = true
+	}
+
+	imgs, err := cmd.opts.SourceOptions.CollectImages(&cmd.Command)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return errors.Wrap(err, "Could not create Docker client")
+	}
+	dockerClient.NegotiateAPIVersion(ctx)
+
+	var copied int
+	for _, image := range imgs {
+		if images.InRegistry(image, cmd.opts.TargetRegistry) {
+			continue
+		}
+		target := images.Rewrite(image, cmd.opts.TargetRegistry)
+		mirrorStep := cmd.NewStep(fmt.Sprintf("Copying image '%s' to '%s'", image, target))
+		if err := docker.PullImage(ctx, dockerClient, image); err != nil {
+			mirrorStep.Failure()
+			return err
+		}
+		if err := dockerClient.ImageTag(ctx, image, target); err != nil {
+			mirrorStep.Failure()
+			return errors.Wrapf(err, "Could not tag image '%s'", image)
+		}
+		if err := docker.PushImage(ctx, dockerClient, target); err != nil {
+			mirrorStep.Failure()
+			return err
+		}
+		mirrorStep.Success()
+		copied++
+	}
+	fmt.Printf("\nCopied %d container images to registry '%s'\n", copied, cmd.opts.TargetRegistry)
+	return nil
+}
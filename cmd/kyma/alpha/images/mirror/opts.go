@@ -0,0 +1,35 @@
+package mirror
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/cmd/kyma/alpha/images"
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	images.SourceOptions
+	TargetRegistry string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+// validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if err := o.SourceOptions.Validate(); err != nil {
+		return err
+	}
+	if o.TargetRegistry == "" {
+		return fmt.Errorf(`Provide the registry the images are copied to with the "target-registry" flag`)
+	}
+	if strings.Contains(o.TargetRegistry, "://") {
+		return fmt.Errorf(`Provide the target registry without protocol, for example, "registry.corp.local/kyma"`)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/cmd/kyma/alpha/images"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Valid options", func(t *testing.T) {
+		opts := &Options{SourceOptions: images.SourceOptions{Source: "2.0.0"}, TargetRegistry: "registry.corp.local/kyma"}
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("Target registry is missing", func(t *testing.T) {
+		opts := &Options{SourceOptions: images.SourceOptions{Source: "2.0.0"}}
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Target registry with protocol", func(t *testing.T) {
+		opts := &Options{SourceOptions: images.SourceOptions{Source: "2.0.0"}, TargetRegistry: "https://registry.corp.local/kyma"}
+		require.Error(t, opts.validateFlags())
+	})
+	t.Run("Local source requires workspace", func(t *testing.T) {
+		opts := &Options{SourceOptions: images.SourceOptions{Source: "local"}, TargetRegistry: "registry.corp.local/kyma"}
+		require.Error(t, opts.validateFlags())
+		opts.WorkspacePath = "/path/to/kyma"
+		require.NoError(t, opts.validateFlags())
+	})
+}
@@ -0,0 +1,92 @@
+package images
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/images"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const localSource = "local"
+
+//SourceOptions defines the Kyma sources and configuration whose container images are processed
+type SourceOptions struct {
+	Source         string
+	WorkspacePath  string
+	ComponentsFile string
+	OverridesFiles []string
+	Profile        string
+}
+
+//AddFlags adds the flags of the source options to the command
+func (o *SourceOptions) AddFlags(cobraCmd *cobra.Command) {
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", "main", `Kyma source, for example, a release version ("2.0.0"), a branch ("main"), a commit ("34edf09a"), a pull request ("PR-9486"), or the local sources ("local")`)
+	cobraCmd.Flags().StringVarP(&o.WorkspacePath, "workspace", "w", "", `Path to the local Kyma sources (required if "--source=local" is used)`)
+	cobraCmd.Flags().StringVarP(&o.ComponentsFile, "components-file", "c", "", "Path to the components file (default: the components file of the Kyma sources)")
+	cobraCmd.Flags().StringSliceVarP(&o.OverridesFiles, "values-file", "f", []string{}, "Path(s) to one or more JSON or YAML files with configuration values")
+	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "p", "", "Kyma deployment profile used to render the components")
+}
+
+//Validate applies a sanity check on the source options
+func (o *SourceOptions) Validate() error {
+	if o.Source == "" {
+		return fmt.Errorf("Provide the Kyma source")
+	}
+	if o.Source == localSource && o.WorkspacePath == "" {
+		return fmt.Errorf(`Provide the path to the local Kyma sources with the "workspace" flag`)
+	}
+	return nil
+}
+
+//CollectImages renders all components of the Kyma sources and returns the referenced container images
+func (o *SourceOptions) CollectImages(cmd *cli.Command) ([]string, error) {
+	sourcesPath := o.WorkspacePath
+	if o.Source != localSource {
+		cacheDir, err := sources.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		sourcesStep := cmd.NewStep(fmt.Sprintf("Resolving Kyma sources (%s)", o.Source))
+		src, _, err := sources.NewCache(cacheDir, sources.KymaRepositoryURL).Get(o.Source)
+		if err != nil {
+			sourcesStep.Failure()
+			return nil, err
+		}
+		sourcesStep.Successf("Using Kyma sources of commit %s", src.Commit[:8])
+		sourcesPath = src.Path
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kyma-images-")
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create temporary folder")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	imagesStep := cmd.NewStep("Collecting container images of Kyma components")
+	componentsFile := filepath.Join(sourcesPath, "installation", "resources", "components.yaml")
+	if o.ComponentsFile != "" {
+		if componentsFile, err = download.GetFile(o.ComponentsFile, tmpDir); err != nil {
+			imagesStep.Failure()
+			return nil, err
+		}
+	}
+	valuesFiles, err := download.GetFiles(o.OverridesFiles, tmpDir)
+	if err != nil {
+		imagesStep.Failure()
+		return nil, err
+	}
+	imgs, err := images.CollectFromSources(sourcesPath, componentsFile, valuesFiles, o.Profile)
+	if err != nil {
+		imagesStep.Failure()
+		return nil, err
+	}
+	imagesStep.Successf("Found %d container images", len(imgs))
+	return imgs, nil
+}
@@ -11,13 +11,19 @@ import (
 	"github.com/kyma-project/cli/internal/clusterinfo"
 	"github.com/kyma-project/cli/internal/k3s"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/kyma-project/cli/pkg/step"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+//localDomain is the domain of Kyma on a local k3s cluster
+const localDomain = "local.kyma.dev"
+
 type command struct {
 	opts *Options
 	cli.Command
+	clusterDeleted bool
 }
 
 //NewCmd creates a new k3s command
@@ -59,6 +65,9 @@ func (c *command) Run() error {
 	if err := c.verifyK3sStatus(); err != nil {
 		return err
 	}
+	if c.clusterDeleted {
+		c.removeTrustedCertificates()
+	}
 	if err := c.createK3sCluster(); err != nil {
 		return err
 	}
@@ -128,11 +137,60 @@ func (c *command) deleteExistingK3sCluster() error {
 			return err
 		}
 		c.CurrentStep.Successf("Existing k3s cluster deleted")
+		c.clusterDeleted = true
 	}
 
 	return nil
 }
 
+//removeTrustedCertificates offers to remove the Kyma certificates of the deleted cluster from the trust store
+func (c *command) removeTrustedCertificates() {
+	if c.opts.NonInteractive { //removing certificates can require a sudo password
+		return
+	}
+	certifier := trust.NewCertifier(nil)
+	certs, err := certifier.ListCertificates()
+	if err != nil {
+		return
+	}
+	var clusterCerts []trust.TrustedCertificate
+	for _, cert := range certs {
+		if cert.MatchesDomain(localDomain) {
+			clusterCerts = append(clusterCerts, cert)
+		}
+	}
+	if len(clusterCerts) == 0 {
+		return
+	}
+
+	qRemoveCertsStep := c.NewStep("Remove Kyma certificates of the deleted cluster")
+	answer := qRemoveCertsStep.PromptYesNo(fmt.Sprintf("Do you want to remove the %d Kyma certificates of '%s' from the trust store? ", len(clusterCerts), localDomain))
+	qRemoveCertsStep.Success()
+	if !answer {
+		return
+	}
+
+	// create a simple step without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+		UseLogger:      c.UseLogger,
+	}
+	removed := make(map[string]bool)
+	for _, cert := range clusterCerts {
+		if removed[cert.Fingerprint] {
+			continue
+		}
+		s := f.NewStep(fmt.Sprintf("Removing the Kyma certificate '%s'", cert.Fingerprint[:16]))
+		if err := certifier.RemoveCertificate(cert.Fingerprint, s); err != nil {
+			// the new cluster can be provisioned anyway: the certificate can be removed later
+			s.Failuref("Could not remove the Kyma certificate, run \"kyma alpha trust remove %s\": %s", cert.Fingerprint[:16], err)
+			continue
+		}
+		removed[cert.Fingerprint] = true
+		s.Successf("Kyma certificate '%s' removed", cert.Fingerprint[:16])
+	}
+}
+
 //Check if a port is allocated
 func (c *command) allocatePorts(ports ...int) error {
 	for _, port := range ports {
@@ -0,0 +1,164 @@
+package prune
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/prune"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new prune command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes the leftovers of a deleted Kyma installation from the cluster.",
+		Long: `Use this command to bring a cluster back to a clean state after Kyma was deleted, so that Kyma can be installed again.
+
+The command finds the resources which Kyma leaves behind:
+- Kyma namespaces, including the ones stuck in Terminating, and the resources in them whose finalizers block the deletion
+- CustomResourceDefinitions of Kyma, together with their custom resources
+- Admission webhook configurations which call deleted services of Kyma
+- PersistentVolumes which were claimed in Kyma namespaces
+- ClusterRoles and ClusterRoleBindings of Kyma
+
+Only resources which carry the "kyma-project.io/installation" label or which were installed by a Helm release in a Kyma namespace belong to Kyma.
+Kyma namespaces are the namespaces with that label and the ones installed by a Helm release in another Kyma namespace.
+While an Istio control plane runs in the cluster (any "istiod" deployment, including revisioned ones), its namespace and the Istio resources of Kyma are kept.
+
+The finalizers of these resources are removed, because the controllers which would remove them were deleted with Kyma.
+Before anything is removed, the command lists the leftovers and asks for confirmation, unless you use the "ci" flag. To only list them, use the "dry-run" flag.
+The command refuses to run while Kyma is still installed. Delete Kyma with "kyma alpha delete" first.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Lists the leftovers of Kyma without removing them")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	var err error
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	if cmd.opts.Verbose {
+		cmd.Factory.UseLogger = true
+	}
+
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Cannot initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	if err := cmd.checkKymaDeleted(); err != nil {
+		return err
+	}
+
+	pruner := prune.New(cmd.K8s.Static(), cmd.K8s.Dynamic())
+	findStep := cmd.NewStep("Finding Kyma leftovers")
+	leftovers, err := pruner.Find()
+	if err != nil {
+		findStep.Failure()
+		return err
+	}
+	findStep.Successf("Found %d Kyma leftovers", len(leftovers))
+	if len(leftovers) == 0 {
+		fmt.Println("No Kyma leftovers found")
+		return nil
+	}
+
+	fmt.Printf("The following resources are left over in cluster '%s':\n", cmd.K8s.RestConfig().Host)
+	printLeftovers(leftovers)
+	if cmd.opts.DryRun {
+		return nil
+	}
+	if !cmd.approveRemoval(leftovers) {
+		return fmt.Errorf("User decided not to remove the Kyma leftovers")
+	}
+
+	for _, l := range leftovers {
+		s := cmd.NewStep(fmt.Sprintf("Removing %s '%s'", l.Kind, qualifiedName(l)))
+		if err := pruner.Remove(l); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Successf("%s '%s' removed", l.Kind, qualifiedName(l))
+	}
+	return nil
+}
+
+//checkKymaDeleted fails if Kyma is still installed, because its resources are no leftovers then
+func (cmd *command) checkKymaDeleted() error {
+	checkStep := cmd.NewStep("Checking that Kyma is deleted")
+	metaProv, err := helm.NewKymaMetadataProvider(installConfig.KubeconfigSource{
+		Path: kube.KubeconfigPath(cmd.KubeconfigPath),
+	})
+	if err != nil {
+		checkStep.Failure()
+		return err
+	}
+	versionSet, err := metaProv.Versions()
+	if err != nil {
+		checkStep.Failure()
+		return err
+	}
+	if versionSet.Count() > 0 {
+		checkStep.Failure()
+		return fmt.Errorf("Kyma is still installed (%s). Delete it with \"kyma alpha delete\" before pruning its leftovers", strings.Join(versionSet.Names(), ", "))
+	}
+	checkStep.Successf("Kyma is deleted")
+	return nil
+}
+
+//approveRemoval asks the user whether the leftovers should be removed
+func (cmd *command) approveRemoval(leftovers []prune.Leftover) bool {
+	if cmd.opts.CI {
+		return true
+	}
+	approveStep := cmd.NewStep("Confirm the removal")
+	defer approveStep.Success()
+	return approveStep.PromptYesNo(fmt.Sprintf("Do you want to remove %d Kyma leftovers and their finalizers? ", len(leftovers)))
+}
+
+//printLeftovers writes the leftovers as table to stdout
+func printLeftovers(leftovers []prune.Leftover) {
+	writer := tablewriter.NewWriter(os.Stdout)
+	writer.SetBorder(false)
+	writer.SetHeader([]string{"KIND", "NAMESPACE", "NAME", "REASON", "FINALIZERS"})
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderLine(false)
+	writer.SetRowSeparator("")
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+	writer.SetAutoWrapText(false)
+
+	for _, l := range leftovers {
+		writer.Append([]string{l.Kind, l.Namespace, l.Name, l.Reason, strings.Join(l.Finalizers, ", ")})
+	}
+	writer.Render()
+}
+
+func qualifiedName(l prune.Leftover) string {
+	if l.Namespace == "" {
+		return l.Name
+	}
+	return l.Namespace + "/" + l.Name
+}
@@ -0,0 +1,18 @@
+package sources
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new sources command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Manages the locally cached Kyma sources.",
+		Long: `Use this command to manage the Kyma sources that are cached locally.
+
+The "alpha deploy" command downloads the Kyma sources of each revision only once and keeps them in the "$HOME/.kyma/cache/sources" folder.
+The cached sources are reused by all deployments and upgrades to the same commit.`,
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package list
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new list command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists the locally cached Kyma sources.",
+		Long:    `Use this command to list the locally cached Kyma sources with their commit, the revisions resolved to this commit, the time of their last usage, and their size.`,
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+		Aliases: []string{"l"},
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	dir, err := sources.DefaultDir()
+	if err != nil {
+		return err
+	}
+	srcs, err := sources.NewCache(dir, sources.KymaRepositoryURL).List()
+	if err != nil {
+		return err
+	}
+
+	if len(srcs) == 0 {
+		fmt.Println("No cached Kyma sources found")
+		return nil
+	}
+
+	writer := tablewriter.NewWriter(os.Stdout)
+	writer.SetBorder(false)
+	writer.SetHeader([]string{"COMMIT", "REVISIONS", "LAST USED", "SIZE"})
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderLine(false)
+	writer.SetRowSeparator("")
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+
+	for _, src := range srcs {
+		size, err := src.Size()
+		if err != nil {
+			return err
+		}
+		writer.Append([]string{
+			src.Commit[:8],
+			strings.Join(src.Revisions, ", "),
+			src.LastUsed.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%.1f MB", float64(size)/(1024*1024)),
+		})
+	}
+	writer.Render()
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package prune
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new prune command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Deletes unused Kyma sources from the local cache.",
+		Long: `Use this command to delete the locally cached Kyma sources that were not used for a given time.
+Leftovers of interrupted downloads are deleted as well.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().DurationVar(&o.OlderThan, "older-than", 30*24*time.Hour, "Deletes all sources that were not used for the given duration")
+	cobraCmd.Flags().BoolVar(&o.All, "all", false, "Deletes all cached sources")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	dir, err := sources.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	unusedSince := time.Now().Add(-cmd.opts.OlderThan)
+	if cmd.opts.All {
+		unusedSince = time.Now()
+	}
+
+	pruneStep := cmd.NewStep("Deleting unused Kyma sources")
+	pruned, err := sources.NewCache(dir, sources.KymaRepositoryURL).Prune(unusedSince)
+	if err != nil {
+		pruneStep.Failure()
+		return err
+	}
+	pruneStep.Successf("Deleted %d cached Kyma sources", len(pruned))
+
+	for _, src := range pruned {
+		fmt.Printf("%s (%s)\n", src.Commit[:8], strings.Join(src.Revisions, ", "))
+	}
+	return nil
+}
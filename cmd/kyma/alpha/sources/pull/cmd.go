@@ -0,0 +1,62 @@
+package pull
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/sources"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new pull command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "pull REVISION...",
+		Short: "Downloads Kyma sources into the local cache.",
+		Long: `Use this command to download the Kyma sources of one or more revisions into the local cache, so that following deployments don't have to download them.
+
+A revision can be a release version (for example, "1.24.0"), a branch (for example, "main"), a commit (for example, "34edf09a"), or a pull request (for example, "PR-9486").
+Sources that are already cached are not downloaded again.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error { return cmd.Run(args) },
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run(revisions []string) error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	dir, err := sources.DefaultDir()
+	if err != nil {
+		return err
+	}
+	cache := sources.NewCache(dir, sources.KymaRepositoryURL)
+
+	for _, revision := range revisions {
+		pullStep := cmd.NewStep(fmt.Sprintf("Pulling Kyma sources (%s)", revision))
+		src, downloaded, err := cache.Get(revision)
+		if err != nil {
+			pullStep.Failure()
+			return err
+		}
+		if downloaded {
+			pullStep.Successf("Kyma sources (%s) downloaded into '%s'", revision, src.Path)
+		} else {
+			pullStep.Successf("Kyma sources (%s) already cached in '%s'", revision, src.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package summary
+
+import (
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/summary"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new summary command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Shows the summary of the Kyma deployment.",
+		Long: `Use this command to show the installed Kyma version, the Kyma domain, the console URL, and the admin credentials of the Kyma deployment on the cluster.
+
+This is the same information that "kyma alpha deploy" shows after a deployment. To process it in scripts, write it as JSON or YAML document with the --output flag.
+The admin password is only included in the document if you set the --show-secrets flag.
+
+Usage Examples:
+  Store the console URL in a variable:
+		CONSOLE_URL=$(kyma alpha summary -o json | jq -r .console)
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Output, "output", "o", "", `Output format of the summary. One of: "json", "yaml"`)
+	cobraCmd.Flags().BoolVar(&o.ShowSecrets, "show-secrets", false, `Includes the admin password in the summary written with "--output json" or "--output yaml"`)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if err := cmd.opts.validateFlags(); err != nil {
+		return err
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	sum, err := summary.Collect(cmd.K8s, cmd.KubeconfigPath, "")
+	if err != nil {
+		return err
+	}
+	sum.NonInteractive = cmd.NonInteractive
+
+	if cmd.opts.Output != "" {
+		return sum.PrintStructured(cmd.opts.Output, cmd.opts.ShowSecrets)
+	}
+	return sum.Print()
+}
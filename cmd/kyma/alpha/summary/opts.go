@@ -0,0 +1,28 @@
+package summary
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/nice"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	Output      string
+	ShowSecrets bool
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+// validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags() error {
+	if o.Output != "" && o.Output != nice.JSONFormat && o.Output != nice.YAMLFormat {
+		return fmt.Errorf("Output format '%s' is not supported. Supported output formats are: %s, %s", o.Output, nice.JSONFormat, nice.YAMLFormat)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Human-readable output", func(t *testing.T) {
+		opts := &Options{}
+		require.NoError(t, opts.validateFlags())
+	})
+	t.Run("Machine-readable output", func(t *testing.T) {
+		for _, output := range []string{"json", "yaml"} {
+			opts := &Options{Output: output, ShowSecrets: true}
+			require.NoError(t, opts.validateFlags())
+		}
+	})
+	t.Run("Unsupported output", func(t *testing.T) {
+		opts := &Options{Output: "json-events"}
+		require.Error(t, opts.validateFlags())
+	})
+}
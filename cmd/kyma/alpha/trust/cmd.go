@@ -0,0 +1,17 @@
+package trust
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new trust command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Manages the Kyma certificates in the trust store of your machine.",
+		Long: `Use this command to manage the Kyma certificates which were imported into the trusted root certificates of your operating system.
+
+Certificates are identified by their SHA-256 fingerprint. Changing the trust store requires administrator permissions, so you might be prompted for your password.`,
+	}
+	return cmd
+}
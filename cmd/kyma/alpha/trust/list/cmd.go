@@ -0,0 +1,74 @@
+package list
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new list command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the Kyma certificates in the trust store.",
+		Long: `Use this command to list the Kyma certificates in the trusted root certificates of your operating system.
+The fingerprint of a certificate, or its first characters, identifies the certificate for "kyma alpha trust remove".`,
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+		Aliases: []string{"ls"},
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Lists only the certificates issued for the domain (e.g. --domain local.kyma.dev)")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	certs, err := trust.NewCertifier(nil).ListCertificates()
+	if err != nil {
+		return err
+	}
+
+	writer := tablewriter.NewWriter(os.Stdout)
+	writer.SetBorder(false)
+	writer.SetHeader([]string{"FINGERPRINT", "SUBJECT", "EXPIRES", "LOCATION"})
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderLine(false)
+	writer.SetRowSeparator("")
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+
+	found := 0
+	for _, cert := range certs {
+		if cmd.opts.Domain != "" && !cert.MatchesDomain(cmd.opts.Domain) {
+			continue
+		}
+		expires := cert.NotAfter.Format("2006-01-02")
+		if cert.Expired() {
+			expires += " (expired)"
+		}
+		writer.Append([]string{cert.Fingerprint[:16], cert.Subject, expires, cert.Location})
+		found++
+	}
+
+	if found == 0 {
+		fmt.Println("No trusted Kyma certificates found")
+		return nil
+	}
+	writer.Render()
+	return nil
+}
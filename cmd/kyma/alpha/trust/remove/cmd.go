@@ -0,0 +1,134 @@
+package remove
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new remove command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "remove [FINGERPRINT...]",
+		Short: "Removes Kyma certificates from the trust store.",
+		Long: `Use this command to remove Kyma certificates from the trusted root certificates of your operating system.
+
+Identify the certificates by their fingerprints as shown by "kyma alpha trust list". The first 8 characters of a fingerprint are enough if they are unique.
+Alternatively, select the certificates with the "all", "expired", or "domain" flag. Before selected certificates are removed, you are asked for confirmation unless you use the "ci" flag.`,
+		RunE:    func(_ *cobra.Command, args []string) error { return cmd.Run(args) },
+		Aliases: []string{"rm"},
+	}
+
+	cobraCmd.Flags().BoolVar(&o.All, "all", false, "Removes all Kyma certificates")
+	cobraCmd.Flags().BoolVar(&o.Expired, "expired", false, "Removes the expired Kyma certificates")
+	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Removes the Kyma certificates issued for the domain (e.g. --domain local.kyma.dev)")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run(fingerprints []string) error {
+	if err := cmd.opts.validateFlags(fingerprints); err != nil {
+		return err
+	}
+
+	certifier := trust.NewCertifier(nil)
+	certs, err := certifier.ListCertificates()
+	if err != nil {
+		return err
+	}
+	selected, err := cmd.selectCertificates(certs, fingerprints)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("No trusted Kyma certificates to remove")
+		return nil
+	}
+
+	if cmd.opts.selects() && !cmd.approveRemoval(selected) {
+		return fmt.Errorf("User decided not to remove the Kyma certificates")
+	}
+
+	// create a simple step without a spinner (spinner overwrites sudo prompt)
+	f := step.Factory{
+		NonInteractive: true,
+		UseLogger:      cmd.UseLogger,
+	}
+	for _, cert := range selected {
+		s := f.NewStep(fmt.Sprintf("Removing the Kyma certificate '%s'", cert.Fingerprint[:16]))
+		// removing a certificate can remove other selected certificates as well (e.g. a certificate chain stored in one file)
+		if remaining, err := certifier.ListCertificates(); err == nil && !contains(remaining, cert.Fingerprint) {
+			s.Successf("Kyma certificate '%s' already removed", cert.Fingerprint[:16])
+			continue
+		}
+		if err := certifier.RemoveCertificate(cert.Fingerprint, s); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Successf("Kyma certificate '%s' removed", cert.Fingerprint[:16])
+	}
+	return nil
+}
+
+//approveRemoval asks the user whether the selected certificates should be removed
+func (cmd *command) approveRemoval(selected []trust.TrustedCertificate) bool {
+	if cmd.opts.CI {
+		return true
+	}
+	for _, cert := range selected {
+		fmt.Printf("%s %s (%s)\n", cert.Fingerprint[:16], cert.Subject, cert.Location)
+	}
+	approveStep := cmd.NewStep("Remove Kyma certificates from the trust store")
+	defer approveStep.Success()
+	return approveStep.PromptYesNo(fmt.Sprintf("Do you want to remove %d Kyma certificates from the trust store? ", len(selected)))
+}
+
+//selectCertificates returns the certificates with the given fingerprints or the certificates selected with the flags
+func (cmd *command) selectCertificates(certs []trust.TrustedCertificate, fingerprints []string) ([]trust.TrustedCertificate, error) {
+	var selected []trust.TrustedCertificate
+	for _, fingerprint := range fingerprints {
+		cert, err := trust.Find(certs, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if !contains(selected, cert.Fingerprint) {
+			selected = append(selected, cert)
+		}
+	}
+	if len(fingerprints) > 0 {
+		return selected, nil
+	}
+
+	for _, cert := range certs {
+		if cmd.opts.Expired && !cert.Expired() {
+			continue
+		}
+		if cmd.opts.Domain != "" && !cert.MatchesDomain(cmd.opts.Domain) {
+			continue
+		}
+		selected = append(selected, cert)
+	}
+	return selected, nil
+}
+
+func contains(certs []trust.TrustedCertificate, fingerprint string) bool {
+	for _, cert := range certs {
+		if cert.Fingerprint == fingerprint {
+			return true
+		}
+	}
+	return false
+}
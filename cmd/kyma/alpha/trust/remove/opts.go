@@ -0,0 +1,39 @@
+package remove
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the command
+type Options struct {
+	*cli.Options
+	All     bool
+	Expired bool
+	Domain  string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
+
+//selects returns true if the certificates are selected with flags instead of fingerprints
+func (o *Options) selects() bool {
+	return o.All || o.Expired || o.Domain != ""
+}
+
+//validateFlags applies a sanity check on provided options
+func (o *Options) validateFlags(fingerprints []string) error {
+	if len(fingerprints) == 0 && !o.selects() {
+		return fmt.Errorf("Provide the fingerprints of the certificates or select them with the \"all\", \"expired\", or \"domain\" flag")
+	}
+	if len(fingerprints) > 0 && o.selects() {
+		return fmt.Errorf("The fingerprints of the certificates cannot be combined with the \"all\", \"expired\", or \"domain\" flag")
+	}
+	if o.All && (o.Expired || o.Domain != "") {
+		return fmt.Errorf("The \"all\" flag cannot be combined with the \"expired\" or \"domain\" flag")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package remove
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptsValidation(t *testing.T) {
+	t.Run("Fingerprints", func(t *testing.T) {
+		opts := &Options{}
+		require.NoError(t, opts.validateFlags([]string{"a1b2c3d4"}))
+		opts.All = true
+		require.Error(t, opts.validateFlags([]string{"a1b2c3d4"}))
+	})
+	t.Run("Selected certificates", func(t *testing.T) {
+		opts := &Options{Expired: true, Domain: "local.kyma.dev"}
+		require.NoError(t, opts.validateFlags(nil))
+		opts.All = true
+		require.Error(t, opts.validateFlags(nil))
+	})
+	t.Run("No certificates", func(t *testing.T) {
+		opts := &Options{}
+		require.Error(t, opts.validateFlags(nil))
+	})
+}
@@ -2,10 +2,33 @@ package kyma
 
 import (
 	"github.com/kyma-project/cli/cmd/kyma/alpha"
+	alphaBundle "github.com/kyma-project/cli/cmd/kyma/alpha/bundle"
+	alphaBundleCreate "github.com/kyma-project/cli/cmd/kyma/alpha/bundle/create"
+	alphaCert "github.com/kyma-project/cli/cmd/kyma/alpha/cert"
+	alphaCertCreate "github.com/kyma-project/cli/cmd/kyma/alpha/cert/create"
+	alphaCertRotate "github.com/kyma-project/cli/cmd/kyma/alpha/cert/rotate"
+	alphaCertStatus "github.com/kyma-project/cli/cmd/kyma/alpha/cert/status"
+	alphaCheck "github.com/kyma-project/cli/cmd/kyma/alpha/check"
 	alphaDelete "github.com/kyma-project/cli/cmd/kyma/alpha/delete"
 	alphaInstall "github.com/kyma-project/cli/cmd/kyma/alpha/deploy"
+	alphaHosts "github.com/kyma-project/cli/cmd/kyma/alpha/hosts"
+	alphaHostsRemove "github.com/kyma-project/cli/cmd/kyma/alpha/hosts/remove"
+	alphaHostsShow "github.com/kyma-project/cli/cmd/kyma/alpha/hosts/show"
+	alphaHostsSync "github.com/kyma-project/cli/cmd/kyma/alpha/hosts/sync"
+	alphaImages "github.com/kyma-project/cli/cmd/kyma/alpha/images"
+	alphaImagesList "github.com/kyma-project/cli/cmd/kyma/alpha/images/list"
+	alphaImagesMirror "github.com/kyma-project/cli/cmd/kyma/alpha/images/mirror"
 	alphaProvision "github.com/kyma-project/cli/cmd/kyma/alpha/provision"
 	"github.com/kyma-project/cli/cmd/kyma/alpha/provision/k3s"
+	alphaPrune "github.com/kyma-project/cli/cmd/kyma/alpha/prune"
+	alphaSources "github.com/kyma-project/cli/cmd/kyma/alpha/sources"
+	alphaSourcesList "github.com/kyma-project/cli/cmd/kyma/alpha/sources/list"
+	alphaSourcesPrune "github.com/kyma-project/cli/cmd/kyma/alpha/sources/prune"
+	alphaSourcesPull "github.com/kyma-project/cli/cmd/kyma/alpha/sources/pull"
+	alphaSummary "github.com/kyma-project/cli/cmd/kyma/alpha/summary"
+	alphaTrust "github.com/kyma-project/cli/cmd/kyma/alpha/trust"
+	alphaTrustList "github.com/kyma-project/cli/cmd/kyma/alpha/trust/list"
+	alphaTrustRemove "github.com/kyma-project/cli/cmd/kyma/alpha/trust/remove"
 	alphaVersion "github.com/kyma-project/cli/cmd/kyma/alpha/version"
 	"github.com/kyma-project/cli/cmd/kyma/apply"
 	"github.com/kyma-project/cli/cmd/kyma/completion"
@@ -63,11 +86,46 @@ Kyma CLI allows you to install, test, and manage Kyma.
 	alphaCmd.AddCommand(alphaInstall.NewCmd(alphaInstall.NewOptions(o)))
 	alphaCmd.AddCommand(alphaDelete.NewCmd(alphaDelete.NewOptions(o)))
 	alphaCmd.AddCommand(alphaVersion.NewCmd(alphaVersion.NewOptions(o)))
+	alphaCmd.AddCommand(alphaSummary.NewCmd(alphaSummary.NewOptions(o)))
+	alphaCmd.AddCommand(alphaCheck.NewCmd(alphaCheck.NewOptions(o)))
+	alphaCmd.AddCommand(alphaPrune.NewCmd(alphaPrune.NewOptions(o)))
 
 	alphaProvisionCmd := alphaProvision.NewCmd()
 	alphaProvisionCmd.AddCommand(k3s.NewCmd(k3s.NewOptions(o)))
 	alphaCmd.AddCommand(alphaProvisionCmd)
 
+	alphaBundleCmd := alphaBundle.NewCmd()
+	alphaBundleCmd.AddCommand(alphaBundleCreate.NewCmd(alphaBundleCreate.NewOptions(o)))
+	alphaCmd.AddCommand(alphaBundleCmd)
+
+	alphaImagesCmd := alphaImages.NewCmd()
+	alphaImagesCmd.AddCommand(alphaImagesList.NewCmd(alphaImagesList.NewOptions(o)))
+	alphaImagesCmd.AddCommand(alphaImagesMirror.NewCmd(alphaImagesMirror.NewOptions(o)))
+	alphaCmd.AddCommand(alphaImagesCmd)
+
+	alphaSourcesCmd := alphaSources.NewCmd()
+	alphaSourcesCmd.AddCommand(alphaSourcesList.NewCmd(alphaSourcesList.NewOptions(o)))
+	alphaSourcesCmd.AddCommand(alphaSourcesPrune.NewCmd(alphaSourcesPrune.NewOptions(o)))
+	alphaSourcesCmd.AddCommand(alphaSourcesPull.NewCmd(alphaSourcesPull.NewOptions(o)))
+	alphaCmd.AddCommand(alphaSourcesCmd)
+
+	alphaCertCmd := alphaCert.NewCmd()
+	alphaCertCmd.AddCommand(alphaCertCreate.NewCmd(alphaCertCreate.NewOptions(o)))
+	alphaCertCmd.AddCommand(alphaCertRotate.NewCmd(alphaCertRotate.NewOptions(o)))
+	alphaCertCmd.AddCommand(alphaCertStatus.NewCmd(alphaCertStatus.NewOptions(o)))
+	alphaCmd.AddCommand(alphaCertCmd)
+
+	alphaHostsCmd := alphaHosts.NewCmd()
+	alphaHostsCmd.AddCommand(alphaHostsSync.NewCmd(alphaHostsSync.NewOptions(o)))
+	alphaHostsCmd.AddCommand(alphaHostsRemove.NewCmd(alphaHostsRemove.NewOptions(o)))
+	alphaHostsCmd.AddCommand(alphaHostsShow.NewCmd(alphaHostsShow.NewOptions(o)))
+	alphaCmd.AddCommand(alphaHostsCmd)
+
+	alphaTrustCmd := alphaTrust.NewCmd()
+	alphaTrustCmd.AddCommand(alphaTrustList.NewCmd(alphaTrustList.NewOptions(o)))
+	alphaTrustCmd.AddCommand(alphaTrustRemove.NewCmd(alphaTrustRemove.NewOptions(o)))
+	alphaCmd.AddCommand(alphaTrustCmd)
+
 	//Stable commands
 	provisionCmd := provision.NewCmd()
 	provisionCmd.AddCommand(minikube.NewCmd(minikube.NewOptions(o)))
@@ -0,0 +1,288 @@
+// Package bundle provides the packaging of everything required to deploy Kyma without access to the internet.
+//
+// A bundle is a gzipped tarball containing the Kyma sources (charts and installation resources), the components file,
+// the values files, the list of referenced container images and optionally the image tarball.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	metadataFile       = "bundle.yaml"
+	sourcesDir         = "sources"
+	componentsFile     = "components.yaml"
+	valuesDir          = "values"
+	imagesArchiveFile  = "images.tar"
+	currentAPIVersion  = "v1"
+	kymaResourcesDir   = "resources"
+	kymaInstallDirName = "installation"
+)
+
+// kymaDirs are the folders of the Kyma sources which are required for a deployment
+var kymaDirs = []string{kymaResourcesDir, filepath.Join(kymaInstallDirName, kymaResourcesDir)}
+
+// Metadata describes the content of a bundle.
+type Metadata struct {
+	APIVersion string    `yaml:"apiVersion"`
+	Source     string    `yaml:"source"`
+	Commit     string    `yaml:"commit,omitempty"`
+	Created    time.Time `yaml:"created"`
+	// ValuesFiles are the names of the values files in the order in which they are applied
+	ValuesFiles []string `yaml:"valuesFiles,omitempty"`
+	// Images are all container images referenced by the rendered components
+	Images []string `yaml:"images"`
+	// ImagesIncluded is true if the bundle contains the tarball of all images
+	ImagesIncluded bool `yaml:"imagesIncluded"`
+}
+
+// Content lists the local files which are packaged into a bundle.
+type Content struct {
+	Metadata
+	// SourcesPath is the root folder of the Kyma sources
+	SourcesPath    string
+	ComponentsFile string
+	ValuesFiles    []string
+	// ImagesArchive is the tarball of all images (optional)
+	ImagesArchive string
+}
+
+// Bundle is an extracted bundle.
+type Bundle struct {
+	Metadata
+	// Dir is the folder the bundle was extracted to
+	Dir string
+}
+
+// SourcesPath returns the root folder of the Kyma sources.
+func (b *Bundle) SourcesPath() string {
+	return filepath.Join(b.Dir, sourcesDir)
+}
+
+// ComponentsFile returns the path of the components file.
+func (b *Bundle) ComponentsFile() string {
+	return filepath.Join(b.Dir, componentsFile)
+}
+
+// ValuesFilePaths returns the paths of the values files in the order in which they have to be applied.
+func (b *Bundle) ValuesFilePaths() []string {
+	var result []string
+	for _, file := range b.ValuesFiles {
+		result = append(result, filepath.Join(b.Dir, valuesDir, file))
+	}
+	return result
+}
+
+// ImagesArchive returns the path of the image tarball or an empty string if the bundle contains no images.
+func (b *Bundle) ImagesArchive() string {
+	if !b.ImagesIncluded {
+		return ""
+	}
+	return filepath.Join(b.Dir, imagesArchiveFile)
+}
+
+// Create writes a bundle with the given content to a file.
+func Create(file string, content Content) (err error) {
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "Could not create bundle file '%s'", file)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(file)
+		}
+	}()
+
+	gzw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gzw)
+
+	metadata := content.Metadata
+	metadata.APIVersion = currentAPIVersion
+	metadata.ValuesFiles = nil
+	for idx, valuesFile := range content.ValuesFiles {
+		// prefix the files with their position to keep the order and to avoid name clashes
+		name := fmt.Sprintf("%02d-%s", idx, filepath.Base(valuesFile))
+		metadata.ValuesFiles = append(metadata.ValuesFiles, name)
+		if err := addFile(tw, valuesFile, filepath.Join(valuesDir, name)); err != nil {
+			return err
+		}
+	}
+	metadata.ImagesIncluded = content.ImagesArchive != ""
+	if metadata.ImagesIncluded {
+		if err := addFile(tw, content.ImagesArchive, imagesArchiveFile); err != nil {
+			return err
+		}
+	}
+	if err := addFile(tw, content.ComponentsFile, componentsFile); err != nil {
+		return err
+	}
+	for _, dir := range kymaDirs {
+		if err := addDir(tw, filepath.Join(content.SourcesPath, dir), filepath.Join(sourcesDir, dir)); err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "Could not serialize bundle metadata")
+	}
+	if err := addContent(tw, data, metadataFile); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "Could not finish bundle archive")
+	}
+	return gzw.Close()
+}
+
+// Extract unpacks a bundle into a folder.
+func Extract(file, dir string) (*Bundle, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not open bundle file '%s'", file)
+	}
+	defer in.Close()
+
+	gzr, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Bundle file '%s' is not a gzipped tarball", file)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read bundle file '%s'", file)
+		}
+		if err := extractEntry(tr, header, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "File '%s' is not a Kyma bundle", file)
+	}
+	bundle := &Bundle{Dir: dir}
+	if err := yaml.Unmarshal(data, &bundle.Metadata); err != nil {
+		return nil, errors.Wrap(err, "Could not parse bundle metadata")
+	}
+	if bundle.APIVersion != currentAPIVersion {
+		return nil, fmt.Errorf("Bundle version '%s' is not supported", bundle.APIVersion)
+	}
+	return bundle, nil
+}
+
+func extractEntry(tr *tar.Reader, header *tar.Header, dir string) error {
+	target := filepath.Join(dir, filepath.FromSlash(header.Name))
+	// reject entries which would be written outside of the target folder
+	if rel, err := filepath.Rel(dir, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("Bundle contains illegal file path '%s'", header.Name)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0700)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode)&0700)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return errors.Wrapf(err, "Could not extract file '%s'", header.Name)
+		}
+		return out.Close()
+	default:
+		// other entry types are never written by Create
+		return fmt.Errorf("Bundle contains unsupported entry '%s'", header.Name)
+	}
+}
+
+func addDir(tw *tar.Writer, src, name string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "Could not add folder '%s' to bundle", src)
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(name, rel)
+		if info.IsDir() {
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     filepath.ToSlash(target) + "/",
+				Mode:     0700,
+				ModTime:  info.ModTime(),
+			})
+		}
+		return addFile(tw, path, target)
+	})
+}
+
+func addFile(tw *tar.Writer, src, name string) error {
+	// follow symbolic links
+	info, err := os.Stat(src)
+	if err != nil {
+		return errors.Wrapf(err, "Could not add file '%s' to bundle", src)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "Could not add file '%s' to bundle", src)
+	}
+	defer in.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filepath.ToSlash(name),
+		Size:     info.Size(),
+		Mode:     int64(info.Mode().Perm()),
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, in); err != nil {
+		return errors.Wrapf(err, "Could not add file '%s' to bundle", src)
+	}
+	return nil
+}
+
+func addContent(tw *tar.Writer, data []byte, name string) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0600,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,88 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndExtract(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-bundle-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Kyma sources with a file which is not required for the deployment
+	src := filepath.Join(dir, "kyma")
+	writeFile(t, filepath.Join(src, "resources", "comp1", "Chart.yaml"), "name: comp1")
+	writeFile(t, filepath.Join(src, "installation", "resources", "values.yaml"), "global: {}")
+	writeFile(t, filepath.Join(src, "docs", "README.md"), "docs")
+	writeFile(t, filepath.Join(dir, "components.yaml"), "components: []")
+	writeFile(t, filepath.Join(dir, "a", "values.yaml"), "a: 1")
+	writeFile(t, filepath.Join(dir, "b", "values.yaml"), "b: 2")
+
+	file := filepath.Join(dir, "kyma.tgz")
+	err = Create(file, Content{
+		Metadata: Metadata{
+			Source: "2.0.0",
+			Images: []string{"eu.gcr.io/kyma-project/comp1:1.0.0"},
+		},
+		SourcesPath:    src,
+		ComponentsFile: filepath.Join(dir, "components.yaml"),
+		ValuesFiles:    []string{filepath.Join(dir, "a", "values.yaml"), filepath.Join(dir, "b", "values.yaml")},
+	})
+	require.NoError(t, err)
+
+	extractDir := filepath.Join(dir, "extracted")
+	b, err := Extract(file, extractDir)
+	require.NoError(t, err)
+
+	require.Equal(t, "2.0.0", b.Source)
+	require.Equal(t, []string{"eu.gcr.io/kyma-project/comp1:1.0.0"}, b.Images)
+	require.Empty(t, b.ImagesArchive())
+	require.FileExists(t, filepath.Join(b.SourcesPath(), "resources", "comp1", "Chart.yaml"))
+	require.FileExists(t, filepath.Join(b.SourcesPath(), "installation", "resources", "values.yaml"))
+	require.NoFileExists(t, filepath.Join(b.SourcesPath(), "docs", "README.md"))
+	requireContent(t, "components: []", b.ComponentsFile())
+
+	valuesFiles := b.ValuesFilePaths()
+	require.Len(t, valuesFiles, 2)
+	requireContent(t, "a: 1", valuesFiles[0])
+	requireContent(t, "b: 2", valuesFiles[1])
+}
+
+func TestExtractIllegalPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-bundle-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "evil.tgz")
+	out, err := os.Create(file)
+	require.NoError(t, err)
+	gzw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gzw)
+	require.NoError(t, addContent(tw, []byte("evil"), "../evil.txt"))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	require.NoError(t, out.Close())
+
+	_, err = Extract(file, filepath.Join(dir, "extracted"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "illegal file path")
+	require.NoFileExists(t, filepath.Join(dir, "evil.txt"))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func requireContent(t *testing.T, expected, path string) {
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+}
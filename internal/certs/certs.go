@@ -0,0 +1,286 @@
+// Package certs creates the self-signed certificates for custom Kyma domains and stores them in the Kyma home directory:
+// for each domain a local certificate authority (CA) and a wildcard certificate for the domain, which is signed by the CA.
+// Importing the CA into the trust store of the OS makes the certificates of the domain trusted.
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultValidity is the validity of a created wildcard certificate
+	DefaultValidity = 365 * 24 * time.Hour
+	// RenewBefore is the remaining validity of a wildcard certificate below which it is not reused but created again
+	RenewBefore = 30 * 24 * time.Hour
+
+	caValidity  = 10 * 365 * 24 * time.Hour
+	keyBits     = 2048
+	dirName     = "certs"
+	caFileName  = "ca.crt"
+	caKeyName   = "ca.key"
+	crtFileName = "tls.crt"
+	keyFileName = "tls.key"
+)
+
+// Cert is the stored certificate of a domain.
+type Cert struct {
+	Domain string
+	// CAFile is the certificate of the CA which signed the certificate
+	CAFile string
+	// CrtFile is the wildcard certificate of the domain followed by the certificate of the CA
+	CrtFile string
+	// KeyFile is the private key of the wildcard certificate
+	KeyFile string
+	// NotAfter is the end of the validity of the wildcard certificate
+	NotAfter time.Time
+}
+
+// DefaultDir returns the directory in the Kyma home directory which contains the certificates of all domains.
+func DefaultDir() (string, error) {
+	kymaHome, err := files.KymaHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(kymaHome, dirName), nil
+}
+
+// Store provides the certificates stored in a directory, one sub-directory per domain.
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore creates a store for the certificates of the directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// Get returns the certificate of the domain or nil if no certificate was created for the domain.
+func (s *Store) Get(domain string) (*Cert, error) {
+	dir, err := s.domainDir(domain)
+	if err != nil {
+		return nil, err
+	}
+	cert := &Cert{
+		Domain:  domain,
+		CAFile:  filepath.Join(dir, caFileName),
+		CrtFile: filepath.Join(dir, crtFileName),
+		KeyFile: filepath.Join(dir, keyFileName),
+	}
+	crt, err := readCertificate(cert.CrtFile)
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cert.NotAfter = crt.NotAfter
+	return cert, nil
+}
+
+// Ensure returns the certificate of the domain if it is valid for longer than RenewBefore.
+// Otherwise, it creates a new certificate with the default validity.
+func (s *Store) Ensure(domain string) (cert *Cert, created bool, err error) {
+	cert, err = s.Get(domain)
+	if err != nil {
+		return nil, false, err
+	}
+	if cert != nil && cert.NotAfter.Sub(s.now()) > RenewBefore {
+		return cert, false, nil
+	}
+	cert, err = s.Create(domain, DefaultValidity)
+	return cert, err == nil, err
+}
+
+// Create creates a wildcard certificate for the domain and replaces the existing certificate of the domain.
+// The existing CA of the domain signs the certificate if it is valid for longer than the certificate,
+// so that a CA which was imported into the trust store stays valid. Otherwise, a new CA is created as well.
+func (s *Store) Create(domain string, validity time.Duration) (*Cert, error) {
+	dir, err := s.domainDir(domain)
+	if err != nil {
+		return nil, err
+	}
+	// only the user may read the private keys, including the ones of folders created by earlier versions
+	for _, d := range []string{s.dir, dir} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return nil, errors.Wrapf(err, "Could not create the certificate folder '%s'", d)
+		}
+		if err := os.Chmod(d, 0700); err != nil {
+			return nil, errors.Wrapf(err, "Could not restrict the permissions of the certificate folder '%s'", d)
+		}
+	}
+	now := s.now()
+	notAfter := now.Add(validity).Truncate(time.Second)
+
+	// a CA without name constraints (created by earlier versions) is replaced, because it could sign certificates of any domain
+	ca, caKey, err := s.loadCA(dir)
+	if err != nil || ca.NotAfter.Before(notAfter) || !ca.PermittedDNSDomainsCritical {
+		if ca, caKey, err = s.createCA(dir, domain, now); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not generate the private key of the certificate")
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(now),
+		Subject:      pkix.Name{CommonName: "*." + domain, Organization: []string{"Kyma"}},
+		DNSNames:     []string{"*." + domain, domain},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not create the certificate of domain '%s'", domain)
+	}
+
+	cert := &Cert{
+		Domain:   domain,
+		CAFile:   filepath.Join(dir, caFileName),
+		CrtFile:  filepath.Join(dir, crtFileName),
+		KeyFile:  filepath.Join(dir, keyFileName),
+		NotAfter: notAfter,
+	}
+	// the gateway presents the certificate chain, so clients which trust the CA can verify it
+	chain := append(encode("CERTIFICATE", der), encode("CERTIFICATE", ca.Raw)...)
+	if err := writeFile(cert.CrtFile, chain); err != nil {
+		return nil, err
+	}
+	if err := writeFile(cert.KeyFile, encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// createCA creates the CA of the domain.
+// The CA is imported into the trust stores of the OS, so its name constraints restrict it to the domain:
+// whoever gets hold of its private key cannot sign certificates of other domains which the machine trusts.
+func (s *Store) createCA(dir, domain string, now time.Time) (*x509.Certificate, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not generate the private key of the CA")
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(now),
+		Subject:      pkix.Name{CommonName: fmt.Sprintf("Kyma CA %s", domain), Organization: []string{"Kyma"}},
+		// the Linux trust store names the imported certificate after its DNS name
+		DNSNames:              []string{domain},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		// the domain and its sub-domains
+		PermittedDNSDomainsCritical: true,
+		PermittedDNSDomains:         []string{domain},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Could not create the CA of domain '%s'", domain)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeFile(filepath.Join(dir, caFileName), encode("CERTIFICATE", der)); err != nil {
+		return nil, nil, err
+	}
+	if err := writeFile(filepath.Join(dir, caKeyName), encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))); err != nil {
+		return nil, nil, err
+	}
+	return ca, key, nil
+}
+
+// loadCA reads the CA of the domain directory.
+func (s *Store) loadCA(dir string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	ca, err := readCertificate(filepath.Join(dir, caFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := readPEM(filepath.Join(dir, caKeyName))
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not parse the private key of the CA")
+	}
+	return ca, key, nil
+}
+
+// domainDir returns the directory of the domain.
+func (s *Store) domainDir(domain string) (string, error) {
+	if domain == "" || strings.ContainsAny(domain, `/\*`) || strings.HasPrefix(domain, ".") {
+		return "", fmt.Errorf("'%s' is not a valid domain", domain)
+	}
+	return filepath.Join(s.dir, domain), nil
+}
+
+// readCertificate parses the first certificate of the PEM file.
+func readCertificate(file string) (*x509.Certificate, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not parse the certificate '%s'", file)
+	}
+	return crt, nil
+}
+
+// readPEM returns the first PEM block of the file.
+func readPEM(file string) (*pem.Block, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("The file '%s' does not contain a PEM encoded certificate or key", file)
+	}
+	return block, nil
+}
+
+func encode(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+// writeFile writes the file so that only the user can read it, even if it existed with other permissions before.
+func writeFile(file string, data []byte) error {
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		return errors.Wrapf(err, "Could not write the file '%s'", file)
+	}
+	if err := os.Chmod(file, 0600); err != nil {
+		return errors.Wrapf(err, "Could not restrict the permissions of the file '%s'", file)
+	}
+	return nil
+}
+
+// serialNumber returns a unique serial number of a certificate.
+func serialNumber(now time.Time) *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return big.NewInt(now.UnixNano())
+	}
+	return serial
+}
@@ -0,0 +1,142 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store := NewStore(dir)
+
+	t.Run("No certificate", func(t *testing.T) {
+		cert, err := store.Get("kyma.example.com")
+		require.NoError(t, err)
+		require.Nil(t, cert)
+	})
+
+	t.Run("Create certificate", func(t *testing.T) {
+		cert, created, err := store.Ensure("kyma.example.com")
+		require.NoError(t, err)
+		require.True(t, created)
+
+		pair, err := tls.LoadX509KeyPair(cert.CrtFile, cert.KeyFile)
+		require.NoError(t, err)
+		require.Len(t, pair.Certificate, 2)
+		leaf, err := x509.ParseCertificate(pair.Certificate[0])
+		require.NoError(t, err)
+
+		ca, err := readCertificate(cert.CAFile)
+		require.NoError(t, err)
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		_, err = leaf.Verify(x509.VerifyOptions{DNSName: "console.kyma.example.com", Roots: roots})
+		require.NoError(t, err)
+		require.True(t, leaf.NotAfter.Equal(cert.NotAfter))
+	})
+
+	t.Run("Reuse certificate", func(t *testing.T) {
+		existing, err := store.Get("kyma.example.com")
+		require.NoError(t, err)
+		cert, created, err := store.Ensure("kyma.example.com")
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, existing, cert)
+	})
+
+	t.Run("Renew certificate with existing CA", func(t *testing.T) {
+		ca, err := ioutil.ReadFile(filepath.Join(dir, "kyma.example.com", caFileName))
+		require.NoError(t, err)
+
+		store.now = func() time.Time { return time.Now().Add(DefaultValidity) }
+		defer func() { store.now = time.Now }()
+		cert, created, err := store.Ensure("kyma.example.com")
+		require.NoError(t, err)
+		require.True(t, created)
+
+		renewedCA, err := ioutil.ReadFile(cert.CAFile)
+		require.NoError(t, err)
+		require.Equal(t, ca, renewedCA)
+	})
+
+	t.Run("CA is restricted to the domain", func(t *testing.T) {
+		ca, caKey, err := store.loadCA(filepath.Join(dir, "kyma.example.com"))
+		require.NoError(t, err)
+		require.True(t, ca.PermittedDNSDomainsCritical)
+		require.Equal(t, []string{"kyma.example.com"}, ca.PermittedDNSDomains)
+
+		// a certificate of another domain which is signed with the key of the CA is not trusted
+		key, err := rsa.GenerateKey(rand.Reader, keyBits)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: serialNumber(time.Now()),
+			Subject:      pkix.Name{CommonName: "bank.example.org"},
+			DNSNames:     []string{"bank.example.org"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		forged, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		_, err = forged.Verify(x509.VerifyOptions{DNSName: "bank.example.org", Roots: roots})
+		require.Error(t, err)
+	})
+
+	t.Run("Only the user can read the private keys", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Windows does not support Unix permissions")
+		}
+		domainDir := filepath.Join(dir, "kyma.example.com")
+		for file, perm := range map[string]os.FileMode{
+			domainDir:                             0700,
+			filepath.Join(domainDir, caKeyName):   0600,
+			filepath.Join(domainDir, keyFileName): 0600,
+		} {
+			info, err := os.Stat(file)
+			require.NoError(t, err)
+			require.Equal(t, perm, info.Mode().Perm(), file)
+		}
+	})
+
+	t.Run("Invalid domain", func(t *testing.T) {
+		_, err := store.Create("../kyma.example.com", DefaultValidity)
+		require.Error(t, err)
+		_, err = store.Create("*.kyma.example.com", DefaultValidity)
+		require.Error(t, err)
+	})
+}
+
+func TestDomain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cert, err := NewStore(dir).Create("kyma.example.com", DefaultValidity)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(cert.CrtFile)
+	require.NoError(t, err)
+	crt, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, "kyma.example.com", Domain(crt))
+
+	ca, err := readCertificate(cert.CAFile)
+	require.NoError(t, err)
+	require.Empty(t, Domain(ca))
+}
@@ -0,0 +1,51 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	gatewayNamespace = "istio-system"
+	gatewaySecret    = "kyma-gateway-certs"
+)
+
+// Gateway returns the certificate which the Kyma gateway presents, stored in the secret "kyma-gateway-certs".
+func Gateway(static kubernetes.Interface) (*x509.Certificate, error) {
+	secret, err := static.CoreV1().Secrets(gatewayNamespace).Get(context.Background(), gatewaySecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read the certificate of the Kyma gateway from secret '%s/%s'", gatewayNamespace, gatewaySecret)
+	}
+	return Parse(secret.Data["tls.crt"])
+}
+
+// Parse returns the first certificate of the PEM encoded data.
+func Parse(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("No PEM encoded certificate found")
+	}
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse the certificate")
+	}
+	return crt, nil
+}
+
+// Domain returns the domain of a wildcard certificate or an empty string if the certificate is not a wildcard certificate.
+func Domain(crt *x509.Certificate) string {
+	names := append([]string{}, crt.DNSNames...)
+	for _, name := range append(names, crt.Subject.CommonName) {
+		if strings.HasPrefix(name, "*.") {
+			return strings.TrimPrefix(name, "*.")
+		}
+	}
+	return ""
+}
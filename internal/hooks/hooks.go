@@ -0,0 +1,112 @@
+// Package hooks runs user-defined commands and Kubernetes Jobs before and after a component is deployed or deleted.
+package hooks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// When defines at which point of the deployment or deletion of a component a hook runs.
+type When string
+
+const (
+	PreDeploy  When = "pre-deploy"
+	PostDeploy When = "post-deploy"
+	PreDelete  When = "pre-delete"
+	PostDelete When = "post-delete"
+
+	defaultTimeout = 5 * time.Minute
+)
+
+// Hook is a shell command or a Kubernetes Job which runs before or after a component is deployed or deleted.
+type Hook struct {
+	Component string `yaml:"component"`
+	When      When   `yaml:"when"`
+	// Command is run by the shell, with the kubeconfig of the cluster set as KUBECONFIG environment variable
+	Command string `yaml:"command,omitempty"`
+	// Job is the path to a manifest of a Kubernetes Job, which is created in the namespace of the component (if not set in the manifest)
+	Job     string        `yaml:"job,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Name returns a human-readable name of the hook.
+func (h Hook) Name() string {
+	if h.Job != "" {
+		return fmt.Sprintf("%s hook '%s'", h.When, filepath.Base(h.Job))
+	}
+	return fmt.Sprintf("%s hook '%s'", h.When, h.Command)
+}
+
+// File is the hooks file which defines the hooks of all components.
+type File struct {
+	Hooks []Hook `yaml:"hooks"`
+	// dir is the directory of the hooks file: commands run in it and job manifests are relative to it
+	dir string
+}
+
+// Load reads and validates the hooks file.
+func Load(file string) (*File, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read hooks file '%s'", file)
+	}
+	f := &File{dir: filepath.Dir(file)}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse hooks file '%s'", file)
+	}
+
+	supported := []When{PreDeploy, PostDeploy, PreDelete, PostDelete}
+	for i := range f.Hooks {
+		hook := &f.Hooks[i]
+		if hook.Component == "" {
+			return nil, fmt.Errorf("Hook %d in hooks file '%s' has no component", i+1, file)
+		}
+		if !isSupported(hook.When, supported) {
+			return nil, fmt.Errorf("Hook %d of component '%s' runs '%s', supported are: %s", i+1, hook.Component, hook.When, join(supported))
+		}
+		if (hook.Command == "") == (hook.Job == "") {
+			return nil, fmt.Errorf("Hook %d of component '%s' requires either a command or a job", i+1, hook.Component)
+		}
+		if hook.Job != "" && !filepath.IsAbs(hook.Job) {
+			hook.Job = filepath.Join(f.dir, hook.Job)
+		}
+		if hook.Timeout <= 0 {
+			hook.Timeout = defaultTimeout
+		}
+	}
+	return f, nil
+}
+
+// Of returns the hooks of the component which run at the given point, in the order they are defined.
+func (f *File) Of(component string, when When) []Hook {
+	var result []Hook
+	for _, hook := range f.Hooks {
+		if hook.Component == component && hook.When == when {
+			result = append(result, hook)
+		}
+	}
+	return result
+}
+
+func isSupported(when When, supported []When) bool {
+	for _, s := range supported {
+		if when == s {
+			return true
+		}
+	}
+	return false
+}
+
+func join(values []When) string {
+	var result []string
+	for _, value := range values {
+		result = append(result, string(value))
+	}
+	return strings.Join(result, ", ")
+}
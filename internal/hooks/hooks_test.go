@@ -0,0 +1,301 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("Valid hooks", func(t *testing.T) {
+		f, err := Load(writeFile(t, dir, "hooks.yaml", `hooks:
+- component: istio
+  when: post-deploy
+  command: ./patch-secrets.sh
+- component: my-app
+  when: pre-deploy
+  job: jobs/seed.yaml
+  timeout: 10m
+`))
+		require.NoError(t, err)
+		require.Equal(t, []Hook{{Component: "istio", When: PostDeploy, Command: "./patch-secrets.sh", Timeout: defaultTimeout}}, f.Of("istio", PostDeploy))
+		require.Equal(t, []Hook{{Component: "my-app", When: PreDeploy, Job: filepath.Join(dir, "jobs", "seed.yaml"), Timeout: 10 * time.Minute}}, f.Of("my-app", PreDeploy))
+		require.Empty(t, f.Of("istio", PreDeploy))
+	})
+
+	t.Run("Invalid hooks", func(t *testing.T) {
+		_, err := Load(writeFile(t, dir, "hooks.yaml", "hooks:\n- component: istio\n  when: before\n  command: ls\n"))
+		require.EqualError(t, err, "Hook 1 of component 'istio' runs 'before', supported are: pre-deploy, post-deploy, pre-delete, post-delete")
+
+		_, err = Load(writeFile(t, dir, "hooks.yaml", "hooks:\n- component: istio\n  when: pre-deploy\n  command: ls\n  job: job.yaml\n"))
+		require.EqualError(t, err, "Hook 1 of component 'istio' requires either a command or a job")
+	})
+}
+
+func TestDeploy(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	f, err := Load(writeFile(t, dir, "hooks.yaml", `hooks:
+- component: a
+  when: pre-deploy
+  command: echo pre-a >> order.txt
+- component: a
+  when: post-deploy
+  command: echo post-a >> order.txt
+- component: b
+  when: pre-deploy
+  command: echo pre-b $KYMA_NAMESPACE >> order.txt
+- component: c
+  when: pre-deploy
+  command: echo broken && exit 1
+- component: d
+  when: pre-deploy
+  command: echo pre-d >> order.txt
+`))
+	require.NoError(t, err)
+
+	t.Run("Pre-deploy hooks run right before their component", func(t *testing.T) {
+		defer os.Remove(filepath.Join(dir, "order.txt"))
+		runner := NewRunner(f, "kubeconfig", nil)
+		var updates []string
+		err := runner.Deploy(&installConfig.ComponentList{
+			Prerequisites: []installConfig.ComponentDefinition{{Name: "a"}, {Name: "b", Namespace: "ns-b"}},
+			Components:    []installConfig.ComponentDefinition{{Name: "c"}, {Name: "d"}, {Name: "e"}},
+		}, fakeDeploy(t, dir), recordUpdates(&updates))
+		require.NoError(t, err)
+
+		require.Equal(t, "pre-a\ndeploy a\npost-a\npre-b ns-b\ndeploy b\npre-d\ndeploy d\ndeploy e\n", readFile(t, dir, "order.txt"))
+		// the updates of the stages look like the updates of a single deployment
+		require.Equal(t, []string{
+			event(deployment.InstallPreRequisites, deployment.ProcessStart),
+			finished(deployment.InstallPreRequisites, "a", components.StatusInstalled),
+			finished(deployment.InstallPreRequisites, "b", components.StatusInstalled),
+			event(deployment.InstallPreRequisites, deployment.ProcessFinished),
+			event(deployment.InstallComponents, deployment.ProcessStart),
+			finished(deployment.InstallComponents, "c", components.StatusError),
+			finished(deployment.InstallComponents, "d", components.StatusInstalled),
+			finished(deployment.InstallComponents, "e", components.StatusInstalled),
+			event(deployment.InstallComponents, deployment.ProcessFinished),
+		}, updates)
+		require.EqualError(t, runner.Err(), "Hooks failed: pre-deploy hook 'echo broken && exit 1' of component 'c'")
+	})
+
+	t.Run("Failed pre-requisite stops the deployment", func(t *testing.T) {
+		defer os.Remove(filepath.Join(dir, "order.txt"))
+		runner := NewRunner(f, "kubeconfig", nil)
+		var updates []string
+		err := runner.Deploy(&installConfig.ComponentList{
+			Prerequisites: []installConfig.ComponentDefinition{{Name: "a"}, {Name: "c"}, {Name: "b"}},
+			Components:    []installConfig.ComponentDefinition{{Name: "e"}},
+		}, fakeDeploy(t, dir), recordUpdates(&updates))
+		require.EqualError(t, err, "Could not process component 'c' because its hooks failed")
+
+		require.Equal(t, "pre-a\ndeploy a\npost-a\n", readFile(t, dir, "order.txt"))
+		require.Equal(t, []string{
+			event(deployment.InstallPreRequisites, deployment.ProcessStart),
+			finished(deployment.InstallPreRequisites, "a", components.StatusInstalled),
+			finished(deployment.InstallPreRequisites, "c", components.StatusError),
+			event(deployment.InstallPreRequisites, deployment.ProcessExecutionFailure),
+		}, updates)
+	})
+
+	t.Run("Without pre-deploy hooks the components are deployed at once", func(t *testing.T) {
+		defer os.Remove(filepath.Join(dir, "order.txt"))
+		runner := NewRunner(f, "kubeconfig", nil)
+		var stages int
+		deploy := fakeDeploy(t, dir)
+		err := runner.Deploy(&installConfig.ComponentList{
+			Prerequisites: []installConfig.ComponentDefinition{{Name: "e"}, {Name: "f"}},
+			Components:    []installConfig.ComponentDefinition{{Name: "g"}},
+		}, func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+			stages++
+			return deploy(list, callback)
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, stages)
+		require.Equal(t, "deploy e\ndeploy f\ndeploy g\n", readFile(t, dir, "order.txt"))
+	})
+}
+
+func TestDelete(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	f, err := Load(writeFile(t, dir, "hooks.yaml", `hooks:
+- component: a
+  when: pre-delete
+  command: echo broken && exit 1
+- component: b
+  when: post-delete
+  command: echo post-b >> order.txt
+`))
+	require.NoError(t, err)
+
+	t.Run("Failed pre-delete hook keeps all components", func(t *testing.T) {
+		runner := NewRunner(f, "kubeconfig", nil)
+		var updates []string
+		err := runner.Delete(&installConfig.ComponentList{
+			Prerequisites: []installConfig.ComponentDefinition{{Name: "a"}},
+			Components:    []installConfig.ComponentDefinition{{Name: "b"}},
+		}, func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+			t.Fatal("Components were deleted although a pre-delete hook failed")
+			return nil
+		}, recordUpdates(&updates))
+		require.Error(t, err)
+		require.Equal(t, []string{
+			event(deployment.UninstallPreRequisites, deployment.ProcessStart),
+			finished(deployment.UninstallPreRequisites, "a", components.StatusError),
+			event(deployment.UninstallPreRequisites, deployment.ProcessExecutionFailure),
+		}, updates)
+	})
+
+	t.Run("Post-delete hooks run after their component", func(t *testing.T) {
+		runner := NewRunner(f, "kubeconfig", nil)
+		err := runner.Delete(&installConfig.ComponentList{
+			Components: []installConfig.ComponentDefinition{{Name: "b"}},
+		}, func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+			callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.UninstallComponents})
+			callback(deployment.ProcessUpdate{
+				Event:     deployment.ProcessRunning,
+				Phase:     deployment.UninstallComponents,
+				Component: components.KymaComponent{Name: "b", Status: components.StatusUninstalled},
+			})
+			callback(deployment.ProcessUpdate{Event: deployment.ProcessFinished, Phase: deployment.UninstallComponents})
+			return nil
+		}, nil)
+		require.NoError(t, err)
+		require.NoError(t, runner.Err())
+		require.Equal(t, "post-b\n", readFile(t, dir, "order.txt"))
+	})
+}
+
+func TestJob(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	f, err := Load(writeFile(t, dir, "hooks.yaml", "hooks:\n- component: my-app\n  when: post-deploy\n  job: seed.yaml\n"))
+	require.NoError(t, err)
+	writeFile(t, dir, "seed.yaml", `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: seed
+spec:
+  template:
+    spec:
+      containers:
+      - name: seed
+        image: busybox
+      restartPolicy: Never
+`)
+
+	static := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "seed-xyz", Namespace: "my-ns", Labels: map[string]string{"job-name": "seed"}},
+	})
+	static.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "seed", Namespace: "my-ns"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}},
+		}, nil
+	})
+
+	runner := NewRunner(f, "kubeconfig", static)
+	runner.interval = time.Millisecond
+	output, err := runner.run(f.Hooks[0], installConfig.ComponentDefinition{Name: "my-app", Namespace: "my-ns"})
+	require.NoError(t, err)
+	require.Equal(t, "fake logs", output)
+
+	// the job is deleted after it finished
+	jobs, err := static.BatchV1().Jobs("my-ns").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, jobs.Items)
+}
+
+// fakeDeploy returns a deployment which reports its phases and components like a real deployment,
+// recording the deployed components in the order file
+func fakeDeploy(t *testing.T, dir string) Process {
+	return func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error {
+		phases := []struct {
+			phase    deployment.InstallationPhase
+			compDefs []installConfig.ComponentDefinition
+		}{
+			{phase: deployment.InstallPreRequisites, compDefs: list.Prerequisites},
+			{phase: deployment.InstallComponents, compDefs: list.Components},
+		}
+		for _, p := range phases {
+			callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: p.phase})
+			for _, compDef := range p.compDefs {
+				appendFile(t, dir, "order.txt", "deploy "+compDef.Name+"\n")
+				callback(deployment.ProcessUpdate{
+					Event:     deployment.ProcessRunning,
+					Phase:     p.phase,
+					Component: components.KymaComponent{Name: compDef.Name, Namespace: compDef.Namespace, Status: components.StatusInstalled},
+				})
+			}
+			callback(deployment.ProcessUpdate{Event: deployment.ProcessFinished, Phase: p.phase})
+		}
+		return nil
+	}
+}
+
+func event(phase deployment.InstallationPhase, event deployment.ProcessEvent) string {
+	return fmt.Sprintf("%s %s", phase, event)
+}
+
+func finished(phase deployment.InstallationPhase, name string, status string) string {
+	return fmt.Sprintf("%s %s %s", phase, name, status)
+}
+
+// recordUpdates returns a callback which records the phase and event, or the component and its status, of each update
+func recordUpdates(updates *[]string) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		if update.IsComponentUpdate() {
+			*updates = append(*updates, finished(update.Phase, update.Component.Name, update.Component.Status))
+			return
+		}
+		*updates = append(*updates, event(update.Phase, update.Event))
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func appendFile(t *testing.T, dir, name, content string) {
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "hooks-")
+	require.NoError(t, err)
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return file
+}
@@ -0,0 +1,150 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultInterval = 2 * time.Second
+
+// run runs the hook and returns its output
+func (r *Runner) run(hook Hook, comp installConfig.ComponentDefinition) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hook.Timeout)
+	defer cancel()
+
+	var output string
+	var err error
+	if hook.Job != "" {
+		output, err = r.runJob(ctx, hook, comp)
+	} else {
+		output, err = r.runCommand(ctx, hook, comp)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("timed out after %s", hook.Timeout)
+	}
+	return output, err
+}
+
+// runCommand runs the command of the hook by the shell in the directory of the hooks file
+func (r *Runner) runCommand(ctx context.Context, hook Hook, comp installConfig.ComponentDefinition) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, hook.Command)
+	cmd.Dir = r.file.dir
+	cmd.Env = append(os.Environ(),
+		"KUBECONFIG="+r.kubeconfig,
+		"KYMA_COMPONENT="+comp.Name,
+		"KYMA_NAMESPACE="+comp.Namespace,
+		"KYMA_HOOK="+string(hook.When),
+	)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// runJob creates the Job of the hook, waits until it is finished, and returns the logs of its pods.
+// The Job is deleted afterwards, so that the hook can run again.
+func (r *Runner) runJob(ctx context.Context, hook Hook, comp installConfig.ComponentDefinition) (string, error) {
+	data, err := ioutil.ReadFile(hook.Job)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read job manifest '%s'", hook.Job)
+	}
+	job := &batchv1.Job{}
+	if err := yaml.UnmarshalStrict(data, job); err != nil {
+		return "", errors.Wrapf(err, "could not parse job manifest '%s'", hook.Job)
+	}
+	if job.Kind != "" && job.Kind != "Job" {
+		return "", fmt.Errorf("manifest '%s' contains a %s instead of a Job", hook.Job, job.Kind)
+	}
+	if job.Namespace == "" {
+		job.Namespace = comp.Namespace
+	}
+	if job.Namespace == "" {
+		job.Namespace = metav1.NamespaceDefault
+	}
+	if job.Name == "" && job.GenerateName == "" {
+		job.GenerateName = fmt.Sprintf("%s-%s-", comp.Name, hook.When)
+	}
+
+	jobs := r.static.BatchV1().Jobs(job.Namespace)
+	created, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "could not create job of manifest '%s'", hook.Job)
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		_ = jobs.Delete(context.Background(), created.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	}()
+
+	succeeded, err := r.waitForJob(ctx, created)
+	logs := r.jobLogs(created)
+	if err != nil {
+		return logs, err
+	}
+	if !succeeded {
+		return logs, fmt.Errorf("job '%s/%s' failed", created.Namespace, created.Name)
+	}
+	return logs, nil
+}
+
+// waitForJob waits until the Job is complete or failed and returns true if it succeeded
+func (r *Runner) waitForJob(ctx context.Context, job *batchv1.Job) (bool, error) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		current, err := r.static.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+		if err != nil && ctx.Err() == nil {
+			return false, errors.Wrapf(err, "could not get job '%s/%s'", job.Namespace, job.Name)
+		}
+		if err == nil {
+			for _, cond := range current.Status.Conditions {
+				if cond.Status != corev1.ConditionTrue {
+					continue
+				}
+				switch cond.Type {
+				case batchv1.JobComplete:
+					return true, nil
+				case batchv1.JobFailed:
+					return false, nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobLogs returns the logs of the pods of the Job (errors are ignored, the logs are only informative)
+func (r *Runner) jobLogs(job *batchv1.Job) string {
+	ctx := context.Background()
+	pods, err := r.static.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+	if err != nil {
+		return ""
+	}
+	var logs []string
+	for _, pod := range pods.Items {
+		data, err := r.static.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			continue
+		}
+		logs = append(logs, strings.TrimSpace(string(data)))
+	}
+	return strings.Join(logs, "\n")
+}
@@ -0,0 +1,340 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-project/cli/pkg/step"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Runner runs the hooks of the components while they are deployed or deleted.
+type Runner struct {
+	// StepFactory creates a step for each hook which shows the outcome and the output of the hook (optional)
+	StepFactory step.FactoryInterface
+
+	file       *File
+	kubeconfig string
+	static     kubernetes.Interface
+	interval   time.Duration
+
+	mu     sync.Mutex
+	failed []string
+}
+
+// Process processes the components of the list, e.g. deploys them, and reports the progress to the callback.
+type Process func(list *installConfig.ComponentList, callback func(deployment.ProcessUpdate)) error
+
+// NewRunner creates a runner for the hooks of the file.
+// Commands get the kubeconfig as environment variable, Jobs are created with the Kubernetes client.
+func NewRunner(file *File, kubeconfig string, static kubernetes.Interface) *Runner {
+	return &Runner{
+		file:       file,
+		kubeconfig: kubeconfig,
+		static:     static,
+		interval:   defaultInterval,
+	}
+}
+
+// Deploy deploys the components of the list with the deploy process and runs their hooks.
+// The deployment only reports finished components, so it is split into stages which are deployed one after another,
+// each of them right after the pre-deploy hooks of its components ran:
+// pre-requisites are deployed one after another, so each pre-requisite with pre-deploy hooks starts a new stage.
+// The components are deployed in parallel in the last stage, after the pre-deploy hooks of all of them ran.
+// A component whose pre-deploy hook fails is not deployed but reported as failed; a failed pre-requisite stops the deployment.
+// The updates of the stages are merged, so that the callback receives the start and the end of each phase only once.
+func (r *Runner) Deploy(list *installConfig.ComponentList, deploy Process, callback func(deployment.ProcessUpdate)) error {
+	stages := r.stages(list)
+	m := &merger{next: r.Callback(map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+		deployment.InstallPreRequisites: list.Prerequisites,
+		deployment.InstallComponents:    list.Components,
+	}, callback), started: make(map[deployment.InstallationPhase]bool)}
+
+	// the pre-requisites phase ends with the last stage which contains pre-requisites, the components phase is the last stage
+	last := len(stages) - 1
+	m.last = map[deployment.InstallationPhase]int{deployment.InstallPreRequisites: 0, deployment.InstallComponents: last}
+	for i, stage := range stages {
+		if len(stage.Prerequisites) > 0 {
+			m.last[deployment.InstallPreRequisites] = i
+		}
+	}
+
+	for i, stage := range stages {
+		// only the first pre-requisite of a stage can have pre-deploy hooks
+		if len(stage.Prerequisites) > 0 {
+			if err := r.runAll(stage.Prerequisites[0], PreDeploy); err != nil {
+				return m.fail(deployment.InstallPreRequisites, stage.Prerequisites[0], err)
+			}
+		}
+		if i == last {
+			stage.Components = r.prepare(stage.Components, PreDeploy, m)
+		}
+		if err := deploy(stage, m.callback(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the components of the list with the delete process and runs their hooks.
+// The deletion of all components cannot leave out single components, so the pre-delete hooks of all components run
+// before the deletion starts, in the order of the deletion. If a pre-delete hook fails, nothing is deleted.
+func (r *Runner) Delete(list *installConfig.ComponentList, del Process, callback func(deployment.ProcessUpdate)) error {
+	m := &merger{next: callback, started: make(map[deployment.InstallationPhase]bool)}
+	phases := []struct {
+		phase    deployment.InstallationPhase
+		compDefs []installConfig.ComponentDefinition
+	}{
+		{phase: deployment.UninstallComponents, compDefs: list.Components},
+		{phase: deployment.UninstallPreRequisites, compDefs: reverse(list.Prerequisites)},
+	}
+	for _, p := range phases {
+		for _, compDef := range p.compDefs {
+			if err := r.runAll(compDef, PreDelete); err != nil {
+				return m.fail(p.phase, compDef, err)
+			}
+		}
+	}
+	return del(list, r.Callback(map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+		deployment.UninstallPreRequisites: list.Prerequisites,
+		deployment.UninstallComponents:    list.Components,
+	}, callback))
+}
+
+// Before runs the pre hooks of a component which is processed next in the phase and returns an error if one of them failed.
+func (r *Runner) Before(phase deployment.InstallationPhase, compDef installConfig.ComponentDefinition) error {
+	pre, _ := hooksOf(phase)
+	if pre == "" {
+		return nil
+	}
+	return r.runAll(compDef, pre)
+}
+
+// Callback runs the post hooks of the components and passes the process updates on to the next callback (if any).
+// Post hooks run when the component is finished, before the update is passed on. The deployment waits for the hooks of
+// the pre-requisites, so that a hook can prepare the cluster for the next components.
+// If a hook fails, the component is reported as failed with the output of the hook.
+func (r *Runner) Callback(queues map[deployment.InstallationPhase][]installConfig.ComponentDefinition, next func(deployment.ProcessUpdate)) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		if update.IsComponentUpdate() {
+			update = r.finishComponent(update, queues[update.Phase])
+		}
+		if next != nil {
+			next(update)
+		}
+	}
+}
+
+// Err returns an error if any hook failed.
+func (r *Runner) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("Hooks failed: %s", strings.Join(r.failed, ", "))
+}
+
+// stages splits the component list into the parts which are deployed one after another (see Deploy)
+func (r *Runner) stages(list *installConfig.ComponentList) []*installConfig.ComponentList {
+	var stages []*installConfig.ComponentList
+	current := &installConfig.ComponentList{}
+	for _, compDef := range list.Prerequisites {
+		if r.hasHooks(compDef, PreDeploy) && len(current.Prerequisites) > 0 {
+			stages = append(stages, current)
+			current = &installConfig.ComponentList{}
+		}
+		current.Prerequisites = append(current.Prerequisites, compDef)
+	}
+	// the pre-deploy hooks of the components must not run before the last pre-requisites are deployed
+	for _, compDef := range list.Components {
+		if r.hasHooks(compDef, PreDeploy) && len(current.Prerequisites) > 0 {
+			stages = append(stages, current)
+			current = &installConfig.ComponentList{}
+			break
+		}
+	}
+	current.Components = list.Components
+	return append(stages, current)
+}
+
+// prepare runs the pre hooks of the components in parallel and returns the components whose hooks succeeded.
+// The failed components are reported to the merger.
+func (r *Runner) prepare(compDefs []installConfig.ComponentDefinition, when When, m *merger) []installConfig.ComponentDefinition {
+	errs := make([]error, len(compDefs))
+	var wg sync.WaitGroup
+	for i, compDef := range compDefs {
+		if !r.hasHooks(compDef, when) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, compDef installConfig.ComponentDefinition) {
+			defer wg.Done()
+			errs[i] = r.runAll(compDef, when)
+		}(i, compDef)
+	}
+	wg.Wait()
+
+	var prepared []installConfig.ComponentDefinition
+	for i, compDef := range compDefs {
+		if errs[i] != nil {
+			m.failComponent(deployment.InstallComponents, compDef, errs[i])
+			continue
+		}
+		prepared = append(prepared, compDef)
+	}
+	return prepared
+}
+
+func (r *Runner) finishComponent(update deployment.ProcessUpdate, queue []installConfig.ComponentDefinition) deployment.ProcessUpdate {
+	_, post := hooksOf(update.Phase)
+	comp := update.Component
+	if post == "" || comp.Status == components.StatusError {
+		return update
+	}
+
+	compDef := installConfig.ComponentDefinition{Name: comp.Name, Namespace: comp.Namespace}
+	for _, queued := range queue {
+		if queued.Name == comp.Name {
+			compDef = queued
+			break
+		}
+	}
+	if err := r.runAll(compDef, post); err != nil {
+		update.Component.Status = components.StatusError
+		update.Component.Error = err
+	}
+	return update
+}
+
+func (r *Runner) hasHooks(compDef installConfig.ComponentDefinition, when When) bool {
+	return len(r.file.Of(compDef.Name, when)) > 0
+}
+
+// runAll runs the hooks of the component one after another and stops at the first failure.
+// The error contains the output of the failed hook.
+func (r *Runner) runAll(comp installConfig.ComponentDefinition, when When) error {
+	for _, hook := range r.file.Of(comp.Name, when) {
+		var s step.Step
+		if r.StepFactory != nil {
+			s = r.StepFactory.NewStep(fmt.Sprintf("Running the %s of component '%s'", hook.Name(), comp.Name))
+		}
+		output, err := r.run(hook, comp)
+		output = strings.TrimSpace(output)
+		if err != nil {
+			r.mu.Lock()
+			r.failed = append(r.failed, fmt.Sprintf("%s of component '%s'", hook.Name(), comp.Name))
+			r.mu.Unlock()
+			if s != nil {
+				s.Failuref("The %s of component '%s' failed", hook.Name(), comp.Name)
+			}
+			msg := fmt.Sprintf("The %s of component '%s' failed: %v", hook.Name(), comp.Name, err)
+			if output != "" {
+				msg = fmt.Sprintf("%s\n%s", msg, output)
+			}
+			// the following hooks of the component might depend on the failed one
+			return fmt.Errorf("%s", msg)
+		}
+		if s != nil {
+			s.Successf("The %s of component '%s' succeeded", hook.Name(), comp.Name)
+			if output != "" {
+				s.LogInfo(output)
+			}
+		}
+	}
+	return nil
+}
+
+// merger passes the updates of several runs of a process on as if they came from a single run
+type merger struct {
+	next func(deployment.ProcessUpdate)
+	// last is the index of the run which finishes the phase
+	last map[deployment.InstallationPhase]int
+
+	mu      sync.Mutex
+	started map[deployment.InstallationPhase]bool
+}
+
+// callback returns the callback for the updates of a run: the start of a phase is passed on once, its end only
+// by the run which finishes the phase, and phases which have no components in the run are left out
+func (m *merger) callback(run int) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		if !update.IsComponentUpdate() {
+			m.mu.Lock()
+			last, ok := m.last[update.Phase]
+			skip := ok && (run > last || (run < last && update.Phase == deployment.InstallComponents))
+			switch {
+			case skip:
+			case update.Event == deployment.ProcessStart:
+				skip = m.started[update.Phase]
+				m.started[update.Phase] = true
+			case update.Event == deployment.ProcessFinished:
+				skip = ok && run != last
+			}
+			m.mu.Unlock()
+			if skip {
+				return
+			}
+		}
+		m.next(update)
+	}
+}
+
+// failComponent reports a component which is not processed because its pre hooks failed
+func (m *merger) failComponent(phase deployment.InstallationPhase, compDef installConfig.ComponentDefinition, err error) {
+	m.start(phase)
+	m.next(deployment.ProcessUpdate{
+		Event: deployment.ProcessRunning,
+		Phase: phase,
+		Component: components.KymaComponent{
+			Name:      compDef.Name,
+			Namespace: compDef.Namespace,
+			Status:    components.StatusError,
+			Error:     err,
+		},
+	})
+}
+
+// fail reports the failed component and the failure of its phase, which stops the process
+func (m *merger) fail(phase deployment.InstallationPhase, compDef installConfig.ComponentDefinition, err error) error {
+	m.failComponent(phase, compDef, err)
+	phaseErr := fmt.Errorf("Could not process component '%s' because its hooks failed", compDef.Name)
+	m.next(deployment.ProcessUpdate{Event: deployment.ProcessExecutionFailure, Phase: phase, Error: phaseErr})
+	return phaseErr
+}
+
+// start passes the start of the phase on if it was not started yet
+func (m *merger) start(phase deployment.InstallationPhase) {
+	m.mu.Lock()
+	started := m.started[phase]
+	m.started[phase] = true
+	m.mu.Unlock()
+	if !started {
+		m.next(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: phase})
+	}
+}
+
+// hooksOf returns the hooks which run before and after the components of a phase
+func hooksOf(phase deployment.InstallationPhase) (When, When) {
+	switch phase {
+	case deployment.InstallPreRequisites, deployment.InstallComponents:
+		return PreDeploy, PostDeploy
+	case deployment.UninstallPreRequisites, deployment.UninstallComponents:
+		return PreDelete, PostDelete
+	}
+	return "", ""
+}
+
+// reverse returns the components in reverse order, e.g. the pre-requisites in the order of their deletion
+func reverse(compDefs []installConfig.ComponentDefinition) []installConfig.ComponentDefinition {
+	reversed := make([]installConfig.ComponentDefinition, 0, len(compDefs))
+	for i := len(compDefs) - 1; i >= 0; i-- {
+		reversed = append(reversed, compDefs[i])
+	}
+	return reversed
+}
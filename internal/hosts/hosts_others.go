@@ -5,6 +5,8 @@ package hosts
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -39,22 +41,25 @@ func addDevDomainsToEtcHostsOSSpecific(domain string, s step.Step, hostAlias str
 		return nil
 	}
 
-	if !strings.HasSuffix(hostAlias, "\n") {
-		hostAlias = hostAlias + "\n"
-	}
-
-	cmd := exec.Command("sudo", "tee", "-a", hostsFile)
-	buf := &bytes.Buffer{}
-	_, err = fmt.Fprint(buf, hostAlias)
-	if err != nil {
-		notifyUserFunc(err)
+	// replace the managed block instead of appending the entries, so that repeated installations do not add duplicates
+	fields := strings.Fields(hostAlias)
+	if len(fields) < 2 {
 		return nil
 	}
-	cmd.Stdin = buf
-	err = cmd.Run()
-	if err != nil {
+	if _, err := NewFile().Sync(fields[0], fields[1:]); err != nil {
 		notifyUserFunc(err)
-		return nil
 	}
 	return nil
 }
+
+// writeHostsFile writes the hosts file, using sudo if the current user is not allowed to write it.
+func writeHostsFile(path string, content []byte, perm os.FileMode) error {
+	err := ioutil.WriteFile(path, content, perm)
+	if !os.IsPermission(err) {
+		return err
+	}
+	cmd := exec.Command("sudo", "tee", path)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -4,6 +4,8 @@ package hosts
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/kyma-project/cli/pkg/step"
@@ -25,3 +27,12 @@ func addDevDomainsToEtcHostsOSSpecific(domain string, s step.Step, hostAlias str
 	}
 	return nil
 }
+
+// writeHostsFile writes the hosts file, which requires a terminal running as Administrator.
+func writeHostsFile(path string, content []byte, perm os.FileMode) error {
+	err := ioutil.WriteFile(path, content, perm)
+	if os.IsPermission(err) {
+		return fmt.Errorf("Permission denied, run the command in a terminal as Administrator: %s", err)
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package hosts
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultIP is the IP the hostnames are mapped to if Kyma runs on a local cluster
+	DefaultIP = "127.0.0.1"
+
+	beginMarker = "# BEGIN Kyma managed hosts (maintained by 'kyma alpha hosts')"
+	endMarker   = "# END Kyma managed hosts"
+)
+
+// File is a hosts file with a block of host entries managed by the Kyma CLI.
+// The block is delimited by comments and replaced as a whole, so that syncing the entries several times does not add duplicates.
+type File struct {
+	Path string
+}
+
+// NewFile returns the hosts file of the OS.
+func NewFile() *File {
+	return &File{Path: hostsFile}
+}
+
+// Managed returns the IP and the hostnames of the managed block, or an empty IP if the file has no managed block.
+func (f *File) Managed() (string, []string, error) {
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "Could not read the hosts file '%s'", f.Path)
+	}
+	ip, hostnames := managedEntries(string(content))
+	return ip, hostnames, nil
+}
+
+// Sync replaces the managed block with the entries which map the hostnames to the IP.
+// It returns false if the block already contains exactly these entries and the file was not changed.
+func (f *File) Sync(ip string, hostnames []string) (bool, error) {
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not read the hosts file '%s'", f.Path)
+	}
+	updated := updateBlock(string(content), ip, hostnames)
+	if updated == string(content) {
+		return false, nil
+	}
+	return true, f.write(updated)
+}
+
+// Remove removes the managed block. It returns false if the file has no managed block.
+func (f *File) Remove() (bool, error) {
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not read the hosts file '%s'", f.Path)
+	}
+	updated, found := removeBlock(string(content))
+	if !found {
+		return false, nil
+	}
+	return true, f.write(updated)
+}
+
+func (f *File) write(content string) error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return errors.Wrapf(err, "Could not read the hosts file '%s'", f.Path)
+	}
+	if err := writeHostsFile(f.Path, []byte(content), info.Mode().Perm()); err != nil {
+		return errors.Wrapf(err, "Could not write the hosts file '%s'", f.Path)
+	}
+	return nil
+}
+
+// Hostnames returns the sorted, unique hostnames which can be resolved by a hosts file (no wildcards and no cluster-internal names).
+func Hostnames(hostnames []string) []string {
+	unique := make(map[string]bool)
+	for _, hostname := range hostnames {
+		hostname = strings.TrimSpace(hostname)
+		if hostname == "" || strings.Contains(hostname, "*") || !strings.Contains(hostname, ".") || strings.HasSuffix(hostname, ".cluster.local") {
+			continue
+		}
+		unique[hostname] = true
+	}
+	var result []string
+	for hostname := range unique {
+		result = append(result, hostname)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// updateBlock returns the content with the managed block replaced by the entries of the hostnames (one entry per line).
+func updateBlock(content, ip string, hostnames []string) string {
+	content, _ = removeBlock(content)
+	if len(hostnames) == 0 {
+		return content
+	}
+	lines := []string{beginMarker}
+	for _, hostname := range Hostnames(hostnames) {
+		lines = append(lines, ip+" "+hostname)
+	}
+	lines = append(lines, endMarker)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + strings.Join(lines, "\n") + "\n"
+}
+
+// removeBlock returns the content without the managed block and true if the content contained a managed block.
+// The lines following a begin marker without end marker are kept, only the marker is removed.
+func removeBlock(content string) (string, bool) {
+	var kept, block []string
+	inBlock, found := false, false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inBlock && trimmed == beginMarker:
+			inBlock, found = true, true
+			block = []string{line}
+		case inBlock:
+			block = append(block, line)
+			if trimmed == endMarker {
+				inBlock = false
+				block = nil
+			}
+		default:
+			kept = append(kept, line)
+		}
+	}
+	if inBlock {
+		kept = append(kept, block[1:]...)
+	}
+	return strings.Join(kept, ""), found
+}
+
+// managedEntries returns the IP and the hostnames of the managed block.
+func managedEntries(content string) (string, []string) {
+	var ip string
+	var hostnames []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == beginMarker:
+			inBlock = true
+		case trimmed == endMarker:
+			inBlock = false
+		case inBlock:
+			fields := strings.Fields(trimmed)
+			if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			ip = fields[0]
+			hostnames = append(hostnames, fields[1:]...)
+		}
+	}
+	return ip, hostnames
+}
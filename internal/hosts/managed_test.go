@@ -0,0 +1,76 @@
+package hosts
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const userEntries = `127.0.0.1 localhost
+192.168.1.10 nas.home
+`
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hosts-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	f := &File{Path: filepath.Join(dir, "hosts")}
+	require.NoError(t, ioutil.WriteFile(f.Path, []byte(userEntries), 0644))
+
+	t.Run("Sync entries", func(t *testing.T) {
+		changed, err := f.Sync("127.0.0.1", []string{"console.local.kyma.dev", "*.local.kyma.dev", "dex.local.kyma.dev", "console.local.kyma.dev", "svc.kyma-system.svc.cluster.local"})
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		content, err := ioutil.ReadFile(f.Path)
+		require.NoError(t, err)
+		require.Equal(t, userEntries+beginMarker+`
+127.0.0.1 console.local.kyma.dev
+127.0.0.1 dex.local.kyma.dev
+`+endMarker+"\n", string(content))
+
+		ip, hostnames, err := f.Managed()
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1", ip)
+		require.Equal(t, []string{"console.local.kyma.dev", "dex.local.kyma.dev"}, hostnames)
+	})
+
+	t.Run("Sync is idempotent", func(t *testing.T) {
+		changed, err := f.Sync("127.0.0.1", []string{"dex.local.kyma.dev", "console.local.kyma.dev"})
+		require.NoError(t, err)
+		require.False(t, changed)
+	})
+
+	t.Run("Sync replaces entries", func(t *testing.T) {
+		changed, err := f.Sync("127.0.0.1", []string{"grafana.local.kyma.dev"})
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		_, hostnames, err := f.Managed()
+		require.NoError(t, err)
+		require.Equal(t, []string{"grafana.local.kyma.dev"}, hostnames)
+	})
+
+	t.Run("Remove entries", func(t *testing.T) {
+		removed, err := f.Remove()
+		require.NoError(t, err)
+		require.True(t, removed)
+
+		content, err := ioutil.ReadFile(f.Path)
+		require.NoError(t, err)
+		require.Equal(t, userEntries, string(content))
+
+		removed, err = f.Remove()
+		require.NoError(t, err)
+		require.False(t, removed)
+	})
+}
+
+func TestRemoveBlockWithoutEnd(t *testing.T) {
+	content, found := removeBlock(beginMarker + "\n" + userEntries)
+	require.True(t, found)
+	require.Equal(t, userEntries, content)
+}
@@ -0,0 +1,53 @@
+// Package images provides the handling of the container images referenced by Kyma components.
+package images
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const imageKey = "image"
+
+var docSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// Extract returns the sorted list of all container images referenced in the given Kubernetes manifests.
+// Images are detected by the "image" key of containers and of any other resource (e.g. custom resources of operators).
+func Extract(manifests ...string) ([]string, error) {
+	found := make(map[string]bool)
+	for _, manifest := range manifests {
+		for _, doc := range docSeparator.Split(manifest, -1) {
+			var res interface{}
+			if err := yaml.Unmarshal([]byte(doc), &res); err != nil {
+				return nil, errors.Wrap(err, "Could not parse manifest")
+			}
+			collect(res, found)
+		}
+	}
+
+	result := make([]string, 0, len(found))
+	for image := range found {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func collect(node interface{}, found map[string]bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if image, ok := value.(string); ok && key == imageKey && image != "" {
+				found[image] = true
+				continue
+			}
+			collect(value, found)
+		}
+	case []interface{}:
+		for _, item := range n {
+			collect(item, found)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	manifest1 := `# Source: comp1/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: comp1
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: eu.gcr.io/kyma-project/init:1.0.0
+      containers:
+      - name: comp1
+        image: eu.gcr.io/kyma-project/comp1:1.0.0
+      - name: sidecar
+        image: eu.gcr.io/kyma-project/sidecar:2.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: comp1
+data:
+  key: value
+`
+	manifest2 := `apiVersion: monitoring.coreos.com/v1
+kind: Prometheus
+metadata:
+  name: monitoring
+spec:
+  image: quay.io/prometheus/prometheus:v2.26.0
+  containers:
+  - name: sidecar
+    image: eu.gcr.io/kyma-project/sidecar:2.0.0
+`
+
+	images, err := Extract(manifest1, manifest2)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"eu.gcr.io/kyma-project/comp1:1.0.0",
+		"eu.gcr.io/kyma-project/init:1.0.0",
+		"eu.gcr.io/kyma-project/sidecar:2.0.0",
+		"quay.io/prometheus/prometheus:v2.26.0",
+	}, images)
+
+	_, err = Extract("kind: [")
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package images
+
+import (
+	"path/filepath"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-project/cli/internal/render"
+	"github.com/pkg/errors"
+)
+
+const globalOverridesKey = "global"
+
+// CollectFromSources renders all components of the components file with the Kyma sources, values files and profile
+// and returns the referenced container images.
+func CollectFromSources(sourcesPath, componentsFile string, valuesFiles []string, profile string) ([]string, error) {
+	ob := &overrides.Builder{}
+	for _, valuesFile := range valuesFiles {
+		if err := ob.AddFile(valuesFile); err != nil {
+			return nil, err
+		}
+	}
+	// TODO remove this block when default component values are migrated to kyma 2.0
+	if err := ob.AddFile(filepath.Join(sourcesPath, "installation", "resources", "values.yaml")); err != nil {
+		return nil, errors.Wrap(err, "Could not add overrides for Kyma 2.0")
+	}
+	o, err := ob.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not build overrides")
+	}
+
+	compList, err := installConfig.NewComponentList(componentsFile)
+	if err != nil {
+		return nil, err
+	}
+	renderer := &render.Renderer{
+		ResourcePath: filepath.Join(sourcesPath, "resources"),
+		Profile:      profile,
+		Overrides:    o.Map(),
+	}
+	return Collect(renderer, compList)
+}
+
+// Collect renders all components of the component list and returns the referenced container images.
+func Collect(renderer *render.Renderer, compList *installConfig.ComponentList) ([]string, error) {
+	manifests, err := renderer.RenderAll(compList)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []string
+	for _, manifest := range manifests {
+		contents = append(contents, manifest.Content)
+	}
+	return Extract(contents...)
+}
+
+// RegistryOverrides returns the overrides which make all components of the component list pull their images from the registry.
+// Like the overrides of the renderer, the result contains one top-level key per component plus the global overrides.
+func RegistryOverrides(renderer *render.Renderer, compList *installConfig.ComponentList, registry string) (map[string]interface{}, error) {
+	comps := append([]installConfig.ComponentDefinition{}, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+
+	result := make(map[string]interface{})
+	for _, comp := range comps {
+		values, err := renderer.Values(comp)
+		if err != nil {
+			return nil, err
+		}
+		rewritten := RewriteValues(values, registry)
+		if global, ok := rewritten[globalOverridesKey].(map[string]interface{}); ok {
+			// global values are shared by all components
+			existing, _ := result[globalOverridesKey].(map[string]interface{})
+			result[globalOverridesKey] = mergeValues(existing, global)
+			delete(rewritten, globalOverridesKey)
+		}
+		if len(rewritten) > 0 {
+			result[comp.Name] = rewritten
+		}
+	}
+	return result, nil
+}
+
+// NotInRegistry returns the images which are not pulled from the given registry.
+func NotInRegistry(images []string, registry string) []string {
+	var result []string
+	for _, image := range images {
+		if !InRegistry(image, registry) {
+			result = append(result, image)
+		}
+	}
+	return result
+}
+
+// mergeValues deeply merges the source values into the destination values
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{})
+	}
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[key] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+	return dst
+}
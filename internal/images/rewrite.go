@@ -0,0 +1,121 @@
+package images
+
+import (
+	"strings"
+)
+
+const (
+	repositoryKey = "repository"
+	registryKey   = "registry"
+	// containerRegistryKey is used by Kyma charts to define the registry path of all Kyma images
+	containerRegistryKey = "containerRegistry"
+	pathKey              = "path"
+	// globalImagesPath contains the images of all Kyma components
+	globalImagesPath = "global.images"
+)
+
+// imageDetailKeys are the keys of values which, besides the repository, describe a container image
+var imageDetailKeys = []string{"tag", "version", "digest", "name"}
+
+// Rewrite returns the reference of an image in the given registry.
+// The registry domain of the image is replaced, the repository path is kept
+// (e.g. "eu.gcr.io/kyma-project/foo:1.0" becomes "registry.corp.local/kyma/kyma-project/foo:1.0").
+func Rewrite(image, registry string) string {
+	_, remainder := splitDomain(image)
+	return strings.TrimSuffix(registry, "/") + "/" + remainder
+}
+
+// InRegistry returns true if the image is pulled from the given registry.
+func InRegistry(image, registry string) bool {
+	return strings.HasPrefix(image, strings.TrimSuffix(registry, "/")+"/")
+}
+
+// RewriteValues returns the chart values which have to be overridden so that all images referenced by the given values
+// are pulled from the registry. Only the changed values are returned, structured like the given values.
+//
+// The following ways to define images are supported:
+//   - image: eu.gcr.io/kyma-project/foo:1.0
+//   - repository: quay.io/prometheus/prometheus (optionally with a separate "registry" value)
+//   - containerRegistry.path: eu.gcr.io/kyma-project
+//
+// The "repository" and "registry" values are only rewritten in maps which describe an image, that is, maps with a tag, version,
+// digest, or name of the image, or the maps of the "global.images" values. Other values with these keys (e.g. the URL of a Helm
+// repository or of a backup location) are kept.
+func RewriteValues(values map[string]interface{}, registry string) map[string]interface{} {
+	return rewriteValues(values, "", registry)
+}
+
+func rewriteValues(values map[string]interface{}, path, registry string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if key == containerRegistryKey {
+				if regPath, ok := v[pathKey].(string); ok && regPath != "" && !InRegistry(regPath+"/", registry) {
+					result[key] = map[string]interface{}{pathKey: Rewrite(regPath, registry)}
+				}
+				continue
+			}
+			if nested := rewriteValues(v, joinPath(path, key), registry); len(nested) > 0 {
+				result[key] = nested
+			}
+		case string:
+			if rewritten, ok := rewriteValue(values, path, key, v, registry); ok {
+				result[key] = rewritten
+			}
+		}
+	}
+	return result
+}
+
+// rewriteValue rewrites a single image related value
+func rewriteValue(values map[string]interface{}, path, key, value, registry string) (string, bool) {
+	if value == "" || InRegistry(value, registry) || strings.TrimSuffix(registry, "/") == value {
+		return "", false
+	}
+	switch key {
+	case imageKey:
+		return Rewrite(value, registry), true
+	case registryKey:
+		// the registry is defined separately from the repository
+		if _, ok := values[repositoryKey]; ok && isImageValues(values, path) {
+			return strings.TrimSuffix(registry, "/"), true
+		}
+	case repositoryKey:
+		// if the registry is defined separately, only the registry is rewritten
+		if _, ok := values[registryKey]; !ok && isImageValues(values, path) && !strings.Contains(value, "://") {
+			return Rewrite(value, registry), true
+		}
+	}
+	return "", false
+}
+
+// isImageValues returns true if the values at the given path describe a container image
+func isImageValues(values map[string]interface{}, path string) bool {
+	if strings.HasPrefix(path, globalImagesPath+".") {
+		return true
+	}
+	for _, key := range imageDetailKeys {
+		if _, ok := values[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// splitDomain splits an image reference into the registry domain and the remainder.
+// The domain is empty for images of the Docker Hub which don't contain a domain.
+func splitDomain(image string) (string, string) {
+	i := strings.IndexRune(image, '/')
+	if i == -1 || (!strings.ContainsAny(image[:i], ".:") && image[:i] != "localhost") {
+		return "", image
+	}
+	return image[:i], image[i+1:]
+}
@@ -0,0 +1,127 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite(t *testing.T) {
+	registry := "registry.corp.local/kyma"
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"eu.gcr.io/kyma-project/foo:1.0", "registry.corp.local/kyma/kyma-project/foo:1.0"},
+		{"quay.io/prometheus/prometheus@sha256:1234", "registry.corp.local/kyma/prometheus/prometheus@sha256:1234"},
+		{"localhost:5000/foo", "registry.corp.local/kyma/foo"},
+		{"bitnami/redis:6.0", "registry.corp.local/kyma/bitnami/redis:6.0"},
+		{"nginx", "registry.corp.local/kyma/nginx"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, Rewrite(tt.image, registry), tt.image)
+		require.Equal(t, tt.expected, Rewrite(tt.image, registry+"/"), tt.image)
+	}
+}
+
+func TestRewriteValues(t *testing.T) {
+	registry := "registry.corp.local/kyma"
+
+	values := map[string]interface{}{
+		"global": map[string]interface{}{
+			"containerRegistry": map[string]interface{}{
+				"path": "eu.gcr.io/kyma-project",
+			},
+			"images": map[string]interface{}{
+				"foo": map[string]interface{}{
+					"name":    "foo",
+					"version": "1.0",
+				},
+			},
+		},
+		"image": "eu.gcr.io/kyma-project/bar:1.0",
+		"prometheus": map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "quay.io/prometheus/prometheus",
+				"tag":        "v2.0",
+			},
+		},
+		"redis": map[string]interface{}{
+			"image": map[string]interface{}{
+				"registry":   "docker.io",
+				"repository": "bitnami/redis",
+				"tag":        "6.0",
+			},
+		},
+		"mirrored": map[string]interface{}{
+			"image": "registry.corp.local/kyma/mirrored:1.0",
+		},
+		"replicas": 1,
+		"name":     "foo",
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"global": map[string]interface{}{
+			"containerRegistry": map[string]interface{}{
+				"path": "registry.corp.local/kyma/kyma-project",
+			},
+		},
+		"image": "registry.corp.local/kyma/kyma-project/bar:1.0",
+		"prometheus": map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "registry.corp.local/kyma/prometheus/prometheus",
+			},
+		},
+		"redis": map[string]interface{}{
+			"image": map[string]interface{}{
+				"registry": "registry.corp.local/kyma",
+			},
+		},
+	}, RewriteValues(values, registry))
+}
+
+func TestRewriteValuesKeepsOtherRepositories(t *testing.T) {
+	values := map[string]interface{}{
+		"helm": map[string]interface{}{
+			"repository": "stable",
+		},
+		"backup": map[string]interface{}{
+			"registry":   "eu.gcr.io",
+			"repository": "backups/kyma",
+		},
+		"docs": map[string]interface{}{
+			"name":       "docs",
+			"repository": "https://github.com/kyma-project/kyma",
+		},
+		"global": map[string]interface{}{
+			"images": map[string]interface{}{
+				"foo": map[string]interface{}{
+					"repository": "eu.gcr.io/kyma-project/foo",
+				},
+			},
+		},
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"global": map[string]interface{}{
+			"images": map[string]interface{}{
+				"foo": map[string]interface{}{
+					"repository": "registry.corp.local/kyma/kyma-project/foo",
+				},
+			},
+		},
+	}, RewriteValues(values, "registry.corp.local/kyma"))
+}
+
+func TestNotInRegistry(t *testing.T) {
+	imgs := []string{
+		"registry.corp.local/kyma/foo:1.0",
+		"registry.corp.local/kyma-other/foo:1.0",
+		"eu.gcr.io/kyma-project/foo:1.0",
+	}
+	require.Equal(t, []string{
+		"registry.corp.local/kyma-other/foo:1.0",
+		"eu.gcr.io/kyma-project/foo:1.0",
+	}, NotInRegistry(imgs, "registry.corp.local/kyma"))
+}
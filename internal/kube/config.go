@@ -1,6 +1,8 @@
 package kube
 
 import (
+	"fmt"
+
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -35,6 +37,31 @@ func KubeconfigPath(file string) string {
 	return po.GetLoadingPrecedence()[0]
 }
 
+// ContextConfig provides a kubeconfig which only contains the given context and uses it as current context.
+// If the context is empty, the current context of the kubeconfig is used. If the file is empty, standard kubeconfig loading rules apply.
+func ContextConfig(file, context string) ([]byte, error) {
+	cfg, err := kubeConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if context == "" {
+		context = cfg.CurrentContext
+	}
+	if _, ok := cfg.Contexts[context]; !ok {
+		return nil, fmt.Errorf("Context '%s' not found in kubeconfig", context)
+	}
+	cfg.CurrentContext = context
+
+	// file references (e.g. certificates) must still be found if the kubeconfig is stored elsewhere
+	if err := clientcmd.ResolveLocalPaths(cfg); err != nil {
+		return nil, err
+	}
+	if err := api.MinifyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return clientcmd.Write(*cfg)
+}
+
 // Append adds the provided kubeconfig in the []byte to the Kubeconfig in the target path without altering other existing conifgs.
 // If the target path is empty, standard kubeconfig loading rules apply.
 func AppendConfig(cfg []byte, target string) error {
@@ -0,0 +1,72 @@
+package kube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging-1
+clusters:
+- name: staging-1
+  cluster:
+    server: https://staging-1.example.com
+- name: staging-2
+  cluster:
+    server: https://staging-2.example.com
+    certificate-authority: ca.crt
+contexts:
+- name: staging-1
+  context:
+    cluster: staging-1
+    user: admin
+- name: staging-2
+  context:
+    cluster: staging-2
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+func TestContextConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config")
+	require.NoError(t, ioutil.WriteFile(file, []byte(testKubeconfig), 0600))
+
+	t.Run("Select context", func(t *testing.T) {
+		data, err := ContextConfig(file, "staging-2")
+		require.NoError(t, err)
+		cfg, err := clientcmd.Load(data)
+		require.NoError(t, err)
+
+		require.Equal(t, "staging-2", cfg.CurrentContext)
+		require.Len(t, cfg.Contexts, 1)
+		require.Len(t, cfg.Clusters, 1)
+		require.Equal(t, "https://staging-2.example.com", cfg.Clusters["staging-2"].Server)
+		require.Equal(t, filepath.Join(dir, "ca.crt"), cfg.Clusters["staging-2"].CertificateAuthority)
+		require.Equal(t, "secret", cfg.AuthInfos["admin"].Token)
+	})
+
+	t.Run("Use current context", func(t *testing.T) {
+		data, err := ContextConfig(file, "")
+		require.NoError(t, err)
+		cfg, err := clientcmd.Load(data)
+		require.NoError(t, err)
+		require.Equal(t, "staging-1", cfg.CurrentContext)
+	})
+
+	t.Run("Unknown context", func(t *testing.T) {
+		_, err := ContextConfig(file, "production")
+		require.EqualError(t, err, "Context 'production' not found in kubeconfig")
+	})
+}
@@ -1,8 +1,18 @@
 package nice
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Supported formats of the machine-readable summary
+const (
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
 )
 
 type Summary struct {
@@ -27,9 +37,11 @@ func (s *Summary) Print() error {
 	fmt.Print(" is installed in version:\t")
 	nicePrint.PrintImportant(s.Version)
 
-	nicePrint.PrintKyma()
-	fmt.Print(" installation took:\t\t")
-	nicePrint.PrintImportantf("%d hours %d minutes", int64(s.Duration.Hours()), int64(s.Duration.Minutes()))
+	if s.Duration > 0 {
+		nicePrint.PrintKyma()
+		fmt.Print(" installation took:\t\t")
+		nicePrint.PrintImportantf("%d hours %d minutes", int64(s.Duration.Hours()), int64(s.Duration.Minutes()))
+	}
 
 	nicePrint.PrintKyma()
 	fmt.Print(" is running at:\t\t")
@@ -61,3 +73,50 @@ func (s *Summary) Print() error {
 
 	return nil
 }
+
+// summaryDocument is the machine-readable representation of the summary
+type summaryDocument struct {
+	Version       string `json:"version" yaml:"version"`
+	Domain        string `json:"domain" yaml:"domain"`
+	Console       string `json:"console" yaml:"console"`
+	Duration      string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	AdminEmail    string `json:"adminEmail,omitempty" yaml:"adminEmail,omitempty"`
+	AdminPassword string `json:"adminPassword,omitempty" yaml:"adminPassword,omitempty"`
+}
+
+// PrintStructured prints the summary as JSON or YAML document.
+// The admin password is only included if showSecrets is true.
+func (s *Summary) PrintStructured(format string, showSecrets bool) error {
+	data, err := s.marshal(format, showSecrets)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func (s *Summary) marshal(format string, showSecrets bool) ([]byte, error) {
+	doc := summaryDocument{
+		Version:    s.Version,
+		Domain:     s.URL,
+		Console:    s.Console,
+		AdminEmail: s.Email,
+	}
+	if s.Duration > 0 {
+		doc.Duration = s.Duration.Round(time.Second).String()
+	}
+	if showSecrets {
+		doc.AdminPassword = s.Password
+	}
+
+	switch format {
+	case JSONFormat:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		return data, errors.Wrap(err, "Could not marshal summary to JSON")
+	case YAMLFormat:
+		data, err := yaml.Marshal(doc)
+		return data, errors.Wrap(err, "Could not marshal summary to YAML")
+	default:
+		return nil, fmt.Errorf("Summary format '%s' is not supported", format)
+	}
+}
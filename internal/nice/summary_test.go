@@ -0,0 +1,47 @@
+package nice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryMarshal(t *testing.T) {
+	s := &Summary{
+		Duration: 12*time.Minute + 30*time.Second + 400*time.Millisecond,
+		Version:  "2.0.0",
+		URL:      "local.kyma.dev",
+		Console:  "https://console.local.kyma.dev",
+		Email:    "admin@kyma.cx",
+		Password: "secret",
+	}
+
+	t.Run("JSON without secrets", func(t *testing.T) {
+		data, err := s.marshal(JSONFormat, false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"version": "2.0.0",
+			"domain": "local.kyma.dev",
+			"console": "https://console.local.kyma.dev",
+			"duration": "12m30s",
+			"adminEmail": "admin@kyma.cx"
+		}`, string(data))
+	})
+	t.Run("YAML with secrets", func(t *testing.T) {
+		data, err := s.marshal(YAMLFormat, true)
+		require.NoError(t, err)
+		require.YAMLEq(t, `
+version: 2.0.0
+domain: local.kyma.dev
+console: https://console.local.kyma.dev
+duration: 12m30s
+adminEmail: admin@kyma.cx
+adminPassword: secret
+`, string(data))
+	})
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := s.marshal("xml", false)
+		require.Error(t, err)
+	})
+}
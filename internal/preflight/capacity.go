@@ -0,0 +1,64 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Capacity contains the resources of all schedulable nodes of a cluster.
+type Capacity struct {
+	Nodes  int
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// ClusterCapacity returns the allocatable resources of all schedulable nodes of the cluster.
+func ClusterCapacity(client kubernetes.Interface) (Capacity, error) {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return Capacity{}, errors.Wrap(err, "Could not list the nodes of the cluster")
+	}
+	return capacityOf(nodes.Items), nil
+}
+
+// Fulfills returns true if the capacity provides the nodes and resources the profile needs.
+// User-defined profiles have no known requirements and are always fulfilled.
+func (c Capacity) Fulfills(profile string) bool {
+	required, ok := requirementsOf(profile)
+	return !ok || (c.fulfillsResources(required) && c.Nodes >= required.nodes)
+}
+
+// String returns a human-readable description of the capacity.
+func (c Capacity) String() string {
+	return fmt.Sprintf("%d nodes, %s CPUs, %s memory", c.Nodes, formatCPU(c.CPU), formatMemory(c.Memory))
+}
+
+func (c Capacity) fulfillsResources(required requirements) bool {
+	return c.CPU.Cmp(required.cpu) >= 0 && c.Memory.Cmp(required.memory) >= 0
+}
+
+// capacityOf sums up the allocatable resources of the schedulable nodes
+func capacityOf(nodes []corev1.Node) Capacity {
+	capacity := Capacity{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		capacity.Nodes++
+		capacity.CPU.Add(*node.Status.Allocatable.Cpu())
+		capacity.Memory.Add(*node.Status.Allocatable.Memory())
+	}
+	return capacity
+}
+
+// requirementsOf returns the requirements of a built-in profile, false is returned for user-defined profiles
+func requirementsOf(profile string) (requirements, bool) {
+	required, ok := profileRequirements[profile]
+	return required, ok
+}
@@ -0,0 +1,263 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	kymaNamespace  = "kyma-system"
+	istioNamespace = "istio-system"
+
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// versionRange is a range of Kubernetes minor versions (both inclusive)
+type versionRange struct {
+	min semver.Version
+	max semver.Version
+}
+
+// requirements are the cluster resources a Kyma profile needs
+type requirements struct {
+	nodes  int
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+var (
+	// kubernetesVersions maps the Kyma major versions to the Kubernetes versions they support
+	kubernetesVersions = map[uint64]versionRange{
+		1: {min: semver.Version{Major: 1, Minor: 16}, max: semver.Version{Major: 1, Minor: 19}},
+		2: {min: semver.Version{Major: 1, Minor: 19}, max: semver.Version{Major: 1, Minor: 21}},
+	}
+	// latestKymaMajor is used for sources which are no releases, like branches or PRs
+	latestKymaMajor uint64 = 2
+
+	// profileRequirements maps the built-in Kyma profiles to the cluster resources they need (an empty profile is the default configuration)
+	profileRequirements = map[string]requirements{
+		"evaluation": {nodes: 1, cpu: resource.MustParse("4"), memory: resource.MustParse("8Gi")},
+		"":           {nodes: 2, cpu: resource.MustParse("8"), memory: resource.MustParse("16Gi")},
+		"production": {nodes: 3, cpu: resource.MustParse("12"), memory: resource.MustParse("24Gi")},
+	}
+
+	// requiredAdmissionPlugins are the admission plugins Kyma components rely on
+	requiredAdmissionPlugins = []string{"MutatingAdmissionWebhook", "ValidatingAdmissionWebhook"}
+
+	// requiredAPIGroupVersions are the API group versions Kyma components use
+	requiredAPIGroupVersions = []string{
+		"admissionregistration.k8s.io/v1",
+		"apiextensions.k8s.io/v1",
+		"apps/v1",
+		"networking.k8s.io/v1",
+		"rbac.authorization.k8s.io/v1",
+		"storage.k8s.io/v1",
+	}
+)
+
+// checkKubernetesVersion verifies that the Kubernetes version of the cluster is supported by the Kyma version
+func checkKubernetesVersion(client kubernetes.Interface, cfg Config) (Result, error) {
+	const name = "Kubernetes version"
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Could not read the Kubernetes version of the cluster")
+	}
+	version, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return warn(name, "Verify that the cluster runs a Kubernetes version supported by Kyma.",
+			"Could not parse the Kubernetes version '%s'", info.GitVersion), nil
+	}
+
+	supported, kyma := supportedKubernetesVersions(cfg.Source)
+	current := semver.Version{Major: version.Major, Minor: version.Minor}
+	switch {
+	case current.LT(supported.min):
+		return warn(name, fmt.Sprintf("Upgrade the cluster to Kubernetes %d.%d or higher.", supported.min.Major, supported.min.Minor),
+			"Kubernetes %s is not supported by Kyma %s", info.GitVersion, kyma), nil
+	case current.GT(supported.max):
+		return warn(name, fmt.Sprintf("Use a cluster with Kubernetes %d.%d to %d.%d.", supported.min.Major, supported.min.Minor, supported.max.Major, supported.max.Minor),
+			"Kubernetes %s is newer than the versions Kyma %s is tested with", info.GitVersion, kyma), nil
+	}
+	return pass(name, "Kubernetes %s is supported by Kyma %s", info.GitVersion, kyma), nil
+}
+
+// supportedKubernetesVersions returns the Kubernetes versions supported by the Kyma source and a description of the Kyma version
+func supportedKubernetesVersions(source string) (versionRange, string) {
+	if version, err := semver.ParseTolerant(source); err == nil {
+		if supported, ok := kubernetesVersions[version.Major]; ok {
+			return supported, source
+		}
+	}
+	return kubernetesVersions[latestKymaMajor], fmt.Sprintf("%d.x (%s)", latestKymaMajor, source)
+}
+
+// checkNodeResources verifies that the schedulable nodes provide the resources the profile needs.
+// Missing resources are only a warning, because the requirements are recommendations which depend on the workload of the cluster.
+func checkNodeResources(client kubernetes.Interface, cfg Config) (Result, error) {
+	const name = "Node resources"
+	capacity, err := ClusterCapacity(client)
+	if err != nil {
+		return Result{}, err
+	}
+	available := fmt.Sprintf("%d schedulable nodes provide %s CPUs and %s memory", capacity.Nodes, formatCPU(capacity.CPU), formatMemory(capacity.Memory))
+	required, ok := requirementsOf(cfg.Profile)
+	switch {
+	case !ok:
+		return pass(name, "%s, the requirements of %s are unknown", available, profileName(cfg.Profile)), nil
+	case !capacity.fulfillsResources(required):
+		return warn(name, "Add nodes to the cluster or use a smaller profile, for example, --profile evaluation.",
+			"%s, but %s requires %s CPUs and %s memory", available, profileName(cfg.Profile), formatCPU(required.cpu), formatMemory(required.memory)), nil
+	case capacity.Nodes < required.nodes:
+		return warn(name, "Add nodes to the cluster to avoid that a single node failure makes Kyma unavailable.",
+			"%s, but %s recommends %d nodes", available, profileName(cfg.Profile), required.nodes), nil
+	}
+	return pass(name, "%s", available), nil
+}
+
+// checkDefaultStorageClass verifies that exactly one StorageClass is marked as default
+func checkDefaultStorageClass(client kubernetes.Interface, _ Config) (Result, error) {
+	const name = "Default StorageClass"
+	storageClasses, err := client.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Could not list the StorageClasses of the cluster")
+	}
+
+	var defaults []string
+	for _, sc := range storageClasses.Items {
+		if sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			defaults = append(defaults, sc.Name)
+		}
+	}
+	switch len(defaults) {
+	case 0:
+		return warn(name, fmt.Sprintf(`Mark a StorageClass as default: kubectl patch storageclass {NAME} -p '{"metadata":{"annotations":{"%s":"true"}}}'`, defaultStorageClassAnnotation),
+			"No default StorageClass found, persistent volume claims of Kyma components cannot be bound"), nil
+	case 1:
+		return pass(name, "StorageClass '%s' is the default", defaults[0]), nil
+	}
+	return warn(name, fmt.Sprintf("Remove the annotation '%s' from all StorageClasses except one.", defaultStorageClassAnnotation),
+		"Several StorageClasses are marked as default: %s", strings.Join(defaults, ", ")), nil
+}
+
+// checkAdmissionPlugins verifies that the API server does not disable the admission plugins Kyma relies on.
+// The API server configuration is only visible if the API server runs as pod (managed control planes are assumed to use the defaults).
+func checkAdmissionPlugins(client kubernetes.Interface, _ Config) (Result, error) {
+	const name = "Admission plugins"
+	pods, err := client.CoreV1().Pods("kube-system").List(context.Background(), metav1.ListOptions{LabelSelector: "component=kube-apiserver"})
+	if err != nil {
+		return warn(name, fmt.Sprintf("Verify that the API server does not disable the admission plugins %s.", strings.Join(requiredAdmissionPlugins, ", ")),
+			"Could not read the API server configuration: %s", err), nil
+	}
+	if len(pods.Items) == 0 {
+		return pass(name, "API server configuration is not visible, the admission plugins %s are enabled by default", strings.Join(requiredAdmissionPlugins, ", ")), nil
+	}
+
+	var disabled []string
+	for _, container := range pods.Items[0].Spec.Containers {
+		for _, arg := range append(container.Command, container.Args...) {
+			if !strings.HasPrefix(arg, "--disable-admission-plugins=") {
+				continue
+			}
+			for _, plugin := range strings.Split(strings.TrimPrefix(arg, "--disable-admission-plugins="), ",") {
+				if contains(requiredAdmissionPlugins, plugin) {
+					disabled = append(disabled, plugin)
+				}
+			}
+		}
+	}
+	if len(disabled) > 0 {
+		return fail(name, "Remove the plugins from the --disable-admission-plugins flag of the API server.",
+			"The API server disables the admission plugins %s", strings.Join(disabled, ", ")), nil
+	}
+	return pass(name, "The admission plugins %s are enabled", strings.Join(requiredAdmissionPlugins, ", ")), nil
+}
+
+// checkAPIGroups verifies that the API server serves all API group versions Kyma components use
+func checkAPIGroups(client kubernetes.Interface, _ Config) (Result, error) {
+	const name = "API groups"
+	groups, err := client.Discovery().ServerGroups()
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Could not read the API groups of the cluster")
+	}
+
+	served := make(map[string]bool)
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			served[version.GroupVersion] = true
+		}
+	}
+	var missing []string
+	for _, groupVersion := range requiredAPIGroupVersions {
+		if !served[groupVersion] {
+			missing = append(missing, groupVersion)
+		}
+	}
+	if len(missing) > 0 {
+		return fail(name, "Enable the API groups with the --runtime-config flag of the API server or use a supported Kubernetes version.",
+			"The API server does not serve %s", strings.Join(missing, ", ")), nil
+	}
+	return pass(name, "All %d required API group versions are served", len(requiredAPIGroupVersions)), nil
+}
+
+// checkIstio verifies that no Istio installation exists which was not installed by Kyma.
+// A foreign Istio installation is only a warning, because Kyma takes it over and the user decides whether this is intended.
+func checkIstio(client kubernetes.Interface, _ Config) (Result, error) {
+	const name = "Istio installation"
+	deployments, err := client.AppsV1().Deployments("").List(context.Background(), metav1.ListOptions{LabelSelector: "app=istiod"})
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Could not list the Istio deployments of the cluster")
+	}
+	if len(deployments.Items) == 0 {
+		return pass(name, "No Istio installation found"), nil
+	}
+
+	_, err = client.CoreV1().Namespaces().Get(context.Background(), kymaNamespace, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return Result{}, errors.Wrapf(err, "Could not read namespace '%s'", kymaNamespace)
+	}
+	kymaInstalled := err == nil
+
+	remediation := "Uninstall the existing Istio installation (for example, with 'istioctl x uninstall --purge'). Kyma installs its own Istio."
+	for _, deployment := range deployments.Items {
+		if deployment.Namespace != istioNamespace {
+			return warn(name, remediation, "Istio installation found in namespace '%s'", deployment.Namespace), nil
+		}
+	}
+	if !kymaInstalled {
+		return warn(name, remediation, "Istio installation found in namespace '%s' which was not installed by Kyma", istioNamespace), nil
+	}
+	return pass(name, "Istio in namespace '%s' is managed by Kyma", istioNamespace), nil
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return "the default configuration"
+	}
+	return fmt.Sprintf("profile '%s'", profile)
+}
+
+func formatCPU(cpu resource.Quantity) string {
+	return fmt.Sprintf("%.1f", float64(cpu.MilliValue())/1000)
+}
+
+func formatMemory(memory resource.Quantity) string {
+	return fmt.Sprintf("%.1fGi", float64(memory.Value())/(1<<30))
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
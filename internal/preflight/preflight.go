@@ -0,0 +1,117 @@
+// Package preflight verifies that a cluster fulfills the requirements of a Kyma deployment before anything is deployed.
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	// StatusPass indicates that the cluster fulfills the requirement
+	StatusPass Status = "pass"
+	// StatusWarn indicates that the deployment can proceed but might not work as expected
+	StatusWarn Status = "warn"
+	// StatusFail indicates that the deployment will fail
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name        string `json:"name"`
+	Status      Status `json:"status"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Config defines the Kyma deployment the cluster is checked for.
+type Config struct {
+	// Source is the Kyma version, branch, or PR which is deployed
+	Source string
+	// Profile is the Kyma deployment profile (empty for the default configuration)
+	Profile string
+}
+
+// Report contains the results of all checks.
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+type check func(client kubernetes.Interface, cfg Config) (Result, error)
+
+// Run executes all checks against the cluster and returns the report.
+// An error is only returned if a check could not be executed, failed checks are part of the report.
+func Run(client kubernetes.Interface, cfg Config) (*Report, error) {
+	checks := []check{
+		checkKubernetesVersion,
+		checkNodeResources,
+		checkDefaultStorageClass,
+		checkAdmissionPlugins,
+		checkAPIGroups,
+		checkIstio,
+	}
+
+	report := &Report{Status: StatusPass}
+	for _, c := range checks {
+		result, err := c(client, cfg)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, result)
+		if result.Status == StatusFail || (result.Status == StatusWarn && report.Status == StatusPass) {
+			report.Status = result.Status
+		}
+	}
+	return report, nil
+}
+
+// Failed returns true if at least one check failed.
+func (r *Report) Failed() bool {
+	return r.Status == StatusFail
+}
+
+// WriteJSON writes the report as JSON document.
+func (r *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Could not marshal the pre-flight check report")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// PrintSteps prints each check as a step. Warnings and failures are followed by their remediation.
+func (r *Report) PrintSteps(newStep func(msg string) step.Step) {
+	for _, result := range r.Checks {
+		s := newStep(result.Name)
+		switch result.Status {
+		case StatusPass, StatusWarn:
+			s.Successf("%s: %s", result.Name, result.Message)
+		case StatusFail:
+			s.Failuref("%s: %s", result.Name, result.Message)
+		}
+		if result.Remediation != "" {
+			s.LogErrorf("%s", result.Remediation)
+		}
+	}
+}
+
+func pass(name, format string, args ...interface{}) Result {
+	return Result{Name: name, Status: StatusPass, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(name, remediation, format string, args ...interface{}) Result {
+	return Result{Name: name, Status: StatusWarn, Message: fmt.Sprintf(format, args...), Remediation: remediation}
+}
+
+func fail(name, remediation, format string, args ...interface{}) Result {
+	return Result{Name: name, Status: StatusFail, Message: fmt.Sprintf(format, args...), Remediation: remediation}
+}
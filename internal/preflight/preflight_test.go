@@ -0,0 +1,156 @@
+package preflight
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Cluster fulfills all requirements", func(t *testing.T) {
+		client := fakeCluster("v1.20.4+k3s1", node("node1", "4", "8Gi"), defaultStorageClass("local-path"))
+
+		report, err := Run(client, Config{Source: "2.0.0", Profile: "evaluation"})
+		require.NoError(t, err)
+		require.False(t, report.Failed())
+		require.Equal(t, StatusPass, report.Status)
+		require.Len(t, report.Checks, 6)
+	})
+
+	t.Run("Cluster fails requirements", func(t *testing.T) {
+		client := fakeCluster("v1.18.2",
+			node("node1", "4", "8Gi"),
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+		discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+		discovery.Resources = discovery.Resources[1:]
+
+		report, err := Run(client, Config{Source: "2.0.0", Profile: "production"})
+		require.NoError(t, err)
+		require.True(t, report.Failed())
+
+		results := make(map[string]Result)
+		for _, result := range report.Checks {
+			results[result.Name] = result
+		}
+		// only missing APIs fail the checks
+		require.Equal(t, StatusWarn, results["Kubernetes version"].Status)
+		require.Equal(t, StatusWarn, results["Node resources"].Status)
+		require.Equal(t, StatusWarn, results["Default StorageClass"].Status)
+		require.Equal(t, StatusPass, results["Admission plugins"].Status)
+		require.Equal(t, StatusFail, results["API groups"].Status)
+		require.Contains(t, results["API groups"].Message, requiredAPIGroupVersions[0])
+		require.Equal(t, StatusWarn, results["Istio installation"].Status)
+		require.NotEmpty(t, results["Istio installation"].Remediation)
+	})
+
+	t.Run("Resource shortfalls are warnings", func(t *testing.T) {
+		client := fakeCluster("v1.20.4", node("node1", "2", "4Gi"), defaultStorageClass("standard"))
+
+		report, err := Run(client, Config{Source: "2.0.0"})
+		require.NoError(t, err)
+		require.False(t, report.Failed())
+		require.Equal(t, StatusWarn, report.Checks[1].Status)
+		require.Contains(t, report.Checks[1].Message, "the default configuration requires 8.0 CPUs")
+	})
+
+	t.Run("User-defined profiles have no resource requirements", func(t *testing.T) {
+		client := fakeCluster("v1.20.4", node("node1", "2", "4Gi"), defaultStorageClass("standard"))
+
+		report, err := Run(client, Config{Source: "2.0.0", Profile: "my-team"})
+		require.NoError(t, err)
+		require.Equal(t, StatusPass, report.Status)
+		require.Equal(t, "1 schedulable nodes provide 2.0 CPUs and 4.0Gi memory, the requirements of profile 'my-team' are unknown", report.Checks[1].Message)
+	})
+
+	t.Run("Warnings", func(t *testing.T) {
+		client := fakeCluster("v1.22.0", node("node1", "8", "16Gi"), node("node2", "8", "16Gi"),
+			defaultStorageClass("standard"), defaultStorageClass("premium"),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kyma-system"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+
+		report, err := Run(client, Config{Source: "main", Profile: "production"})
+		require.NoError(t, err)
+		require.False(t, report.Failed())
+		require.Equal(t, StatusWarn, report.Status)
+
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteJSON(&buf))
+		written := &Report{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), written))
+		require.Equal(t, report, written)
+		require.Equal(t, StatusWarn, written.Checks[0].Status)
+		require.Equal(t, StatusWarn, written.Checks[1].Status)
+		require.Equal(t, StatusWarn, written.Checks[2].Status)
+		require.Equal(t, StatusPass, written.Checks[5].Status)
+	})
+}
+
+func TestCheckAdmissionPlugins(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver", Namespace: "kube-system", Labels: map[string]string{"component": "kube-apiserver"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "kube-apiserver",
+			Command: []string{"kube-apiserver", "--disable-admission-plugins=PodSecurityPolicy,ValidatingAdmissionWebhook"},
+		}}},
+	})
+
+	result, err := checkAdmissionPlugins(client, Config{})
+	require.NoError(t, err)
+	require.Equal(t, StatusFail, result.Status)
+	require.Contains(t, result.Message, "ValidatingAdmissionWebhook")
+	require.NotContains(t, result.Message, "PodSecurityPolicy")
+
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("pods is forbidden")
+	})
+	result, err = checkAdmissionPlugins(client, Config{})
+	require.NoError(t, err)
+	require.Equal(t, StatusWarn, result.Status)
+	require.Contains(t, result.Message, "pods is forbidden")
+}
+
+func fakeCluster(gitVersion string, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+	for _, groupVersion := range requiredAPIGroupVersions {
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{GroupVersion: groupVersion})
+	}
+	return client
+}
+
+func node(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func defaultStorageClass(name string) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{defaultStorageClassAnnotation: "true"},
+		},
+	}
+}
@@ -0,0 +1,145 @@
+// Package profiles resolves the Kyma deployment profiles: the built-in profiles of the Kyma charts,
+// the user-defined profiles stored as values files in the Kyma home directory, and the automatic profile selection.
+package profiles
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/kyma-project/cli/internal/preflight"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Auto selects the built-in profile which fits the capacity of the cluster
+	Auto = "auto"
+	// Evaluation is the built-in profile for small clusters
+	Evaluation = "evaluation"
+	// Production is the built-in profile for highly available clusters
+	Production = "production"
+
+	dirName = "profiles"
+)
+
+var (
+	// BuiltIn are the profiles defined by the Kyma charts
+	BuiltIn = []string{Evaluation, Production}
+	// fileExtensions are the supported extensions of user-defined profile files
+	fileExtensions = []string{".yaml", ".yml", ".json"}
+)
+
+// IsBuiltIn returns true if the profile is defined by the Kyma charts.
+func IsBuiltIn(profile string) bool {
+	for _, builtIn := range BuiltIn {
+		if builtIn == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUserDefined returns true if the profile is neither empty, nor "auto", nor a built-in profile.
+func IsUserDefined(profile string) bool {
+	return profile != "" && profile != Auto && !IsBuiltIn(profile)
+}
+
+// DefaultDir returns the directory in the Kyma home directory which contains the user-defined profiles.
+func DefaultDir() (string, error) {
+	kymaHome, err := files.KymaHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(kymaHome, dirName), nil
+}
+
+// Store provides the user-defined profiles stored as values files in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store for the user-defined profiles of the directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Validate returns an error if the profile is neither empty, nor "auto", nor a built-in or user-defined profile.
+func (s *Store) Validate(profile string) error {
+	if !IsUserDefined(profile) {
+		return nil
+	}
+	_, err := s.File(profile)
+	return err
+}
+
+// File returns the values file of a user-defined profile.
+func (s *Store) File(profile string) (string, error) {
+	// the name is part of the file path, so it must not point outside of the profile directory
+	if strings.ContainsAny(profile, `/\`) || strings.Contains(profile, "..") {
+		return "", fmt.Errorf("Profile '%s' is invalid: the name of a profile must not contain '/', '\\', or '..'", profile)
+	}
+	for _, ext := range fileExtensions {
+		file := filepath.Join(s.dir, profile+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+
+	supported := append([]string{Auto}, BuiltIn...)
+	userDefined, err := s.List()
+	if err != nil {
+		return "", err
+	}
+	supported = append(supported, userDefined...)
+	return "", fmt.Errorf("Profile '%s' is unknown. Supported profiles are: %s. To define your own profile, store its values file as '%s'",
+		profile, strings.Join(supported, ", "), filepath.Join(s.dir, profile+fileExtensions[0]))
+}
+
+// List returns the names of all user-defined profiles.
+func (s *Store) List() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read profile directory '%s'", s.dir)
+	}
+
+	var result []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !supportedExtension(ext) {
+			continue
+		}
+		result = append(result, strings.TrimSuffix(entry.Name(), ext))
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Select returns the largest built-in profile which fits the capacity of the cluster.
+// The evaluation profile is returned if the cluster is too small for the production profile.
+func Select(client kubernetes.Interface) (string, preflight.Capacity, error) {
+	capacity, err := preflight.ClusterCapacity(client)
+	if err != nil {
+		return "", capacity, err
+	}
+	if capacity.Fulfills(Production) {
+		return Production, capacity, nil
+	}
+	return Evaluation, capacity, nil
+}
+
+func supportedExtension(ext string) bool {
+	for _, supported := range fileExtensions {
+		if supported == ext {
+			return true
+		}
+	}
+	return false
+}
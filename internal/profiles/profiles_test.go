@@ -0,0 +1,92 @@
+package profiles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-profiles-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team-small.yaml"), []byte("ory:\n  hydra:\n    replicaCount: 1\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team-large.json"), []byte(`{"ory":{"hydra":{"replicaCount":3}}}`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("Team profiles"), 0600))
+
+	store := NewStore(dir)
+
+	t.Run("List user-defined profiles", func(t *testing.T) {
+		profiles, err := store.List()
+		require.NoError(t, err)
+		require.Equal(t, []string{"team-large", "team-small"}, profiles)
+	})
+
+	t.Run("Resolve profile file", func(t *testing.T) {
+		file, err := store.File("team-large")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "team-large.json"), file)
+	})
+
+	t.Run("Validate profiles", func(t *testing.T) {
+		for _, profile := range []string{"", "auto", "evaluation", "production", "team-small"} {
+			require.NoError(t, store.Validate(profile), profile)
+		}
+		err := store.Validate("team-medium")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "auto, evaluation, production, team-large, team-small")
+	})
+
+	t.Run("Profiles outside of the profile directory", func(t *testing.T) {
+		for _, profile := range []string{"../outside", "sub/team", `sub\team`, "..", "team..small"} {
+			_, err := store.File(profile)
+			require.Error(t, err, profile)
+			require.Contains(t, err.Error(), "is invalid", profile)
+			require.Error(t, store.Validate(profile), profile)
+		}
+	})
+
+	t.Run("Missing profile directory", func(t *testing.T) {
+		profiles, err := NewStore(filepath.Join(dir, "missing")).List()
+		require.NoError(t, err)
+		require.Empty(t, profiles)
+	})
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []runtime.Object
+		expected string
+	}{
+		{"Single small node", []runtime.Object{node("node1", "4", "8Gi")}, Evaluation},
+		{"Single large node", []runtime.Object{node("node1", "16", "64Gi")}, Evaluation},
+		{"Three large nodes", []runtime.Object{node("node1", "4", "16Gi"), node("node2", "4", "16Gi"), node("node3", "4", "16Gi")}, Production},
+	}
+	for _, tt := range tests {
+		profile, capacity, err := Select(fake.NewSimpleClientset(tt.nodes...))
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.expected, profile, tt.name)
+		require.Equal(t, len(tt.nodes), capacity.Nodes, tt.name)
+	}
+}
+
+func node(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
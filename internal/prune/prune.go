@@ -0,0 +1,132 @@
+// Package prune finds the resources which Kyma leaves behind in a cluster after it was deleted and removes them,
+// including the finalizers which block their deletion because the controllers which would remove them are gone.
+package prune
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// installationLabel marks the resources which belong to the Kyma installation, e.g. its namespaces
+	installationLabel          = "kyma-project.io/installation"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+	istioOwnerLabel            = "install.operator.istio.io/owning-resource"
+
+	istioGroup = "istio.io"
+	// istioDeployment is the name of the Istio control plane, which is followed by the revision if Istio is installed with one, e.g. "istiod-1-10-2"
+	istioDeployment = "istiod"
+)
+
+// Leftover is a resource which remained in the cluster after Kyma was deleted.
+type Leftover struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Reason    string
+	// Finalizers block the deletion of the resource (or of the resources it contains), so they are removed as well
+	Finalizers []string
+
+	remove func() error
+}
+
+// String returns a human-readable description of the leftover.
+func (l Leftover) String() string {
+	var sb strings.Builder
+	if l.Namespace == "" {
+		fmt.Fprintf(&sb, "%s '%s': %s", l.Kind, l.Name, l.Reason)
+	} else {
+		fmt.Fprintf(&sb, "%s '%s/%s': %s", l.Kind, l.Namespace, l.Name, l.Reason)
+	}
+	if len(l.Finalizers) > 0 {
+		fmt.Fprintf(&sb, " (finalizers: %s)", strings.Join(l.Finalizers, ", "))
+	}
+	return sb.String()
+}
+
+// Pruner finds and removes the leftovers of Kyma. Only resources which belong to Kyma are leftovers:
+// resources with the Kyma installation label, resources which were installed by Helm releases of Kyma components, and the resources in Kyma namespaces.
+// Resources which the running Istio control plane needs are kept.
+type Pruner struct {
+	static  kubernetes.Interface
+	dynamic dynamic.Interface
+}
+
+// New creates a pruner which uses the given clients.
+func New(static kubernetes.Interface, dynamic dynamic.Interface) *Pruner {
+	return &Pruner{
+		static:  static,
+		dynamic: dynamic,
+	}
+}
+
+// Find returns the leftovers in the order in which they have to be removed:
+// webhooks first, because they intercept the API calls for the other resources, and namespaces after their content.
+func (p *Pruner) Find() ([]Leftover, error) {
+	inv, err := p.inventory()
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []Leftover
+	finders := []func(*inventory) ([]Leftover, error){
+		p.findWebhooks,
+		p.findCRDs,
+		p.findClusterRBAC,
+		p.findNamespaces,
+		p.findPersistentVolumes,
+	}
+	for _, find := range finders {
+		found, err := find(inv)
+		if err != nil {
+			return nil, err
+		}
+		leftovers = append(leftovers, found...)
+	}
+	return leftovers, nil
+}
+
+// Remove removes the finalizers of the leftover and deletes it.
+// A leftover which was already deleted in the meantime is ignored.
+func (p *Pruner) Remove(l Leftover) error {
+	if err := l.remove(); err != nil {
+		return errors.Wrapf(err, "Could not remove %s '%s'", l.Kind, l.Name)
+	}
+	return nil
+}
+
+// inventory tells the resources of Kyma apart from the other resources of the cluster
+type inventory struct {
+	// kymaNamespaces are the namespaces which were created by Kyma
+	kymaNamespaces map[string]bool
+	// istioNamespaces are the namespaces in which an Istio control plane of any revision is running
+	istioNamespaces map[string]bool
+}
+
+// owner returns why a cluster-wide resource belongs to Kyma, or an empty string if it does not
+func (inv *inventory) owner(meta metav1.Object) string {
+	if _, ok := meta.GetLabels()[installationLabel]; ok {
+		return "labeled as part of Kyma"
+	}
+	if inv.kymaNamespaces[meta.GetAnnotations()[releaseNamespaceAnnotation]] {
+		return "installed by a Kyma component"
+	}
+	return ""
+}
+
+// usedByIstio returns true if a resource of Kyma is still needed by the running Istio control plane:
+// it belongs to the Istio API group, it was installed by Istio, or it was installed by a Helm release in the namespace of the control plane.
+func (inv *inventory) usedByIstio(meta metav1.Object, group string) bool {
+	if len(inv.istioNamespaces) == 0 {
+		return false
+	}
+	if _, ok := meta.GetLabels()[istioOwnerLabel]; ok {
+		return true
+	}
+	return strings.HasSuffix(group, istioGroup) || inv.istioNamespaces[meta.GetAnnotations()[releaseNamespaceAnnotation]]
+}
@@ -0,0 +1,251 @@
+package prune
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	functionGVR       = schema.GroupVersionResource{Group: "serverless.kyma-project.io", Version: "v1alpha1", Resource: "functions"}
+	virtualServiceGVR = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}
+	configMapGVR      = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+func TestFind(t *testing.T) {
+	t.Run("Leftovers of Kyma", func(t *testing.T) {
+		objects, dynObjects := leftoverObjects()
+		p, _, _ := newPruner(objects, dynObjects...)
+		leftovers, err := p.Find()
+		require.NoError(t, err)
+
+		var descriptions []string
+		for _, l := range leftovers {
+			descriptions = append(descriptions, l.String())
+		}
+		require.Equal(t, []string{
+			"ValidatingWebhookConfiguration 'serverless': calls deleted service 'kyma-system/serverless-webhook'",
+			"CustomResourceDefinition 'functions.serverless.kyma-project.io': labeled as part of Kyma, 1 custom resources (finalizers: serverless.kyma-project.io/deletion-hook)",
+			"CustomResourceDefinition 'virtualservices.networking.istio.io': installed by a Kyma component, 0 custom resources",
+			"ClusterRole 'serverless': installed by a Kyma component",
+			"Namespace 'istio-system': Kyma namespace",
+			"Namespace 'kyma-integration': Kyma namespace",
+			"ConfigMap 'kyma-system/locked': blocks namespace deletion (finalizers: example.com/lock)",
+			"Namespace 'kyma-system': stuck in Terminating (finalizers: kubernetes)",
+			"PersistentVolume 'pv-1': released, claimed by 'kyma-system/data'",
+		}, descriptions)
+	})
+
+	t.Run("Resources of installed Istio are kept", func(t *testing.T) {
+		istioAnnotations := map[string]string{"meta.helm.sh/release-name": "istio", releaseNamespaceAnnotation: "istio-system"}
+		tests := []struct {
+			name      string
+			istiod    *appsv1.Deployment
+			leftovers []string
+		}{
+			{
+				name:   "istiod",
+				istiod: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"}},
+			},
+			{
+				name:   "Revisioned istiod",
+				istiod: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod-1-10-2", Namespace: "istio-system"}},
+			},
+			{
+				name:      "istiod in another namespace",
+				istiod:    &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "mesh"}},
+				leftovers: []string{"ClusterRole 'istiod-istio-system': installed by a Kyma component", "Namespace 'istio-system': Kyma namespace"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p, _, _ := newPruner(
+					[]runtime.Object{
+						tt.istiod,
+						kymaNamespace("istio-system", false),
+						&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "istiod-istio-system", Annotations: istioAnnotations}},
+					},
+					owned(crd("virtualservices.networking.istio.io", virtualServiceGVR, "VirtualService"), nil, istioAnnotations),
+				)
+				leftovers, err := p.Find()
+				require.NoError(t, err)
+
+				var descriptions []string
+				for _, l := range leftovers {
+					descriptions = append(descriptions, l.String())
+				}
+				require.Equal(t, tt.leftovers, descriptions)
+			})
+		}
+	})
+
+	t.Run("Clean cluster", func(t *testing.T) {
+		p, _, _ := newPruner([]runtime.Object{namespace("default", false)})
+		leftovers, err := p.Find()
+		require.NoError(t, err)
+		require.Empty(t, leftovers)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	objects, dynObjects := leftoverObjects()
+	p, static, dynamic := newPruner(objects, dynObjects...)
+	leftovers, err := p.Find()
+	require.NoError(t, err)
+	for _, l := range leftovers {
+		require.NoError(t, p.Remove(l))
+	}
+	// removing leftovers which are already gone is no error
+	for _, l := range leftovers {
+		require.NoError(t, p.Remove(l))
+	}
+
+	ctx := context.Background()
+	_, err = static.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "serverless", metav1.GetOptions{})
+	require.True(t, k8sErrors.IsNotFound(err))
+	_, err = dynamic.Resource(crdGVR).Get(ctx, "functions.serverless.kyma-project.io", metav1.GetOptions{})
+	require.True(t, k8sErrors.IsNotFound(err))
+	function, err := dynamic.Resource(functionGVR).Namespace("default").Get(ctx, "hello", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, function.GetFinalizers())
+	_, err = static.RbacV1().ClusterRoles().Get(ctx, "serverless", metav1.GetOptions{})
+	require.True(t, k8sErrors.IsNotFound(err))
+	configMap, err := dynamic.Resource(configMapGVR).Namespace("kyma-system").Get(ctx, "locked", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, configMap.GetFinalizers())
+	ns, err := static.CoreV1().Namespaces().Get(ctx, "kyma-system", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, ns.Spec.Finalizers)
+	_, err = static.CoreV1().Namespaces().Get(ctx, "kyma-integration", metav1.GetOptions{})
+	require.True(t, k8sErrors.IsNotFound(err))
+	_, err = static.CoreV1().PersistentVolumes().Get(ctx, "pv-1", metav1.GetOptions{})
+	require.True(t, k8sErrors.IsNotFound(err))
+}
+
+func newPruner(objects []runtime.Object, dynObjects ...runtime.Object) (*Pruner, *fake.Clientset, *dynFake.FakeDynamicClient) {
+	static := fake.NewSimpleClientset(objects...)
+	static.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: metav1.Verbs{"list", "patch"}}},
+	}}
+	dynamic := dynFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			crdGVR:            "CustomResourceDefinitionList",
+			functionGVR:       "FunctionList",
+			virtualServiceGVR: "VirtualServiceList",
+			configMapGVR:      "ConfigMapList",
+		}, dynObjects...)
+	return New(static, dynamic), static, dynamic
+}
+
+// leftoverObjects returns the static and dynamic objects of a cluster with leftovers of a deleted Kyma installation and some unrelated resources
+func leftoverObjects() ([]runtime.Object, []runtime.Object) {
+	kymaAnnotations := map[string]string{"meta.helm.sh/release-name": "serverless", releaseNamespaceAnnotation: "kyma-system"}
+	istioAnnotations := map[string]string{"meta.helm.sh/release-name": "istio", releaseNamespaceAnnotation: "istio-system"}
+	integration := namespace("kyma-integration", false)
+	integration.Annotations = map[string]string{"meta.helm.sh/release-name": "cluster-essentials", releaseNamespaceAnnotation: "kyma-system"}
+	objects := []runtime.Object{
+		namespace("default", false),
+		kymaNamespace("kyma-system", true),
+		kymaNamespace("istio-system", false),
+		integration,
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other-webhook", Namespace: "kyma-system"}},
+		webhook("serverless", "kyma-system", "serverless-webhook"),
+		webhook("other", "kyma-system", "other-webhook"),
+		webhook("custom", "default", "custom-webhook"),
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "serverless", Annotations: kymaAnnotations}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "admin"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "istio-reader", Labels: map[string]string{istioOwnerLabel: "installed-state"}}},
+		persistentVolume("pv-1", "kyma-system", corev1.VolumeReleased),
+		persistentVolume("pv-2", "default", corev1.VolumeReleased),
+	}
+
+	function := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serverless.kyma-project.io/v1alpha1",
+		"kind":       "Function",
+		"metadata":   map[string]interface{}{"name": "hello", "namespace": "default"},
+	}}
+	function.SetFinalizers([]string{"serverless.kyma-project.io/deletion-hook"})
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "locked", "namespace": "kyma-system"},
+	}}
+	configMap.SetFinalizers([]string{"example.com/lock"})
+	dynObjects := []runtime.Object{
+		owned(crd("functions.serverless.kyma-project.io", functionGVR, "Function"), map[string]string{installationLabel: ""}, nil),
+		owned(crd("virtualservices.networking.istio.io", virtualServiceGVR, "VirtualService"), nil, istioAnnotations),
+		// resources of Istio or of the Kyma API groups which were not installed by Kyma are kept
+		crd("gateways.networking.istio.io", schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "gateways"}, "Gateway"),
+		crd("applications.applicationconnector.kyma-project.io", schema.GroupVersionResource{Group: "applicationconnector.kyma-project.io", Version: "v1alpha1", Resource: "applications"}, "Application"),
+		crd("certificates.cert-manager.io", schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}, "Certificate"),
+		function,
+		configMap,
+	}
+	return objects, dynObjects
+}
+
+func namespace(name string, terminating bool) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}
+	if terminating {
+		ns.Spec.Finalizers = []corev1.FinalizerName{corev1.FinalizerKubernetes}
+		ns.Status.Phase = corev1.NamespaceTerminating
+	}
+	return ns
+}
+
+func kymaNamespace(name string, terminating bool) *corev1.Namespace {
+	ns := namespace(name, terminating)
+	ns.Labels = map[string]string{installationLabel: ""}
+	return ns
+}
+
+func webhook(name, namespace, service string) *admissionv1.ValidatingWebhookConfiguration {
+	return &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks: []admissionv1.ValidatingWebhook{{
+			Name:         name + ".example.com",
+			ClientConfig: admissionv1.WebhookClientConfig{Service: &admissionv1.ServiceReference{Namespace: namespace, Name: service}},
+		}},
+	}
+}
+
+func persistentVolume(name, claimNamespace string, phase corev1.PersistentVolumePhase) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: claimNamespace, Name: "data"}},
+		Status:     corev1.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func crd(name string, gvr schema.GroupVersionResource, kind string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"group":    gvr.Group,
+			"names":    map[string]interface{}{"plural": gvr.Resource, "kind": kind},
+			"versions": []interface{}{map[string]interface{}{"name": gvr.Version, "served": true, "storage": true}},
+		},
+	}}
+}
+
+// owned sets the labels and annotations which tell the owner of a resource
+func owned(obj *unstructured.Unstructured, labels, annotations map[string]string) *unstructured.Unstructured {
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
@@ -0,0 +1,431 @@
+package prune
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// removeFinalizersPatch sets the finalizers of a resource to null, so that the API server can complete its deletion
+var removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+// inventory finds the namespaces of Kyma and of the running Istio control planes
+func (p *Pruner) inventory() (*inventory, error) {
+	ctx := context.Background()
+	inv := &inventory{kymaNamespaces: make(map[string]bool), istioNamespaces: make(map[string]bool)}
+
+	deployments, err := p.static.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not check whether Istio is installed")
+	}
+	for _, d := range deployments.Items {
+		if strings.HasPrefix(d.Name, istioDeployment) {
+			inv.istioNamespaces[d.Namespace] = true
+		}
+	}
+
+	namespaces, err := p.static.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the namespaces of the cluster")
+	}
+	for _, ns := range namespaces.Items {
+		if _, ok := ns.Labels[installationLabel]; ok {
+			inv.kymaNamespaces[ns.Name] = true
+		}
+	}
+	// namespaces which are installed by a Helm release of Kyma belong to Kyma as well, and so do the ones installed by their releases
+	for found := true; found; {
+		found = false
+		for _, ns := range namespaces.Items {
+			if !inv.kymaNamespaces[ns.Name] && inv.kymaNamespaces[ns.Annotations[releaseNamespaceAnnotation]] {
+				inv.kymaNamespaces[ns.Name] = true
+				found = true
+			}
+		}
+	}
+	return inv, nil
+}
+
+// findWebhooks returns the admission webhook configurations which call a deleted service of a Kyma namespace.
+// Such webhooks fail all API calls for the resources they intercept.
+func (p *Pruner) findWebhooks(inv *inventory) ([]Leftover, error) {
+	ctx := context.Background()
+	var leftovers []Leftover
+
+	mutating, err := p.static.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the MutatingWebhookConfigurations of the cluster")
+	}
+	for _, config := range mutating.Items {
+		var clientConfigs []admissionv1.WebhookClientConfig
+		for _, webhook := range config.Webhooks {
+			clientConfigs = append(clientConfigs, webhook.ClientConfig)
+		}
+		reason, err := p.deletedWebhookService(inv, clientConfigs)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			name := config.Name
+			leftovers = append(leftovers, Leftover{Kind: "MutatingWebhookConfiguration", Name: name, Reason: reason, remove: func() error {
+				return ignoreNotFound(p.static.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(ctx, name, metav1.DeleteOptions{}))
+			}})
+		}
+	}
+
+	validating, err := p.static.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the ValidatingWebhookConfigurations of the cluster")
+	}
+	for _, config := range validating.Items {
+		var clientConfigs []admissionv1.WebhookClientConfig
+		for _, webhook := range config.Webhooks {
+			clientConfigs = append(clientConfigs, webhook.ClientConfig)
+		}
+		reason, err := p.deletedWebhookService(inv, clientConfigs)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			name := config.Name
+			leftovers = append(leftovers, Leftover{Kind: "ValidatingWebhookConfiguration", Name: name, Reason: reason, remove: func() error {
+				return ignoreNotFound(p.static.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(ctx, name, metav1.DeleteOptions{}))
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+// deletedWebhookService returns a reason if a webhook calls a service of a Kyma namespace which does not exist anymore
+func (p *Pruner) deletedWebhookService(inv *inventory, clientConfigs []admissionv1.WebhookClientConfig) (string, error) {
+	for _, clientConfig := range clientConfigs {
+		svc := clientConfig.Service
+		if svc == nil || !inv.kymaNamespaces[svc.Namespace] {
+			continue
+		}
+		_, err := p.static.CoreV1().Services(svc.Namespace).Get(context.Background(), svc.Name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			return fmt.Sprintf("calls deleted service '%s/%s'", svc.Namespace, svc.Name), nil
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "Could not get service '%s/%s'", svc.Namespace, svc.Name)
+		}
+	}
+	return "", nil
+}
+
+// findCRDs returns the CustomResourceDefinitions which belong to Kyma, unless the running Istio control plane needs them.
+// The custom resources of a CRD are removed together with it, so their finalizers are reported for the CRD.
+func (p *Pruner) findCRDs(inv *inventory) ([]Leftover, error) {
+	crds, err := p.dynamic.Resource(crdGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the CustomResourceDefinitions of the cluster")
+	}
+
+	sort.Slice(crds.Items, func(i, j int) bool { return crds.Items[i].GetName() < crds.Items[j].GetName() })
+
+	var leftovers []Leftover
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		reason := inv.owner(crd)
+		if reason == "" || inv.usedByIstio(crd, group) {
+			continue
+		}
+
+		crs, err := p.customResources(crd)
+		if err != nil {
+			return nil, err
+		}
+		reason = fmt.Sprintf("%s, %d custom resources", reason, len(crs))
+		if crd.GetDeletionTimestamp() != nil {
+			reason += ", stuck in deletion"
+		}
+
+		name := crd.GetName()
+		gvr := customResourceGVR(crd)
+		leftovers = append(leftovers, Leftover{Kind: "CustomResourceDefinition", Name: name, Reason: reason, Finalizers: finalizersOf(crs), remove: func() error {
+			for _, cr := range crs {
+				if len(cr.GetFinalizers()) == 0 {
+					continue
+				}
+				if err := p.removeFinalizers(gvr, cr.GetNamespace(), cr.GetName()); err != nil {
+					return err
+				}
+			}
+			return ignoreNotFound(p.dynamic.Resource(crdGVR).Delete(context.Background(), name, metav1.DeleteOptions{}))
+		}})
+	}
+	return leftovers, nil
+}
+
+// customResources returns the custom resources of a CRD in all namespaces
+func (p *Pruner) customResources(crd *unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	gvr := customResourceGVR(crd)
+	if gvr.Version == "" {
+		return nil, nil
+	}
+	crs, err := p.dynamic.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+	if k8sErrors.IsNotFound(err) {
+		// the CRD is not served (anymore), so it cannot have custom resources
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not list the custom resources of CustomResourceDefinition '%s'", crd.GetName())
+	}
+	return crs.Items, nil
+}
+
+// customResourceGVR returns the resource of a CRD in its storage version
+func customResourceGVR(crd *unstructured.Unstructured) schema.GroupVersionResource {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	gvr := schema.GroupVersionResource{Group: group, Resource: plural}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := version["storage"].(bool); storage {
+			gvr.Version, _ = version["name"].(string)
+		}
+	}
+	return gvr
+}
+
+// findClusterRBAC returns the ClusterRoles and ClusterRoleBindings which belong to Kyma, unless the running Istio control plane needs them
+func (p *Pruner) findClusterRBAC(inv *inventory) ([]Leftover, error) {
+	ctx := context.Background()
+	reason := func(meta metav1.Object) string {
+		if inv.usedByIstio(meta, "") {
+			return ""
+		}
+		return inv.owner(meta)
+	}
+	var leftovers []Leftover
+
+	roles, err := p.static.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the ClusterRoles of the cluster")
+	}
+	for i := range roles.Items {
+		role := &roles.Items[i]
+		if r := reason(role); r != "" {
+			name := role.Name
+			leftovers = append(leftovers, Leftover{Kind: "ClusterRole", Name: name, Reason: r, remove: func() error {
+				return ignoreNotFound(p.static.RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{}))
+			}})
+		}
+	}
+
+	bindings, err := p.static.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the ClusterRoleBindings of the cluster")
+	}
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if r := reason(binding); r != "" {
+			name := binding.Name
+			leftovers = append(leftovers, Leftover{Kind: "ClusterRoleBinding", Name: name, Reason: r, remove: func() error {
+				return ignoreNotFound(p.static.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}))
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+// findNamespaces returns the Kyma namespaces which still exist, preceded by the resources in them whose finalizers would block their deletion.
+// The namespaces in which an Istio control plane is running are kept.
+func (p *Pruner) findNamespaces(inv *inventory) ([]Leftover, error) {
+	ctx := context.Background()
+	resourceLists, err := discovery.ServerPreferredNamespacedResources(p.static.Discovery())
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, errors.Wrap(err, "Could not discover the resources of the cluster")
+	}
+
+	var names []string
+	for name := range inv.kymaNamespaces {
+		if !inv.istioNamespaces[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var leftovers []Leftover
+	for i := range names {
+		name := names[i]
+		ns, err := p.static.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not get namespace '%s'", name)
+		}
+
+		leftovers = append(leftovers, p.findFinalizedResources(resourceLists, name)...)
+
+		terminating := ns.Status.Phase == corev1.NamespaceTerminating
+		reason := "Kyma namespace"
+		var finalizers []string
+		if terminating {
+			reason = "stuck in Terminating"
+			for _, f := range ns.Spec.Finalizers {
+				finalizers = append(finalizers, string(f))
+			}
+		}
+		leftovers = append(leftovers, Leftover{Kind: "Namespace", Name: name, Reason: reason, Finalizers: finalizers, remove: func() error {
+			if !terminating {
+				return ignoreNotFound(p.static.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{}))
+			}
+			ns, err := p.static.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+			if k8sErrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// a namespace stuck in Terminating is only deleted once the finalizers of its spec are removed
+			ns.Spec.Finalizers = nil
+			_, err = p.static.CoreV1().Namespaces().Finalize(ctx, ns, metav1.UpdateOptions{})
+			return ignoreNotFound(err)
+		}})
+	}
+	return leftovers, nil
+}
+
+// findFinalizedResources returns the resources of a namespace which have finalizers.
+// Their controllers were deleted together with Kyma, so nobody would remove the finalizers and the namespace would be stuck in Terminating.
+func (p *Pruner) findFinalizedResources(resourceLists []*metav1.APIResourceList, namespace string) []Leftover {
+	var leftovers []Leftover
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if !hasVerbs(resource.Verbs, "list", "patch") {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			resources, err := p.dynamic.Resource(gvr).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				// resources which cannot be listed (e.g. forbidden ones) are skipped
+				continue
+			}
+			for _, r := range resources.Items {
+				if len(r.GetFinalizers()) == 0 {
+					continue
+				}
+				name := r.GetName()
+				leftovers = append(leftovers, Leftover{Kind: resource.Kind, Namespace: namespace, Name: name, Reason: "blocks namespace deletion", Finalizers: r.GetFinalizers(), remove: func() error {
+					return p.removeFinalizers(gvr, namespace, name)
+				}})
+			}
+		}
+	}
+	sort.SliceStable(leftovers, func(i, j int) bool {
+		if leftovers[i].Kind != leftovers[j].Kind {
+			return leftovers[i].Kind < leftovers[j].Kind
+		}
+		return leftovers[i].Name < leftovers[j].Name
+	})
+	return leftovers
+}
+
+func hasVerbs(verbs metav1.Verbs, required ...string) bool {
+	for _, r := range required {
+		found := false
+		for _, v := range verbs {
+			if v == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// findPersistentVolumes returns the PersistentVolumes which were claimed in a Kyma namespace.
+// Volumes with the "Retain" reclaim policy remain in the cluster after their claims were deleted.
+func (p *Pruner) findPersistentVolumes(inv *inventory) ([]Leftover, error) {
+	ctx := context.Background()
+	pvs, err := p.static.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list the PersistentVolumes of the cluster")
+	}
+
+	var leftovers []Leftover
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		claim := pv.Spec.ClaimRef
+		if claim == nil || !inv.kymaNamespaces[claim.Namespace] {
+			continue
+		}
+		reason := fmt.Sprintf("claimed by '%s/%s'", claim.Namespace, claim.Name)
+		if pv.Status.Phase != "" {
+			reason = fmt.Sprintf("%s, %s", strings.ToLower(string(pv.Status.Phase)), reason)
+		}
+		var finalizers []string
+		stuck := pv.DeletionTimestamp != nil
+		if stuck {
+			reason += ", stuck in deletion"
+			finalizers = pv.Finalizers
+		}
+		name := pv.Name
+		leftovers = append(leftovers, Leftover{Kind: "PersistentVolume", Name: name, Reason: reason, Finalizers: finalizers, remove: func() error {
+			if stuck {
+				_, err := p.static.CoreV1().PersistentVolumes().Patch(ctx, name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{})
+				return ignoreNotFound(err)
+			}
+			return ignoreNotFound(p.static.CoreV1().PersistentVolumes().Delete(ctx, name, metav1.DeleteOptions{}))
+		}})
+	}
+	return leftovers, nil
+}
+
+// removeFinalizers removes all finalizers of a resource (a resource which does not exist anymore is ignored)
+func (p *Pruner) removeFinalizers(gvr schema.GroupVersionResource, namespace, name string) error {
+	_, err := p.dynamic.Resource(gvr).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{})
+	return ignoreNotFound(err)
+}
+
+// finalizersOf returns the distinct finalizers of the resources, sorted by name
+func finalizersOf(resources []unstructured.Unstructured) []string {
+	seen := make(map[string]bool)
+	var finalizers []string
+	for _, r := range resources {
+		for _, f := range r.GetFinalizers() {
+			if !seen[f] {
+				seen[f] = true
+				finalizers = append(finalizers, f)
+			}
+		}
+	}
+	sort.Strings(finalizers)
+	return finalizers
+}
+
+func ignoreNotFound(err error) error {
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,314 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+)
+
+// Change describes how a resource is modified by a deployment.
+type Change string
+
+const (
+	// Added resources are rendered but not part of the installed release
+	Added Change = "added"
+	// Changed resources are part of the installed release (or exist in the cluster) but will be modified
+	Changed Change = "changed"
+	// Removed resources are part of the installed release but not rendered anymore
+	Removed Change = "removed"
+)
+
+const hookAnnotation = "helm.sh/hook"
+
+var (
+	docSeparator  = regexp.MustCompile(`(?m)^---\s*$`)
+	workloadKinds = map[string]bool{"Deployment": true, "StatefulSet": true, "DaemonSet": true}
+)
+
+// ResourceDiff contains the difference of a single Kubernetes resource between two manifests.
+type ResourceDiff struct {
+	// Resource identifies the resource as 'Kind/[namespace/]name'
+	Resource string
+	Kind     string
+	Change   Change
+	// Restart is true if the pod template of a workload changes, which causes its pods to be recreated
+	Restart bool
+	// Diff is the unified diff of the resource
+	Diff string
+}
+
+// LiveGetter returns the live object of a resource of a manifest, or nil if the resource does not exist in the cluster.
+type LiveGetter func(res map[string]interface{}) (map[string]interface{}, error)
+
+// DiffLive compares the live objects in the cluster with a rendered manifest, so that manual changes of the cluster show up as well.
+// The live objects of all resources of the installed release and of the rendered manifest are fetched with the getter.
+// Only the fields which either manifest sets are compared: fields which the API server defaults or which only the status contains are ignored.
+// Helm hooks are ignored, because they usually do not exist after the deployment.
+func DiffLive(installed, rendered string, get LiveGetter) ([]ResourceDiff, error) {
+	installedRes, err := parseManifest(installed)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse the manifest of the installed release")
+	}
+	renderedRes, err := parseManifest(rendered)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse the rendered manifest")
+	}
+	removeHooks(installedRes)
+	removeHooks(renderedRes)
+
+	liveRes := make(map[string]map[string]interface{})
+	for _, resources := range []map[string]map[string]interface{}{installedRes, renderedRes} {
+		for id, res := range resources {
+			if _, fetched := liveRes[id]; fetched {
+				continue
+			}
+			live, err := get(res)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Could not get the live object of resource '%s'", id)
+			}
+			if live == nil {
+				// the resource does not exist in the cluster
+				liveRes[id] = nil
+				continue
+			}
+			if live, err = normalize(live); err != nil {
+				return nil, err
+			}
+			shape := mergeShapes(installedRes[id], renderedRes[id])
+			liveRes[id], _ = project(live, shape).(map[string]interface{})
+		}
+	}
+	for id, res := range liveRes {
+		if res == nil {
+			delete(liveRes, id)
+		}
+	}
+	return diffResources(liveRes, renderedRes)
+}
+
+// diffResources compares the live resources with the rendered ones
+func diffResources(liveRes, renderedRes map[string]map[string]interface{}) ([]ResourceDiff, error) {
+	var diffs []ResourceDiff
+	for id, newRes := range renderedRes {
+		oldRes, exists := liveRes[id]
+		if !exists {
+			d, err := resourceDiff(id, nil, newRes, Added)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, d)
+			continue
+		}
+		if reflect.DeepEqual(oldRes, newRes) {
+			continue
+		}
+		d, err := resourceDiff(id, oldRes, newRes, Changed)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, d)
+	}
+	for id, oldRes := range liveRes {
+		if _, exists := renderedRes[id]; exists {
+			continue
+		}
+		d, err := resourceDiff(id, oldRes, nil, Removed)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Resource < diffs[j].Resource
+	})
+	return diffs, nil
+}
+
+// removeHooks removes the resources which are Helm hooks
+func removeHooks(resources map[string]map[string]interface{}) {
+	for id, res := range resources {
+		metadata, _ := res["metadata"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if _, hook := annotations[hookAnnotation]; hook {
+			delete(resources, id)
+		}
+	}
+}
+
+// mergeShapes returns the union of the fields of both objects (the values are irrelevant, only the structure is used)
+func mergeShapes(a, b interface{}) interface{} {
+	switch aTyped := a.(type) {
+	case map[string]interface{}:
+		bTyped, ok := b.(map[string]interface{})
+		if !ok {
+			return a
+		}
+		merged := make(map[string]interface{}, len(aTyped))
+		for key, value := range aTyped {
+			merged[key] = mergeShapes(value, bTyped[key])
+		}
+		for key, value := range bTyped {
+			if _, exists := aTyped[key]; !exists {
+				merged[key] = value
+			}
+		}
+		return merged
+	case []interface{}:
+		bTyped, ok := b.([]interface{})
+		if !ok {
+			return a
+		}
+		merged := make([]interface{}, 0, len(aTyped))
+		for i := 0; i < len(aTyped) || i < len(bTyped); i++ {
+			switch {
+			case i >= len(aTyped):
+				merged = append(merged, bTyped[i])
+			case i >= len(bTyped):
+				merged = append(merged, aTyped[i])
+			default:
+				merged = append(merged, mergeShapes(aTyped[i], bTyped[i]))
+			}
+		}
+		return merged
+	case nil:
+		return b
+	}
+	return a
+}
+
+// project returns the fields of the live object which the shape contains
+func project(live, shape interface{}) interface{} {
+	switch shapeTyped := shape.(type) {
+	case map[string]interface{}:
+		liveTyped, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		projected := make(map[string]interface{})
+		for key, value := range shapeTyped {
+			if liveValue, exists := liveTyped[key]; exists {
+				projected[key] = project(liveValue, value)
+			}
+		}
+		return projected
+	case []interface{}:
+		liveTyped, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		projected := make([]interface{}, 0, len(liveTyped))
+		for i, liveValue := range liveTyped {
+			if i < len(shapeTyped) {
+				liveValue = project(liveValue, shapeTyped[i])
+			}
+			projected = append(projected, liveValue)
+		}
+		return projected
+	}
+	return live
+}
+
+// normalize converts the live object the same way as parsed manifests are converted (e.g. all numbers become float64)
+func normalize(obj map[string]interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not convert live object to YAML")
+	}
+	normalized := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &normalized); err != nil {
+		return nil, errors.Wrap(err, "Could not parse live object")
+	}
+	return normalized, nil
+}
+
+func resourceDiff(id string, oldRes, newRes map[string]interface{}, change Change) (ResourceDiff, error) {
+	oldYAML, err := toYAML(oldRes)
+	if err != nil {
+		return ResourceDiff{}, err
+	}
+	newYAML, err := toYAML(newRes)
+	if err != nil {
+		return ResourceDiff{}, err
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(oldYAML),
+		B:        splitLines(newYAML),
+		FromFile: fmt.Sprintf("live/%s", id),
+		ToFile:   fmt.Sprintf("rendered/%s", id),
+		Context:  3,
+	})
+	if err != nil {
+		return ResourceDiff{}, errors.Wrapf(err, "Could not create diff of resource '%s'", id)
+	}
+
+	kind := strings.SplitN(id, "/", 2)[0]
+	return ResourceDiff{
+		Resource: id,
+		Kind:     kind,
+		Change:   change,
+		Restart:  change == Changed && workloadKinds[kind] && !reflect.DeepEqual(podTemplate(oldRes), podTemplate(newRes)),
+		Diff:     diff,
+	}, nil
+}
+
+// parseManifest splits a multi-document manifest into resources indexed by their identifier.
+func parseManifest(manifest string) (map[string]map[string]interface{}, error) {
+	resources := make(map[string]map[string]interface{})
+	for _, doc := range docSeparator.Split(manifest, -1) {
+		res := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(doc), &res); err != nil {
+			return nil, err
+		}
+		if len(res) == 0 {
+			// document contains only comments
+			continue
+		}
+		resources[resourceID(res)] = res
+	}
+	return resources, nil
+}
+
+func resourceID(res map[string]interface{}) string {
+	kind, _ := res["kind"].(string)
+	metadata, _ := res["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if namespace, ok := metadata["namespace"].(string); ok && namespace != "" {
+		return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+func podTemplate(res map[string]interface{}) interface{} {
+	spec, _ := res["spec"].(map[string]interface{})
+	return spec["template"]
+}
+
+// splitLines splits a YAML document into lines (each line keeps its line break)
+func splitLines(doc string) []string {
+	if doc == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(doc, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func toYAML(res map[string]interface{}) (string, error) {
+	if res == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(res)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not convert resource to YAML")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const liveManifest = `---
+# Source: comp/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: comp
+  namespace: kyma-system
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: comp
+        image: comp:1
+---
+# Source: comp/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old
+data:
+  a: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+  - port: 80
+`
+
+const renderedManifest = `---
+# Source: comp/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: comp
+  namespace: kyma-system
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: comp
+        image: comp:2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+    - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new
+`
+
+func TestDiffLive(t *testing.T) {
+	liveObjects := map[string]map[string]interface{}{
+		// scaled manually, plus fields defaulted by the API server
+		"Deployment/kyma-system/comp": {
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":        "comp",
+				"namespace":   "kyma-system",
+				"uid":         "1234",
+				"annotations": map[string]interface{}{"meta.helm.sh/release-name": "comp"},
+			},
+			"spec": map[string]interface{}{
+				"replicas":             int64(3),
+				"revisionHistoryLimit": int64(10),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "comp", "image": "comp:1", "imagePullPolicy": "IfNotPresent"}},
+					},
+				},
+			},
+			"status": map[string]interface{}{"replicas": int64(3)},
+		},
+		// unchanged, apart from defaulted fields
+		"Service/svc": {
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "svc", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"clusterIP": "10.0.0.1",
+				"ports":     []interface{}{map[string]interface{}{"port": int64(80), "protocol": "TCP"}},
+			},
+		},
+	}
+	var fetched []string
+	get := func(res map[string]interface{}) (map[string]interface{}, error) {
+		id := resourceID(res)
+		fetched = append(fetched, id)
+		return liveObjects[id], nil
+	}
+
+	hook := `---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-upgrade
+`
+	diffs, err := DiffLive(liveManifest, renderedManifest+hook, get)
+	require.NoError(t, err)
+	require.NotContains(t, fetched, "Job/migrate")
+	// the manually deleted ConfigMap "old" is not rendered anymore, so it does not show up
+	require.Len(t, diffs, 2)
+
+	require.Equal(t, "ConfigMap/new", diffs[0].Resource)
+	require.Equal(t, Added, diffs[0].Change)
+
+	require.Equal(t, "Deployment/kyma-system/comp", diffs[1].Resource)
+	require.Equal(t, Changed, diffs[1].Change)
+	require.True(t, diffs[1].Restart)
+	require.Contains(t, diffs[1].Diff, "--- live/Deployment/kyma-system/comp")
+	require.Contains(t, diffs[1].Diff, "-  replicas: 3")
+	require.Contains(t, diffs[1].Diff, "+  replicas: 1")
+	require.Contains(t, diffs[1].Diff, "+      - image: comp:2")
+	require.NotContains(t, diffs[1].Diff, "imagePullPolicy")
+	require.NotContains(t, diffs[1].Diff, "uid")
+}
@@ -0,0 +1,182 @@
+// Package render provides the rendering of Kyma component charts into plain Kubernetes manifests without touching a cluster.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const globalOverridesKey = "global"
+
+// Manifest contains the rendered Kubernetes resources of a Kyma component.
+type Manifest struct {
+	Component installConfig.ComponentDefinition
+	Content   string
+}
+
+// Renderer renders Kyma component charts the same way the deployment would apply them.
+type Renderer struct {
+	// ResourcePath is the folder containing the component charts
+	ResourcePath string
+	// Profile is the Kyma deployment profile whose values are merged into the chart values
+	Profile string
+	// Overrides are the merged overrides (one top-level key per component plus the global overrides)
+	Overrides map[string]interface{}
+}
+
+// RenderAll renders the prerequisites and the components of the component list (in this order).
+func (r *Renderer) RenderAll(compList *installConfig.ComponentList) ([]Manifest, error) {
+	comps := append([]installConfig.ComponentDefinition{}, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+
+	var manifests []Manifest
+	for _, comp := range comps {
+		content, err := r.Render(comp)
+		if err != nil {
+			return manifests, err
+		}
+		manifests = append(manifests, Manifest{Component: comp, Content: content})
+	}
+	return manifests, nil
+}
+
+// Render renders the chart of a single component and returns its manifest (including CRDs and hooks).
+func (r *Renderer) Render(comp installConfig.ComponentDefinition) (string, error) {
+	return r.RenderWithValues(comp, ComponentValues(r.Overrides, comp.Name))
+}
+
+// RenderWithValues renders the chart of a single component with the given values instead of the overrides of the renderer.
+func (r *Renderer) RenderWithValues(comp installConfig.ComponentDefinition, values map[string]interface{}) (string, error) {
+	ch, err := r.Chart(comp)
+	if err != nil {
+		return "", err
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.ReleaseName = comp.Name
+	install.Namespace = comp.Namespace
+
+	rel, err := install.Run(ch, values)
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not render chart of component '%s'", comp.Name)
+	}
+
+	return ReleaseContent(rel), nil
+}
+
+// Values returns the values the chart of a component is rendered with:
+// the overrides coalesced with the default values of the chart and its subcharts.
+func (r *Renderer) Values(comp installConfig.ComponentDefinition) (map[string]interface{}, error) {
+	ch, err := r.Chart(comp)
+	if err != nil {
+		return nil, err
+	}
+	values, err := chartutil.CoalesceValues(ch, ComponentValues(r.Overrides, comp.Name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not merge values of component '%s'", comp.Name)
+	}
+	return values.AsMap(), nil
+}
+
+// Chart loads the chart of a component and applies the profile.
+func (r *Renderer) Chart(comp installConfig.ComponentDefinition) (*chart.Chart, error) {
+	ch, err := loader.Load(filepath.Join(r.ResourcePath, comp.Name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not load chart of component '%s'", comp.Name)
+	}
+	if err := mergeProfileValues(ch, r.Profile); err != nil {
+		return nil, errors.Wrapf(err, "Could not apply profile '%s' to chart of component '%s'", r.Profile, comp.Name)
+	}
+	return ch, nil
+}
+
+// ReleaseContent returns the manifest of a Helm release including its hooks.
+func ReleaseContent(rel *release.Release) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, rel.Manifest)
+	for _, hook := range rel.Hooks {
+		fmt.Fprintf(&buf, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return buf.String()
+}
+
+// ComponentValues returns the values passed to the chart of a component:
+// the component specific overrides plus the global overrides.
+func ComponentValues(overrides map[string]interface{}, component string) map[string]interface{} {
+	values := make(map[string]interface{})
+	if compOverrides, ok := overrides[component].(map[string]interface{}); ok {
+		for key, value := range compOverrides {
+			values[key] = value
+		}
+	}
+	if globalOverrides, ok := overrides[globalOverridesKey]; ok {
+		values[globalOverridesKey] = globalOverrides
+	}
+	return values
+}
+
+// ReuseValues merges the values of an installed release into the new values, the same way Helm upgrades a release with "--reuse-values":
+// the new values take precedence. The new values are not modified.
+func ReuseValues(values, releaseValues map[string]interface{}) map[string]interface{} {
+	return chartutil.CoalesceTables(copyValues(values), releaseValues)
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyValues(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// Write stores each manifest as '<component>.yaml' in the given directory.
+func Write(dir string, manifests []Manifest) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrapf(err, "Could not create directory '%s'", dir)
+	}
+	for _, manifest := range manifests {
+		file := filepath.Join(dir, fmt.Sprintf("%s.yaml", manifest.Component.Name))
+		if err := ioutil.WriteFile(file, []byte(manifest.Content), 0600); err != nil {
+			return errors.Wrapf(err, "Could not write manifest of component '%s'", manifest.Component.Name)
+		}
+	}
+	return nil
+}
+
+// mergeProfileValues merges the values of the profile file (e.g. 'profile-evaluation.yaml') into the chart values.
+// Profile values take precedence over the default chart values.
+func mergeProfileValues(ch *chart.Chart, profile string) error {
+	if profile == "" {
+		return nil
+	}
+	for _, file := range ch.Files {
+		if file.Name != fmt.Sprintf("profile-%s.yaml", profile) && file.Name != fmt.Sprintf("values-%s.yaml", profile) {
+			continue
+		}
+		profileValues, err := chartutil.ReadValues(file.Data)
+		if err != nil {
+			return err
+		}
+		ch.Values = chartutil.CoalesceTables(profileValues.AsMap(), ch.Values)
+		return nil
+	}
+	return nil
+}
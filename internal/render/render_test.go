@@ -0,0 +1,132 @@
+package render
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentValues(t *testing.T) {
+	overrides := map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "local.kyma.dev",
+		},
+		"comp1": map[string]interface{}{
+			"replicas": 3,
+		},
+		"comp2": map[string]interface{}{
+			"replicas": 5,
+		},
+	}
+
+	t.Run("Component with overrides", func(t *testing.T) {
+		values := ComponentValues(overrides, "comp1")
+		require.Equal(t, map[string]interface{}{
+			"replicas": 3,
+			"global": map[string]interface{}{
+				"domainName": "local.kyma.dev",
+			},
+		}, values)
+	})
+	t.Run("Component without overrides", func(t *testing.T) {
+		values := ComponentValues(overrides, "comp3")
+		require.Equal(t, map[string]interface{}{
+			"global": map[string]interface{}{
+				"domainName": "local.kyma.dev",
+			},
+		}, values)
+	})
+}
+
+func TestRender(t *testing.T) {
+	comp := installConfig.ComponentDefinition{Name: "test-component", Namespace: "kyma-system"}
+
+	t.Run("Render with default values", func(t *testing.T) {
+		r := &Renderer{ResourcePath: filepath.Join("testdata", "resources")}
+		manifest, err := r.Render(comp)
+		require.NoError(t, err)
+		require.Contains(t, manifest, "namespace: kyma-system")
+		require.Contains(t, manifest, "replicas: 1")
+		require.Contains(t, manifest, "domain: kyma.example.com")
+	})
+	t.Run("Render with profile", func(t *testing.T) {
+		r := &Renderer{ResourcePath: filepath.Join("testdata", "resources"), Profile: "evaluation"}
+		manifest, err := r.Render(comp)
+		require.NoError(t, err)
+		require.Contains(t, manifest, "replicas: 2")
+	})
+	t.Run("Overrides take precedence over profile", func(t *testing.T) {
+		r := &Renderer{
+			ResourcePath: filepath.Join("testdata", "resources"),
+			Profile:      "evaluation",
+			Overrides: map[string]interface{}{
+				"global":         map[string]interface{}{"domainName": "local.kyma.dev"},
+				"test-component": map[string]interface{}{"replicas": 3},
+			},
+		}
+		manifest, err := r.Render(comp)
+		require.NoError(t, err)
+		require.Contains(t, manifest, "replicas: 3")
+		require.Contains(t, manifest, "domain: local.kyma.dev")
+	})
+	t.Run("Unknown component", func(t *testing.T) {
+		r := &Renderer{ResourcePath: filepath.Join("testdata", "resources")}
+		_, err := r.Render(installConfig.ComponentDefinition{Name: "xyz", Namespace: "kyma-system"})
+		require.Error(t, err)
+	})
+}
+
+func TestValues(t *testing.T) {
+	comp := installConfig.ComponentDefinition{Name: "test-component", Namespace: "kyma-system"}
+	r := &Renderer{
+		ResourcePath: filepath.Join("testdata", "resources"),
+		Profile:      "evaluation",
+		Overrides: map[string]interface{}{
+			"test-component": map[string]interface{}{"image": "registry.corp.local/kyma/test-component:1.0.0"},
+		},
+	}
+
+	values, err := r.Values(comp)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, values["replicas"])
+	require.Equal(t, "registry.corp.local/kyma/test-component:1.0.0", values["image"])
+	require.Equal(t, map[string]interface{}{"domainName": "kyma.example.com"}, values["global"])
+}
+
+func TestReuseValues(t *testing.T) {
+	values := map[string]interface{}{
+		"global": map[string]interface{}{"domainName": "local.kyma.dev"},
+	}
+	releaseValues := map[string]interface{}{
+		"replicas": 3,
+		"global":   map[string]interface{}{"domainName": "kyma.example.com", "tlsCrt": "abc"},
+	}
+
+	merged := ReuseValues(values, releaseValues)
+	require.Equal(t, map[string]interface{}{
+		"replicas": 3,
+		"global":   map[string]interface{}{"domainName": "local.kyma.dev", "tlsCrt": "abc"},
+	}, merged)
+	// the new values are not modified
+	require.Equal(t, map[string]interface{}{"domainName": "local.kyma.dev"}, values["global"])
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-render-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	manifests := []Manifest{
+		{Component: installConfig.ComponentDefinition{Name: "comp1"}, Content: "content1"},
+		{Component: installConfig.ComponentDefinition{Name: "comp2"}, Content: "content2"},
+	}
+	require.NoError(t, Write(filepath.Join(dir, "manifests"), manifests))
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "manifests", "comp2.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "content2", string(content))
+}
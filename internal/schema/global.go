@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// globalSchema is the JSON schema of the global values which are shared by all Kyma components.
+// Only the types of the known keys are verified: components can define additional global values.
+const globalSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "domainName": {
+      "description": "Domain of the Kyma cluster",
+      "type": "string",
+      "pattern": "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+    },
+    "tlsCrt": {
+      "description": "Base64-encoded TLS certificate of the domain",
+      "type": "string",
+      "pattern": "^[A-Za-z0-9+/=\\s]*$"
+    },
+    "tlsKey": {
+      "description": "Base64-encoded TLS key of the domain",
+      "type": "string",
+      "pattern": "^[A-Za-z0-9+/=\\s]*$"
+    },
+    "containerRegistry": {
+      "type": "object",
+      "properties": {
+        "path": {
+          "description": "Registry path of the Kyma container images",
+          "type": "string"
+        }
+      }
+    }
+  }
+}`
+
+// validateGlobal validates the global overrides against the global schema
+func validateGlobal(values interface{}) ([]Violation, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(globalSchema), gojsonschema.NewGoLoader(values))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not validate the global values")
+	}
+	violations := resultViolations(result, "")
+	for i := range violations {
+		violations[i].Component = globalOverridesKey
+	}
+	return violations, nil
+}
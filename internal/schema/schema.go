@@ -0,0 +1,268 @@
+// Package schema validates the overrides of a Kyma deployment against the component charts before anything is deployed.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-project/cli/internal/render"
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const (
+	globalOverridesKey = "global"
+	// rootField is the field name gojsonschema uses for errors of the root object
+	rootField = "(root)"
+	// additionalPropertyError is the gojsonschema error type of keys which are not allowed by the schema
+	additionalPropertyError = "additional_property_not_allowed"
+)
+
+// Violation describes an override which does not match the chart of a component.
+type Violation struct {
+	// Component is the name of the component or "global" for global overrides
+	Component string
+	// Key is the path of the override within the component values, e.g. "hydra.replicaCount"
+	Key string
+	// Message describes the problem
+	Message string
+	// Source is the origin of the override, e.g. a values file or a command line flag (empty for chart default values)
+	Source string
+	// Warning is true if the override does not necessarily break the deployment:
+	// a key which is not defined by the default values can still be used by the templates of the chart
+	Warning bool
+}
+
+func (v Violation) String() string {
+	if v.Source == "" {
+		return fmt.Sprintf("%s: %s", joinKey(v.Component, v.Key), v.Message)
+	}
+	return fmt.Sprintf("%s: %s (set by %s)", joinKey(v.Component, v.Key), v.Message, v.Source)
+}
+
+// Validate validates the overrides of the renderer against the charts of all components of the component list:
+// overrides which are defined neither by the default values nor by the values schema of the chart are reported as warnings,
+// and the merged values must match the values schemas of the chart and its subcharts.
+// The global overrides are validated against the schema of the global values maintained by the CLI.
+// The sources are used to find the origin of each violation, later sources take precedence.
+func Validate(renderer *render.Renderer, compList *installConfig.ComponentList, sources []Source) ([]Violation, error) {
+	violations, err := validateGlobal(renderer.Overrides[globalOverridesKey])
+	if err != nil {
+		return nil, err
+	}
+
+	defaultsRenderer := &render.Renderer{ResourcePath: renderer.ResourcePath, Profile: renderer.Profile}
+	comps := append([]installConfig.ComponentDefinition{}, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+	for _, comp := range comps {
+		ch, err := renderer.Chart(comp)
+		if err != nil {
+			return nil, err
+		}
+		defaults, err := defaultsRenderer.Values(comp)
+		if err != nil {
+			return nil, err
+		}
+		values, err := renderer.Values(comp)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := parseSchema(ch)
+		if err != nil {
+			return nil, err
+		}
+		overrides, _ := renderer.Overrides[comp.Name].(map[string]interface{})
+		for _, key := range unknownKeys(overrides, defaults, schema, "") {
+			violations = append(violations, Violation{Component: comp.Name, Key: key, Message: "unknown key", Warning: true})
+		}
+
+		schemaViolations, err := validateChart(ch, values, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, violation := range schemaViolations {
+			violation.Component = comp.Name
+			violations = append(violations, violation)
+		}
+	}
+
+	return withSources(deduplicate(violations), sources), nil
+}
+
+// validateChart validates the values against the schema of the chart and the schemas of its subcharts
+func validateChart(ch *chart.Chart, values map[string]interface{}, prefix string) ([]Violation, error) {
+	var violations []Violation
+	if len(ch.Schema) > 0 {
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(ch.Schema), gojsonschema.NewGoLoader(values))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not validate values against the schema of chart '%s'", ch.Name())
+		}
+		violations = append(violations, resultViolations(result, prefix)...)
+	}
+	for _, dep := range ch.Dependencies() {
+		depValues, ok := values[dep.Name()].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		depViolations, err := validateChart(dep, depValues, joinKey(prefix, dep.Name()))
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, depViolations...)
+	}
+	return violations, nil
+}
+
+// resultViolations converts the errors of a schema validation into violations
+func resultViolations(result *gojsonschema.Result, prefix string) []Violation {
+	var violations []Violation
+	for _, resultErr := range result.Errors() {
+		key := resultErr.Field()
+		if key == rootField {
+			key = ""
+		}
+		message := resultErr.Description()
+		if resultErr.Type() == additionalPropertyError {
+			key = joinKey(key, fmt.Sprintf("%v", resultErr.Details()["property"]))
+			message = "unknown key"
+		}
+		violations = append(violations, Violation{Key: joinKey(prefix, key), Message: message})
+	}
+	return violations
+}
+
+// unknownKeys returns the keys of the overrides which are neither defined by the default values nor by the schema.
+// Nested keys are only verified if the default value is a non-empty map: empty maps (e.g. annotations) accept any key.
+func unknownKeys(overrides, defaults, schema map[string]interface{}, prefix string) []string {
+	var result []string
+	for key, value := range overrides {
+		if prefix == "" && key == globalOverridesKey {
+			// global overrides are shared by all components and validated separately
+			continue
+		}
+		defaultValue, defined := defaults[key]
+		keySchema := schemaProperty(schema, key)
+		if !defined && keySchema == nil {
+			result = append(result, joinKey(prefix, key))
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		defaultNested, defaultIsMap := defaultValue.(map[string]interface{})
+		if isMap && defaultIsMap && len(defaultNested) > 0 && !allowsAdditionalKeys(keySchema) {
+			result = append(result, unknownKeys(nested, defaultNested, keySchema, joinKey(prefix, key))...)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// withSources sets the source of each violation to the last source which defines the key
+func withSources(violations []Violation, sources []Source) []Violation {
+	for i, violation := range violations {
+		keys := append([]string{violation.Component}, splitKey(violation.Key)...)
+		for j := len(sources) - 1; j >= 0; j-- {
+			if sources[j].defines(keys) {
+				violations[i].Source = sources[j].Name
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// deduplicate removes violations of the same key (e.g. unknown keys reported by the default values and by the schema).
+// Errors take precedence over warnings.
+func deduplicate(violations []Violation) []Violation {
+	var result []Violation
+	seen := make(map[string]int)
+	for _, violation := range violations {
+		id := joinKey(violation.Component, violation.Key)
+		if i, ok := seen[id]; ok {
+			if result[i].Warning && !violation.Warning {
+				result[i] = violation
+			}
+			continue
+		}
+		seen[id] = len(result)
+		result = append(result, violation)
+	}
+	return result
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + "." + key
+}
+
+func splitKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(key, ".")
+}
+
+// lookup returns the value of the nested key (list items are addressed by their index)
+func lookup(value interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// parseSchema returns the values schema of the chart or nil if the chart has no schema
+func parseSchema(ch *chart.Chart) (map[string]interface{}, error) {
+	if len(ch.Schema) == 0 {
+		return nil, nil
+	}
+	schema := make(map[string]interface{})
+	if err := json.Unmarshal(ch.Schema, &schema); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse the values schema of chart '%s'", ch.Name())
+	}
+	return schema, nil
+}
+
+// schemaProperty returns the schema of a property or nil if the schema does not define the property
+func schemaProperty(schema map[string]interface{}, key string) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	property, _ := properties[key].(map[string]interface{})
+	return property
+}
+
+// allowsAdditionalKeys returns true if the schema accepts keys which it does not define
+func allowsAdditionalKeys(schema map[string]interface{}) bool {
+	if _, ok := schema["patternProperties"]; ok {
+		return true
+	}
+	switch additional := schema["additionalProperties"].(type) {
+	case bool:
+		return additional
+	case map[string]interface{}:
+		return true
+	}
+	return false
+}
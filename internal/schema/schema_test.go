@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-project/cli/internal/render"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	compList := &installConfig.ComponentList{
+		Components: []installConfig.ComponentDefinition{
+			{Name: "test-component", Namespace: "kyma-system"},
+		},
+	}
+
+	t.Run("Valid overrides", func(t *testing.T) {
+		renderer := &render.Renderer{
+			ResourcePath: filepath.Join("testdata", "resources"),
+			Overrides: map[string]interface{}{
+				"global": map[string]interface{}{
+					"domainName": "local.kyma.dev",
+					"custom":     true,
+				},
+				"test-component": map[string]interface{}{
+					"replicas":       3,
+					"logLevel":       "debug",
+					"image":          map[string]interface{}{"tag": "2.0.0"},
+					"podAnnotations": map[string]interface{}{"sidecar.istio.io/inject": "false"},
+					"sub":            map[string]interface{}{"port": 9090},
+				},
+				"other-component": map[string]interface{}{
+					"anything": "goes",
+				},
+			},
+		}
+		violations, err := Validate(renderer, compList, nil)
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+
+	t.Run("Invalid overrides", func(t *testing.T) {
+		file := Source{
+			Name: "values file 'values.yaml'",
+			Values: map[string]interface{}{
+				"global": map[string]interface{}{
+					"domainName": "Local_Kyma",
+				},
+				"test-component": map[string]interface{}{
+					"replicas": "three",
+					"imag":     "foo",
+					"image":    map[string]interface{}{"tagg": "2.0.0"},
+				},
+			},
+		}
+		flag := Source{
+			Name: "flag --value 'test-component.sub.port=http'",
+			Values: map[string]interface{}{
+				"test-component": map[string]interface{}{
+					"sub": map[string]interface{}{"port": "http"},
+				},
+			},
+		}
+		renderer := &render.Renderer{
+			ResourcePath: filepath.Join("testdata", "resources"),
+			Overrides: map[string]interface{}{
+				"global": map[string]interface{}{
+					"domainName": "Local_Kyma",
+				},
+				"test-component": map[string]interface{}{
+					"replicas": "three",
+					"imag":     "foo",
+					"image":    map[string]interface{}{"tagg": "2.0.0"},
+					"sub":      map[string]interface{}{"port": "http", "prt": 80},
+					"logLevel": "trace",
+				},
+			},
+		}
+
+		violations, err := Validate(renderer, compList, []Source{file, flag})
+		require.NoError(t, err)
+
+		result := make(map[string]Violation)
+		for _, violation := range violations {
+			result[joinKey(violation.Component, violation.Key)] = violation
+		}
+		require.Len(t, result, 7)
+		require.Equal(t, Violation{Component: "test-component", Key: "imag", Message: "unknown key", Source: file.Name, Warning: true}, result["test-component.imag"])
+		require.Equal(t, Violation{Component: "test-component", Key: "image.tagg", Message: "unknown key", Source: file.Name, Warning: true}, result["test-component.image.tagg"])
+		require.Equal(t, Violation{Component: "test-component", Key: "sub.prt", Message: "unknown key", Warning: true}, result["test-component.sub.prt"])
+		require.Equal(t, file.Name, result["test-component.replicas"].Source)
+		require.Contains(t, result["test-component.replicas"].Message, "Invalid type")
+		require.False(t, result["test-component.replicas"].Warning)
+		require.Equal(t, flag.Name, result["test-component.sub.port"].Source)
+		require.Contains(t, result["test-component.sub.port"].Message, "Invalid type")
+		require.Contains(t, result, "test-component.logLevel")
+		require.Equal(t, file.Name, result["global.domainName"].Source)
+		require.Equal(t, "test-component.imag: unknown key (set by values file 'values.yaml')", result["test-component.imag"].String())
+	})
+
+	t.Run("Keys used only by the templates are warnings", func(t *testing.T) {
+		renderer := &render.Renderer{
+			ResourcePath: filepath.Join("testdata", "resources"),
+			Overrides: map[string]interface{}{
+				"test-component": map[string]interface{}{
+					"nodeSelector": map[string]interface{}{"pool": "kyma"},
+				},
+			},
+		}
+		violations, err := Validate(renderer, compList, nil)
+		require.NoError(t, err)
+		require.Equal(t, []Violation{{Component: "test-component", Key: "nodeSelector", Message: "unknown key", Warning: true}}, violations)
+
+		// the value is deployed nevertheless
+		manifest, err := renderer.Render(compList.Components[0])
+		require.NoError(t, err)
+		require.Contains(t, manifest, "pool: kyma")
+	})
+}
+
+func TestFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-schema-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "values.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("ory:\n  hydra:\n    replicas: 2\n"), 0600))
+
+	source, err := FileSource(file)
+	require.NoError(t, err)
+	require.Equal(t, "values file '"+file+"'", source.Name)
+	require.True(t, source.defines([]string{"ory", "hydra", "replicas"}))
+	require.False(t, source.defines([]string{"ory", "oathkeeper"}))
+
+	_, err = FileSource(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Source is a set of overrides together with its origin, for example, a values file or a command line flag.
+type Source struct {
+	// Name describes the origin, e.g. "values file 'values.yaml'" or "flag --value 'ory.hydra.replicaCount=2'"
+	Name string
+	// Values are the overrides with a top-level key per component plus the global overrides
+	Values map[string]interface{}
+}
+
+// FileSource reads the overrides of a JSON or YAML values file.
+func FileSource(file string) (Source, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return Source{}, errors.Wrapf(err, "Could not read values file '%s'", file)
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return Source{}, errors.Wrapf(err, "Could not parse values file '%s'", file)
+	}
+	return Source{Name: fmt.Sprintf("values file '%s'", file), Values: values}, nil
+}
+
+// defines returns true if the source sets a value for the nested key
+func (s Source) defines(keys []string) bool {
+	_, ok := lookup(s.Values, keys)
+	return ok
+}
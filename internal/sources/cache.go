@@ -0,0 +1,432 @@
+// Package sources provides a local cache of Kyma sources.
+//
+// The sources of each Kyma revision are downloaded only once and stored in a folder named after the resolved commit SHA,
+// so they can be reused by all following deployments and upgrades.
+package sources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// KymaRepositoryURL is the URL of the Kyma repository
+	KymaRepositoryURL = "https://github.com/kyma-project/kyma"
+
+	indexFile         = "index.yaml"
+	lockFile          = "index.lock"
+	downloadDirPrefix = ".download-"
+	prunedDirPrefix   = ".pruned-"
+
+	// staleDownloadAge is the age after which a folder which is not in the index is considered a leftover of an interrupted download.
+	// Younger folders may belong to a download of a concurrent deployment.
+	staleDownloadAge = 24 * time.Hour
+
+	// the lock is only held while the index is changed and folders are moved, so an older lock is a leftover of a crashed process
+	staleLockAge      = time.Minute
+	lockTimeout       = 2 * time.Minute
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// Source describes Kyma sources stored in the cache.
+type Source struct {
+	// Commit is the SHA of the commit the sources are checked out at
+	Commit string `yaml:"commit"`
+	// Revisions which were resolved to this commit (e.g. release versions, branches or pull requests)
+	Revisions []string `yaml:"revisions"`
+	// Aliases are object SHAs resolving to the commit (e.g. annotated tags)
+	Aliases  []string  `yaml:"aliases,omitempty"`
+	Created  time.Time `yaml:"created"`
+	LastUsed time.Time `yaml:"lastUsed"`
+	// Path is the folder containing the sources
+	Path string `yaml:"-"`
+}
+
+// Size returns the disk usage of the sources in bytes.
+func (s *Source) Size() (int64, error) {
+	var size int64
+	err := filepath.Walk(s.Path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func (s *Source) hasRevision(revision string) bool {
+	for _, rev := range s.Revisions {
+		if rev == revision {
+			return true
+		}
+	}
+	return false
+}
+
+type index struct {
+	Sources []*Source `yaml:"sources"`
+}
+
+// Cache manages the Kyma sources stored in a local folder.
+type Cache struct {
+	dir     string
+	repoURL string
+
+	// functions interacting with the remote repository (replaceable in tests)
+	resolveRef func(repoURL, revision string) (string, error)
+	clone      func(repoURL, dir, revision string) error
+	headCommit func(dir string) (string, error)
+	now        func() time.Time
+}
+
+// DefaultDir returns the default folder of the source cache.
+func DefaultDir() (string, error) {
+	kymaHome, err := files.KymaHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(kymaHome, "cache", "sources"), nil
+}
+
+// NewCache creates a cache which stores the sources of the given repository in the given folder.
+func NewCache(dir, repoURL string) *Cache {
+	return &Cache{
+		dir:        dir,
+		repoURL:    repoURL,
+		resolveRef: resolveRemoteRef,
+		clone:      git.CloneRepo,
+		headCommit: HeadCommit,
+		now:        time.Now,
+	}
+}
+
+// Get returns the sources of the given revision.
+// The revision is resolved to a commit SHA and the sources are only downloaded if this commit is not cached yet.
+// The returned flag is true if the sources were downloaded.
+func (c *Cache) Get(revision string) (*Source, bool, error) {
+	idx, err := c.loadIndex()
+	if err != nil {
+		return nil, false, err
+	}
+
+	commit, err := c.resolve(idx, revision)
+	if err != nil {
+		// the remote repository is not reachable: fall back to the sources cached for this revision
+		src := c.findRevision(idx, revision)
+		if src == nil {
+			return nil, false, err
+		}
+		commit = src.Commit
+	}
+
+	var src *Source
+	err = c.update(func(idx *index) error {
+		src = c.use(idx, commit, revision)
+		return nil
+	})
+	if err != nil || src != nil {
+		return src, false, err
+	}
+
+	// the download takes a while, so the index is not locked meanwhile
+	tmpDir, downloaded, err := c.download(revision)
+	if err != nil {
+		return nil, false, err
+	}
+	defer os.RemoveAll(tmpDir)
+	err = c.update(func(idx *index) error {
+		if src, err = c.add(idx, revision, tmpDir, downloaded); err != nil {
+			return err
+		}
+		if commit != "" && commit != src.Commit && c.find(idx, commit) == nil {
+			src.Aliases = append(src.Aliases, commit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return src, true, nil
+}
+
+// List returns all cached sources, the most recently used first.
+func (c *Cache) List() ([]*Source, error) {
+	idx, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(idx.Sources, func(i, j int) bool {
+		return idx.Sources[i].LastUsed.After(idx.Sources[j].LastUsed)
+	})
+	return idx.Sources, nil
+}
+
+// Prune deletes all sources which were not used since the given time,
+// including incomplete downloads and folders which are not tracked by the cache.
+// Untracked folders younger than a day are kept, because they may be downloads of a concurrent deployment.
+// It returns the deleted sources.
+func (c *Cache) Prune(unusedSince time.Time) ([]*Source, error) {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "Could not create source cache folder '%s'", c.dir)
+	}
+	// the folders are moved out of the cache while the index is locked and deleted afterwards
+	trashDir, err := ioutil.TempDir(c.dir, prunedDirPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create folder for the pruned sources")
+	}
+	defer os.RemoveAll(trashDir)
+
+	var pruned []*Source
+	err = c.update(func(idx *index) error {
+		var kept []*Source
+		for _, src := range idx.Sources {
+			if !src.LastUsed.Before(unusedSince) {
+				kept = append(kept, src)
+				continue
+			}
+			if err := os.Rename(src.Path, filepath.Join(trashDir, src.Commit)); err != nil {
+				return errors.Wrapf(err, "Could not delete sources of commit '%s'", src.Commit)
+			}
+			pruned = append(pruned, src)
+		}
+		idx.Sources = kept
+
+		// delete leftovers of interrupted downloads, but keep the ones which may still be in progress
+		entries, err := ioutil.ReadDir(c.dir)
+		if err != nil {
+			return errors.Wrapf(err, "Could not read source cache folder '%s'", c.dir)
+		}
+		staleBefore := c.now().Add(-staleDownloadAge)
+		for _, entry := range entries {
+			path := filepath.Join(c.dir, entry.Name())
+			if !entry.IsDir() || path == trashDir || c.find(idx, entry.Name()) != nil || !entry.ModTime().Before(staleBefore) {
+				continue
+			}
+			if err := os.Rename(path, filepath.Join(trashDir, entry.Name())); err != nil {
+				return errors.Wrapf(err, "Could not delete folder '%s' from source cache", entry.Name())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// resolve returns the commit SHA of a revision.
+// A revision which looks like a commit SHA is only used as such if no branch or tag of the remote repository has this name.
+// An empty SHA is returned if the revision can only be resolved by downloading the sources (e.g. an abbreviated commit SHA).
+func (c *Cache) resolve(idx *index, revision string) (string, error) {
+	commit, err := c.resolveRef(c.repoURL, revision)
+	if err != nil || commit != "" || !isCommit(revision) {
+		return commit, err
+	}
+	if len(revision) == commitLength {
+		return revision, nil
+	}
+	for _, src := range idx.Sources {
+		if strings.HasPrefix(src.Commit, revision) {
+			return src.Commit, nil
+		}
+	}
+	return "", nil
+}
+
+// download clones the sources of a revision into a temporary folder of the cache and returns the folder and the commit SHA of the sources
+func (c *Cache) download(revision string) (string, string, error) {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return "", "", errors.Wrapf(err, "Could not create source cache folder '%s'", c.dir)
+	}
+	tmpDir, err := ioutil.TempDir(c.dir, downloadDirPrefix)
+	if err != nil {
+		return "", "", errors.Wrap(err, "Could not create download folder in source cache")
+	}
+	if err := c.clone(c.repoURL, tmpDir, revision); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", "", errors.Wrapf(err, "Could not download Kyma sources of revision '%s'", revision)
+	}
+	commit, err := c.headCommit(tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", "", err
+	}
+	return tmpDir, commit, nil
+}
+
+// use returns the cached source of a commit and records that it was used for the revision, or nil if the commit is not cached
+func (c *Cache) use(idx *index, commit, revision string) *Source {
+	src := c.find(idx, commit)
+	if src == nil {
+		return nil
+	}
+	if !src.hasRevision(revision) {
+		src.Revisions = append(src.Revisions, revision)
+	}
+	src.LastUsed = c.now()
+	return src
+}
+
+// add moves the downloaded sources of a commit into the cache and adds them to the index.
+// If the commit was cached in the meantime, e.g. by a concurrent deployment, the cached sources are used instead.
+func (c *Cache) add(idx *index, revision, tmpDir, commit string) (*Source, error) {
+	if src := c.use(idx, commit, revision); src != nil {
+		return src, nil
+	}
+
+	path := c.path(commit)
+	if _, err := os.Stat(path); err == nil {
+		// the folder was moved into the cache, but not added to the index (e.g. the process was interrupted).
+		// It is not deleted, because another process may use it.
+		if head, err := c.headCommit(path); err != nil || head != commit {
+			return nil, errors.Errorf("Could not move Kyma sources into source cache folder '%s': the folder exists already", path)
+		}
+	} else if err := os.Rename(tmpDir, path); err != nil {
+		return nil, errors.Wrapf(err, "Could not move Kyma sources into source cache folder '%s'", path)
+	}
+
+	now := c.now()
+	src := &Source{Commit: commit, Revisions: []string{revision}, Created: now, LastUsed: now, Path: path}
+	idx.Sources = append(idx.Sources, src)
+	return src, nil
+}
+
+// find returns the cached source of a commit or nil if the commit is not cached
+func (c *Cache) find(idx *index, commit string) *Source {
+	if commit == "" {
+		return nil
+	}
+	for _, src := range idx.Sources {
+		if src.Commit == commit {
+			return src
+		}
+		for _, alias := range src.Aliases {
+			if alias == commit {
+				return src
+			}
+		}
+	}
+	return nil
+}
+
+// findRevision returns the most recently used source of a revision or nil if the revision is not cached
+func (c *Cache) findRevision(idx *index, revision string) *Source {
+	var result *Source
+	for _, src := range idx.Sources {
+		if src.hasRevision(revision) && (result == nil || src.LastUsed.After(result.LastUsed)) {
+			result = src
+		}
+	}
+	return result
+}
+
+func (c *Cache) path(commit string) string {
+	return filepath.Join(c.dir, commit)
+}
+
+// loadIndex reads the index of the cache and drops all sources whose folder does not exist anymore
+func (c *Cache) loadIndex() (*index, error) {
+	idx := &index{}
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, indexFile))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read index of source cache")
+	}
+	if err := yaml.Unmarshal(data, idx); err != nil {
+		return nil, errors.Wrap(err, "Could not parse index of source cache")
+	}
+
+	var existing []*Source
+	for _, src := range idx.Sources {
+		src.Path = c.path(src.Commit)
+		if _, err := os.Stat(src.Path); err == nil {
+			existing = append(existing, src)
+		}
+	}
+	idx.Sources = existing
+	return idx, nil
+}
+
+// update changes the index while holding the lock of the cache, so that concurrent processes do not lose each other's changes
+func (c *Cache) update(change func(idx *index) error) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	idx, err := c.loadIndex()
+	if err != nil {
+		return err
+	}
+	if err := change(idx); err != nil {
+		return err
+	}
+	return c.saveIndex(idx)
+}
+
+// lock creates the lock file of the cache and returns the function which removes it.
+// It waits while another process holds the lock and removes the locks of crashed processes.
+func (c *Cache) lock() (func(), error) {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "Could not create source cache folder '%s'", c.dir)
+	}
+	path := filepath.Join(c.dir, lockFile)
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrap(err, "Could not lock source cache")
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("Could not lock source cache: the lock file '%s' is held by another process", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// saveIndex replaces the index of the cache at once, so that an interrupted write does not leave a truncated index
+func (c *Cache) saveIndex(idx *index) error {
+	data, err := yaml.Marshal(idx)
+	if err != nil {
+		return errors.Wrap(err, "Could not serialize index of source cache")
+	}
+	tmp, err := ioutil.TempFile(c.dir, indexFile+".")
+	if err != nil {
+		return errors.Wrap(err, "Could not write index of source cache")
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, indexFile))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "Could not write index of source cache")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package sources
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	commit1 = "1111111111111111111111111111111111111111"
+	commit2 = "2222222222222222222222222222222222222222"
+)
+
+type fakeRepo struct {
+	refs      map[string]string
+	offline   bool
+	downloads int
+}
+
+func (r *fakeRepo) cache(dir string, now time.Time) *Cache {
+	return &Cache{
+		dir:     dir,
+		repoURL: KymaRepositoryURL,
+		resolveRef: func(_, revision string) (string, error) {
+			if r.offline {
+				return "", errors.New("repository not reachable")
+			}
+			return r.refs[revision], nil
+		},
+		clone: func(_, dir, revision string) error {
+			r.downloads++
+			commit, ok := r.refs[revision]
+			if !ok {
+				return errors.Errorf("unknown revision '%s'", revision)
+			}
+			return ioutil.WriteFile(filepath.Join(dir, "HEAD"), []byte(commit), 0600)
+		},
+		headCommit: func(dir string) (string, error) {
+			head, err := ioutil.ReadFile(filepath.Join(dir, "HEAD"))
+			return string(head), err
+		},
+		now: func() time.Time { return now },
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-sources-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repo := &fakeRepo{refs: map[string]string{
+		"1.24.0": commit1,
+		"main":   commit1,
+		"PR-123": commit2,
+	}}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	today := time.Now()
+
+	t.Run("Download sources", func(t *testing.T) {
+		src, downloaded, err := repo.cache(dir, yesterday).Get("1.24.0")
+		require.NoError(t, err)
+		require.True(t, downloaded)
+		require.Equal(t, commit1, src.Commit)
+		require.Equal(t, filepath.Join(dir, commit1), src.Path)
+		require.DirExists(t, src.Path)
+	})
+	t.Run("Reuse sources of the same commit", func(t *testing.T) {
+		src, downloaded, err := repo.cache(dir, yesterday).Get("main")
+		require.NoError(t, err)
+		require.False(t, downloaded)
+		require.Equal(t, []string{"1.24.0", "main"}, src.Revisions)
+		require.Equal(t, 1, repo.downloads)
+	})
+	t.Run("Use cached sources if repository is not reachable", func(t *testing.T) {
+		repo.offline = true
+		defer func() { repo.offline = false }()
+		src, downloaded, err := repo.cache(dir, yesterday).Get("main")
+		require.NoError(t, err)
+		require.False(t, downloaded)
+		require.Equal(t, commit1, src.Commit)
+
+		_, _, err = repo.cache(dir, yesterday).Get("PR-123")
+		require.Error(t, err)
+	})
+	t.Run("Resolve abbreviated commit from cache", func(t *testing.T) {
+		src, downloaded, err := repo.cache(dir, yesterday).Get(commit1[:8])
+		require.NoError(t, err)
+		require.False(t, downloaded)
+		require.Equal(t, commit1, src.Commit)
+	})
+	t.Run("Resolve branches which look like a commit", func(t *testing.T) {
+		repo.refs["deadbeef"] = commit2
+		defer delete(repo.refs, "deadbeef")
+		src, _, err := repo.cache(dir, yesterday).Get("deadbeef")
+		require.NoError(t, err)
+		require.Equal(t, commit2, src.Commit)
+	})
+	t.Run("List sources", func(t *testing.T) {
+		_, _, err := repo.cache(dir, today).Get("PR-123")
+		require.NoError(t, err)
+
+		srcs, err := repo.cache(dir, today).List()
+		require.NoError(t, err)
+		require.Len(t, srcs, 2)
+		require.Equal(t, commit2, srcs[0].Commit)
+		require.Equal(t, commit1, srcs[1].Commit)
+	})
+	t.Run("Prune unused sources", func(t *testing.T) {
+		stale := filepath.Join(dir, downloadDirPrefix+"123")
+		require.NoError(t, os.Mkdir(stale, 0700))
+		require.NoError(t, os.Chtimes(stale, today.Add(-2*staleDownloadAge), today.Add(-2*staleDownloadAge)))
+		inProgress := filepath.Join(dir, downloadDirPrefix+"456")
+		require.NoError(t, os.Mkdir(inProgress, 0700))
+
+		pruned, err := repo.cache(dir, today).Prune(today.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Len(t, pruned, 1)
+		require.Equal(t, commit1, pruned[0].Commit)
+		require.NoDirExists(t, filepath.Join(dir, commit1))
+		require.NoDirExists(t, stale)
+		require.DirExists(t, inProgress, "download of a concurrent deployment must be kept")
+
+		srcs, err := repo.cache(dir, today).List()
+		require.NoError(t, err)
+		require.Len(t, srcs, 1)
+		require.Equal(t, commit2, srcs[0].Commit)
+	})
+}
+
+func TestConcurrentCaches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-sources-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// each deployment runs in its own process with its own cache
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		revision := fmt.Sprintf("release-%d", i)
+		repo := &fakeRepo{refs: map[string]string{revision: fmt.Sprintf("%040d", i)}}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := repo.cache(dir, time.Now()).Get(revision)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	srcs, err := (&fakeRepo{}).cache(dir, time.Now()).List()
+	require.NoError(t, err)
+	require.Len(t, srcs, 10, "no deployment may lose the sources of another one")
+	require.NoFileExists(t, filepath.Join(dir, lockFile))
+}
+
+func TestUntrackedSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-sources-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// sources which were moved into the cache by an interrupted process, and may be in use, are reused instead of deleted
+	untracked := filepath.Join(dir, commit1)
+	require.NoError(t, os.MkdirAll(untracked, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(untracked, "HEAD"), []byte(commit1), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(untracked, "in-use"), nil, 0600))
+	// a lock which a crashed process left behind
+	lock := filepath.Join(dir, lockFile)
+	require.NoError(t, ioutil.WriteFile(lock, nil, 0600))
+	require.NoError(t, os.Chtimes(lock, time.Now().Add(-2*staleLockAge), time.Now().Add(-2*staleLockAge)))
+
+	repo := &fakeRepo{refs: map[string]string{"main": commit1}}
+	src, _, err := repo.cache(dir, time.Now()).Get("main")
+	require.NoError(t, err)
+	require.Equal(t, untracked, src.Path)
+	require.FileExists(t, filepath.Join(untracked, "in-use"))
+}
@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+const (
+	commitLength = 40
+	prPrefix     = "PR-"
+	peeledSuffix = "^{}"
+)
+
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// isCommit returns true if the revision looks like a (possibly abbreviated) commit SHA.
+// Branches and tags can look like that as well (e.g. "2021"), so they take precedence.
+func isCommit(revision string) bool {
+	return commitPattern.MatchString(revision)
+}
+
+// resolveRemoteRef looks up the commit SHA of a revision in the references of the remote repository.
+// An empty SHA is returned if no reference matches the revision.
+func resolveRemoteRef(repoURL, revision string) (string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoURL},
+	})
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not list references of repository '%s'", repoURL)
+	}
+	return matchRef(refs, revision), nil
+}
+
+// matchRef returns the commit SHA of the reference matching the revision.
+// Pull requests (PR-<number>), tags and branches are supported.
+func matchRef(refs []*plumbing.Reference, revision string) string {
+	var candidates []string
+	if strings.HasPrefix(revision, prPrefix) {
+		candidates = []string{fmt.Sprintf("refs/pull/%s/head", strings.TrimPrefix(revision, prPrefix))}
+	} else {
+		// annotated tags are peeled to the commit they point to
+		candidates = []string{
+			fmt.Sprintf("refs/tags/%s%s", revision, peeledSuffix),
+			fmt.Sprintf("refs/tags/%s", revision),
+			fmt.Sprintf("refs/heads/%s", revision),
+		}
+	}
+
+	hashes := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if ref.Type() == plumbing.HashReference {
+			hashes[ref.Name().String()] = ref.Hash().String()
+		}
+	}
+	for _, candidate := range candidates {
+		if hash, ok := hashes[candidate]; ok {
+			return hash
+		}
+	}
+	return ""
+}
+
+// HeadCommit returns the commit SHA the sources in the folder are checked out at.
+func HeadCommit(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not open Kyma repository in '%s'", dir)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not get head of Kyma repository in '%s'", dir)
+	}
+	return head.Hash().String(), nil
+}
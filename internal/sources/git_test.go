@@ -0,0 +1,33 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+func TestMatchRef(t *testing.T) {
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference("refs/heads/main", plumbing.NewHash(commit1)),
+		plumbing.NewHashReference("refs/tags/1.24.0", plumbing.NewHash("3333333333333333333333333333333333333333")),
+		plumbing.NewHashReference("refs/tags/1.24.0^{}", plumbing.NewHash(commit2)),
+		plumbing.NewHashReference("refs/tags/1.23.0", plumbing.NewHash(commit1)),
+		plumbing.NewHashReference("refs/pull/123/head", plumbing.NewHash(commit2)),
+		plumbing.NewSymbolicReference("HEAD", "refs/heads/main"),
+	}
+
+	require.Equal(t, commit1, matchRef(refs, "main"))
+	require.Equal(t, commit2, matchRef(refs, "1.24.0"), "annotated tags are peeled")
+	require.Equal(t, commit1, matchRef(refs, "1.23.0"))
+	require.Equal(t, commit2, matchRef(refs, "PR-123"))
+	require.Equal(t, "", matchRef(refs, "unknown"))
+}
+
+func TestIsCommit(t *testing.T) {
+	require.True(t, isCommit("34edf09a"))
+	require.True(t, isCommit(commit1))
+	require.False(t, isCommit("main"))
+	require.False(t, isCommit("1.24.0"))
+	require.False(t, isCommit("abc"))
+}
@@ -0,0 +1,113 @@
+// Package summary collects the information about a Kyma deployment which is shown to the user after the deployment.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/pkg/errors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	kymaNamespace     = "kyma-system"
+	consoleService    = "console-web"
+	adminSecret       = "admin-user"
+	kymaGateway       = "kyma-gateway"
+	consoleNotPresent = "not installed"
+)
+
+// Collect reads the installed Kyma versions, the console URL, and the admin credentials from the cluster.
+// If the domain is empty, it is read from the Kyma gateway.
+func Collect(k8s kube.KymaKube, kubeconfigPath, domain string) (*nice.Summary, error) {
+	versions, err := installedVersions(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return collect(k8s, versions, domain)
+}
+
+func collect(k8s kube.KymaKube, versions []string, domain string) (*nice.Summary, error) {
+	var err error
+	if domain == "" {
+		if domain, err = gatewayDomain(k8s); err != nil {
+			return nil, err
+		}
+	}
+
+	var consoleURL string
+	vs, err := k8s.Istio().NetworkingV1alpha3().VirtualServices(kymaNamespace).Get(context.Background(), consoleService, metav1.GetOptions{})
+	switch {
+	case k8sErrors.IsNotFound(err):
+		consoleURL = consoleNotPresent
+	case err != nil:
+		return nil, err
+	case vs != nil && len(vs.Spec.Hosts) > 0:
+		consoleURL = fmt.Sprintf("https://%s", vs.Spec.Hosts[0])
+	default:
+		return nil, errors.New("console host could not be obtained")
+	}
+
+	var email, pass string
+	adm, err := k8s.Static().CoreV1().Secrets(kymaNamespace).Get(context.Background(), adminSecret, metav1.GetOptions{})
+	switch {
+	case k8sErrors.IsNotFound(err):
+		break
+	case err != nil:
+		return nil, err
+	case adm != nil:
+		email = string(adm.Data["email"])
+		pass = string(adm.Data["password"])
+	default:
+		return nil, errors.New("admin credentials could not be obtained")
+	}
+
+	return &nice.Summary{
+		Version:  strings.Join(versions, ", "),
+		URL:      domain,
+		Console:  consoleURL,
+		Email:    email,
+		Password: pass,
+	}, nil
+}
+
+// installedVersions returns the names of the Kyma versions installed on the cluster
+func installedVersions(kubeconfigPath string) ([]string, error) {
+	provider, err := helm.NewKymaMetadataProvider(installConfig.KubeconfigSource{
+		Path: kube.KubeconfigPath(kubeconfigPath),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kymaVersionSet, err := provider.Versions()
+	if err != nil {
+		return nil, err
+	}
+	if kymaVersionSet.Count() == 0 {
+		return nil, errors.New("Kyma is not installed on the cluster")
+	}
+	return kymaVersionSet.Names(), nil
+}
+
+// gatewayDomain returns the domain of the Kyma gateway (e.g. "local.kyma.dev" for host "*.local.kyma.dev")
+func gatewayDomain(k8s kube.KymaKube) (string, error) {
+	gw, err := k8s.Istio().NetworkingV1alpha3().Gateways(kymaNamespace).Get(context.Background(), kymaGateway, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "Could not read the Kyma gateway to determine the Kyma domain")
+	}
+	for _, server := range gw.Spec.Servers {
+		for _, host := range server.Hosts {
+			if strings.HasPrefix(host, "*.") {
+				return strings.TrimPrefix(host, "*."), nil
+			}
+		}
+	}
+	return "", errors.New("Kyma domain could not be obtained from the Kyma gateway")
+}
@@ -0,0 +1,57 @@
+package summary
+
+import (
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	"istio.io/client-go/pkg/apis/networking/v1alpha3"
+	fakeIstio "istio.io/client-go/pkg/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollect(t *testing.T) {
+	k8sMock := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+			Data: map[string][]byte{
+				"email":    []byte("admin@kyma.cx"),
+				"password": []byte("1234-super-secure"),
+			},
+		},
+	)
+	istioMock := fakeIstio.NewSimpleClientset(
+		&v1alpha3.VirtualService{
+			ObjectMeta: metaV1.ObjectMeta{Name: "console-web", Namespace: "kyma-system"},
+			Spec:       networkingv1alpha3.VirtualService{Hosts: []string{"console.local.kyma.dev"}},
+		},
+		&v1alpha3.Gateway{
+			ObjectMeta: metaV1.ObjectMeta{Name: "kyma-gateway", Namespace: "kyma-system"},
+			Spec: networkingv1alpha3.Gateway{
+				Servers: []*networkingv1alpha3.Server{{Hosts: []string{"*.local.kyma.dev"}}},
+			},
+		},
+	)
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(k8sMock)
+	kymaMock.On("Istio").Return(istioMock)
+
+	t.Run("Domain from the Kyma gateway", func(t *testing.T) {
+		sum, err := collect(kymaMock, []string{"2.0.0"}, "")
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0", sum.Version)
+		require.Equal(t, "local.kyma.dev", sum.URL)
+		require.Equal(t, "https://console.local.kyma.dev", sum.Console)
+		require.Equal(t, "admin@kyma.cx", sum.Email)
+		require.Equal(t, "1234-super-secure", sum.Password)
+	})
+	t.Run("Given domain", func(t *testing.T) {
+		sum, err := collect(kymaMock, []string{"2.0.0", "2.0.1"}, "example.com")
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0, 2.0.1", sum.Version)
+		require.Equal(t, "example.com", sum.URL)
+	})
+}
@@ -0,0 +1,160 @@
+package trust
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minFingerprintPrefix is the minimum length of an abbreviated fingerprint.
+const minFingerprintPrefix = 8
+
+// TrustedCertificate is a Kyma certificate in the trusted root certificates manager of the OS.
+type TrustedCertificate struct {
+	// Fingerprint is the hex encoded SHA-256 hash of the certificate and identifies it in the trust store.
+	Fingerprint string
+	Subject     string
+	DNSNames    []string
+	NotAfter    time.Time
+	// Location is where the OS stores the certificate (e.g. the file or the keychain)
+	Location string
+
+	raw []byte
+	// nickname identifies the certificate in an NSS database
+	nickname string
+}
+
+// Fingerprint returns the hex encoded SHA-256 hash of the DER encoded certificate.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// sha1Hash returns the hex encoded SHA-1 hash of the DER encoded certificate, which is how some trust stores identify certificates.
+func sha1Hash(der []byte) string {
+	sum := sha1.Sum(der)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// Expired returns true if the certificate is no longer valid.
+func (c TrustedCertificate) Expired() bool {
+	return time.Now().After(c.NotAfter)
+}
+
+// MatchesDomain returns true if the certificate was issued for the domain or a wildcard of the domain.
+func (c TrustedCertificate) MatchesDomain(domain string) bool {
+	for _, name := range c.DNSNames {
+		if name == domain || name == "*."+domain {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the certificate whose fingerprint starts with the given (possibly abbreviated) fingerprint.
+// If the certificate is stored in several locations, the first location is returned.
+func Find(certs []TrustedCertificate, fingerprint string) (TrustedCertificate, error) {
+	prefix := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	if len(prefix) < minFingerprintPrefix {
+		return TrustedCertificate{}, fmt.Errorf("The fingerprint '%s' is too short, provide at least %d characters", fingerprint, minFingerprintPrefix)
+	}
+	var found []TrustedCertificate
+	for _, c := range certs {
+		if strings.HasPrefix(c.Fingerprint, prefix) && (len(found) == 0 || found[0].Fingerprint != c.Fingerprint) {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return TrustedCertificate{}, fmt.Errorf("No trusted Kyma certificate with fingerprint '%s' found", fingerprint)
+	case 1:
+		return found[0], nil
+	default:
+		return TrustedCertificate{}, fmt.Errorf("The fingerprint '%s' is ambiguous, it matches several certificates", fingerprint)
+	}
+}
+
+// parseCertificates returns the Kyma certificates of the PEM encoded data, which can contain many certificates.
+// Blocks which are no certificates and certificates which were not issued by Kyma are skipped.
+func parseCertificates(data []byte, location string) []TrustedCertificate {
+	var certs []TrustedCertificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if c, ok := newTrustedCertificate(block.Bytes, location); ok {
+			certs = append(certs, c)
+		}
+	}
+	return certs
+}
+
+// newTrustedCertificate parses the DER encoded certificate and returns false if it is not a Kyma certificate.
+func newTrustedCertificate(der []byte, location string) (TrustedCertificate, bool) {
+	crt, err := x509.ParseCertificate(der)
+	if err != nil || !isKymaCertificate(crt) {
+		return TrustedCertificate{}, false
+	}
+	return TrustedCertificate{
+		Fingerprint: Fingerprint(der),
+		Subject:     crt.Subject.CommonName,
+		DNSNames:    crt.DNSNames,
+		NotAfter:    crt.NotAfter,
+		Location:    location,
+		raw:         der,
+	}, true
+}
+
+// isKymaCertificate returns true for the certificates created for Kyma:
+// the certificates created by the CLI belong to the "Kyma" organization and the default certificates are issued for Kyma domains.
+func isKymaCertificate(crt *x509.Certificate) bool {
+	orgs := append([]string{}, crt.Subject.Organization...)
+	for _, org := range append(orgs, crt.Issuer.Organization...) {
+		if strings.EqualFold(org, "kyma") {
+			return true
+		}
+	}
+	names := []string{crt.Subject.CommonName, crt.Issuer.CommonName}
+	for _, name := range append(names, crt.DNSNames...) {
+		if strings.Contains(strings.ToLower(name), "kyma") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortCertificates orders the certificates by location and expiry date.
+func sortCertificates(certs []TrustedCertificate) {
+	sort.SliceStable(certs, func(i, j int) bool {
+		if certs[i].Location != certs[j].Location {
+			return certs[i].Location < certs[j].Location
+		}
+		return certs[i].NotAfter.Before(certs[j].NotAfter)
+	})
+}
+
+// dedupCertificates removes the duplicates of certificates which are stored many times in the same location.
+func dedupCertificates(certs []TrustedCertificate) []TrustedCertificate {
+	seen := make(map[string]bool)
+	var unique []TrustedCertificate
+	for _, c := range certs {
+		key := c.Location + "/" + c.Fingerprint
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, c)
+	}
+	return unique
+}
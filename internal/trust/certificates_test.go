@@ -0,0 +1,77 @@
+package trust
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCertificates(t *testing.T) {
+	kymaCA := createCertificate(t, pkix.Name{CommonName: "Kyma CA local.kyma.dev", Organization: []string{"Kyma"}}, "local.kyma.dev", true)
+	defaultCrt := createCertificate(t, pkix.Name{CommonName: "*.local.kyma.dev"}, "*.local.kyma.dev", false)
+	otherCA := createCertificate(t, pkix.Name{CommonName: "Example CA", Organization: []string{"Example"}}, "example.com", true)
+
+	var data []byte
+	for _, der := range [][]byte{kymaCA, otherCA, defaultCrt} {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("key")})...)
+
+	certs := parseCertificates(data, "kyma.crt")
+	require.Len(t, certs, 2)
+	require.Equal(t, Fingerprint(kymaCA), certs[0].Fingerprint)
+	require.Equal(t, "Kyma CA local.kyma.dev", certs[0].Subject)
+	require.Equal(t, "kyma.crt", certs[0].Location)
+	require.Equal(t, Fingerprint(defaultCrt), certs[1].Fingerprint)
+	require.Len(t, certs[0].Fingerprint, 64)
+
+	require.True(t, certs[0].MatchesDomain("local.kyma.dev"))
+	require.True(t, certs[1].MatchesDomain("local.kyma.dev"))
+	require.False(t, certs[1].MatchesDomain("kyma.dev"))
+
+	require.Len(t, dedupCertificates(append(certs, certs...)), 2)
+}
+
+func TestFind(t *testing.T) {
+	certs := []TrustedCertificate{
+		{Fingerprint: "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"},
+		{Fingerprint: "a1b2c3d4ffff0718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"},
+		{Fingerprint: "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90", Location: "copy.crt"},
+	}
+
+	cert, err := Find(certs, "A1:B2:C3:D4:E5")
+	require.NoError(t, err)
+	require.Equal(t, certs[0], cert)
+
+	_, err = Find(certs, "a1b2c3d4")
+	require.Error(t, err, "ambiguous fingerprint")
+	_, err = Find(certs, "a1b2")
+	require.Error(t, err, "fingerprint too short")
+	_, err = Find(certs, "0000000000")
+	require.Error(t, err, "unknown fingerprint")
+}
+
+func createCertificate(t *testing.T, subject pkix.Name, dnsName string, isCA bool) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+
+		BasicConstraintsValid: isCA,
+		IsCA:                  isCA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
@@ -0,0 +1,242 @@
+// +build linux
+
+package trust
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+// systemStore is the layout of the system-wide trusted CA certificates of a family of Linux distributions.
+type systemStore struct {
+	name string
+	// dir is the folder of the locally added CA certificates
+	dir string
+	// update is the command which updates the trusted CA certificates after the folder changed
+	update []string
+}
+
+var systemStores = []systemStore{
+	{name: "Debian", dir: "/usr/local/share/ca-certificates", update: []string{"update-ca-certificates"}},
+	{name: "Fedora/RHEL", dir: "/etc/pki/ca-trust/source/anchors", update: []string{"update-ca-trust", "extract"}},
+}
+
+// adminDirs are the folders of the system administration tools, which are usually not in the PATH of a normal user.
+var adminDirs = []string{"/usr/local/sbin", "/usr/sbin", "/sbin"}
+
+// detectSystemStores returns the system stores of the running distribution.
+func detectSystemStores() []systemStore {
+	return detectStores(systemStores, adminDirs)
+}
+
+// detectStores returns the stores whose folder exists and whose update command is found in the PATH or in one of the tool folders.
+func detectStores(stores []systemStore, toolDirs []string) []systemStore {
+	var found []systemStore
+	for _, s := range stores {
+		if info, err := os.Stat(s.dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if _, ok := lookupTool(s.update[0], toolDirs); !ok {
+			continue
+		}
+		found = append(found, s)
+	}
+	return found
+}
+
+// lookupTool returns the path of the tool in the PATH or in the first of the folders which contains it.
+func lookupTool(name string, dirs []string) (string, bool) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, true
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// systemStoreOf returns the system store which contains the certificate file.
+func systemStoreOf(file string) (systemStore, bool) {
+	for _, s := range systemStores {
+		if filepath.Dir(file) == s.dir {
+			return s, true
+		}
+	}
+	return systemStore{}, false
+}
+
+func (s systemStore) add(file, name string) error {
+	if _, err := cli.RunCmd("sudo", "cp", file, filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	return s.refresh()
+}
+
+// refresh runs the update command with its full path, because the PATH of sudo might not contain the system administration tools either.
+func (s systemStore) refresh() error {
+	update := s.update
+	if tool, ok := lookupTool(update[0], adminDirs); ok {
+		update = append([]string{tool}, update[1:]...)
+	}
+	_, err := cli.RunCmd("sudo", update...)
+	return err
+}
+
+// nssDatabases returns the NSS databases of the user in the home directory: the shared database which Chrome and Chromium use,
+// and the databases of the Firefox profiles. The databases are prefixed with their format, as expected by certutil.
+func nssDatabases(home string) []string {
+	dirs := []string{filepath.Join(home, ".pki", "nssdb")}
+	for _, profiles := range []string{
+		filepath.Join(home, ".mozilla", "firefox"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox"),
+	} {
+		matches, _ := filepath.Glob(filepath.Join(profiles, "*"))
+		dirs = append(dirs, matches...)
+	}
+
+	var dbs []string
+	for _, dir := range dirs {
+		if fileExists(filepath.Join(dir, "cert9.db")) {
+			dbs = append(dbs, "sql:"+dir)
+		} else if fileExists(filepath.Join(dir, "cert8.db")) {
+			dbs = append(dbs, "dbm:"+dir)
+		}
+	}
+	return dbs
+}
+
+// userNSSDatabases returns the NSS databases of the current user, or nothing if certutil is not installed.
+func userNSSDatabases() (dbs []string, certutilMissing bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, false
+	}
+	dbs = nssDatabases(home)
+	if len(dbs) == 0 {
+		return nil, false
+	}
+	if _, err := exec.LookPath("certutil"); err != nil {
+		return nil, true
+	}
+	return dbs, false
+}
+
+// storeInNSS imports the root of the certificate chain into the NSS databases, which browsers use instead of the system store.
+// It returns the databases which were updated.
+func storeInNSS(dbs []string, file, nickname string, i Informer) []string {
+	rootFile, trustArgs, err := rootCertificate(file)
+	if err != nil {
+		i.LogInfof("Could not import the Kyma certificate into the browser certificate databases: %s", err)
+		return nil
+	}
+	defer os.Remove(rootFile)
+
+	var updated []string
+	for _, db := range dbs {
+		// replace the certificate of a former cluster with the same domain
+		_, _ = cli.RunCmd("certutil", "-D", "-d", db, "-n", nickname)
+		if _, err := cli.RunCmd("certutil", "-A", "-d", db, "-n", nickname, "-t", trustArgs, "-i", rootFile); err != nil {
+			i.LogInfof("Could not import the Kyma certificate into '%s': %s", db, err)
+			continue
+		}
+		updated = append(updated, db)
+	}
+	return updated
+}
+
+// listNSS returns the Kyma certificates of the NSS database.
+func listNSS(db string) ([]TrustedCertificate, error) {
+	out, err := cli.RunCmd("certutil", "-L", "-d", db)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read the certificates of '%s'", db)
+	}
+	var certs []TrustedCertificate
+	for _, nickname := range nssNicknames(out) {
+		if !strings.HasPrefix(nickname, "kyma-") {
+			continue
+		}
+		pemOut, err := cli.RunCmd("certutil", "-L", "-d", db, "-n", nickname, "-a")
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read the certificate '%s' of '%s'", nickname, db)
+		}
+		for _, c := range parseCertificates([]byte(pemOut), db) {
+			c.nickname = nickname
+			certs = append(certs, c)
+		}
+	}
+	return certs, nil
+}
+
+// nssTrustArgs matches the trust attributes which certutil lists after the nickname (e.g. "C,," or "CT,C,C")
+var nssTrustArgs = regexp.MustCompile(`^(.*\S)\s+([a-zA-Z]*,[a-zA-Z]*,[a-zA-Z]*)\s*$`)
+
+// nssNicknames returns the nicknames of the certificates listed by "certutil -L".
+func nssNicknames(out string) []string {
+	var nicknames []string
+	for _, line := range strings.Split(out, "\n") {
+		if m := nssTrustArgs.FindStringSubmatch(line); m != nil {
+			nicknames = append(nicknames, m[1])
+		}
+	}
+	return nicknames
+}
+
+// rootCertificate writes the last certificate of the chain in the file to a temporary file and returns its NSS trust attributes:
+// a CA is trusted to issue server certificates, and a self-signed server certificate is trusted as a peer.
+func rootCertificate(file string) (string, string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", "", err
+	}
+	var root *pem.Block
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			root = block
+		}
+	}
+	if root == nil {
+		return "", "", fmt.Errorf("The file '%s' does not contain a PEM encoded certificate", file)
+	}
+	crt, err := x509.ParseCertificate(root.Bytes)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "Could not parse the certificate '%s'", file)
+	}
+	trustArgs := "P,,"
+	if crt.IsCA {
+		trustArgs = "C,,"
+	}
+
+	tmpFile, err := ioutil.TempFile("", "kyma-*.crt")
+	if err != nil {
+		return "", "", err
+	}
+	defer tmpFile.Close()
+	if err := pem.Encode(tmpFile, root); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", "", err
+	}
+	return tmpFile.Name(), trustArgs, nil
+}
+
+func fileExists(file string) bool {
+	info, err := os.Stat(file)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,115 @@
+// +build linux
+
+package trust
+
+import (
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sbin := filepath.Join(dir, "sbin")
+	debian := filepath.Join(dir, "ca-certificates")
+	fedora := filepath.Join(dir, "anchors")
+	for _, folder := range []string{sbin, debian, fedora} {
+		require.NoError(t, os.MkdirAll(folder, 0700))
+	}
+	// the tools of the system administrator are not in the PATH of the user
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sbin, "kyma-test-update-ca-certificates"), nil, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sbin, "kyma-test-update-ca-trust"), nil, 0600))
+
+	stores := []systemStore{
+		{name: "Debian", dir: debian, update: []string{"kyma-test-update-ca-certificates"}},
+		{name: "Fedora/RHEL", dir: fedora, update: []string{"kyma-test-update-ca-trust", "extract"}},
+		{name: "Missing", dir: filepath.Join(dir, "missing"), update: []string{"kyma-test-update-ca-certificates"}},
+	}
+	require.Equal(t, stores[:1], detectStores(stores, []string{filepath.Join(dir, "usr", "sbin"), sbin}))
+
+	tool, found := lookupTool("kyma-test-update-ca-certificates", []string{sbin})
+	require.True(t, found)
+	require.Equal(t, filepath.Join(sbin, "kyma-test-update-ca-certificates"), tool)
+	_, found = lookupTool("kyma-test-update-ca-trust", []string{sbin})
+	require.False(t, found, "files which are not executable are no tools")
+}
+
+func TestNSSDatabases(t *testing.T) {
+	home, err := ioutil.TempDir("", "trust-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	require.Empty(t, nssDatabases(home))
+
+	chrome := filepath.Join(home, ".pki", "nssdb")
+	firefox := filepath.Join(home, ".mozilla", "firefox", "abc123.default-release")
+	legacy := filepath.Join(home, ".mozilla", "firefox", "xyz789.legacy")
+	for _, file := range []string{
+		filepath.Join(chrome, "cert9.db"),
+		filepath.Join(firefox, "cert9.db"),
+		filepath.Join(legacy, "cert8.db"),
+		filepath.Join(home, ".mozilla", "firefox", "profiles.ini"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+		require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+	}
+
+	require.Equal(t, []string{"sql:" + chrome, "sql:" + firefox, "dbm:" + legacy}, nssDatabases(home))
+}
+
+func TestNSSNicknames(t *testing.T) {
+	out := `
+Certificate Nickname                                         Trust Attributes
+                                                             SSL,S/MIME,JAR/XPI
+
+kyma-local.kyma.dev                                          C,,
+Example Root CA                                              CT,C,C
+kyma-*.kyma.example.com, DNS:kyma.example.com                P,,
+`
+	require.Equal(t, []string{"kyma-local.kyma.dev", "Example Root CA", "kyma-*.kyma.example.com, DNS:kyma.example.com"}, nssNicknames(out))
+}
+
+func TestRootCertificate(t *testing.T) {
+	crt := createCertificate(t, pkix.Name{CommonName: "*.local.kyma.dev"}, "*.local.kyma.dev", false)
+	ca := createCertificate(t, pkix.Name{CommonName: "Kyma CA local.kyma.dev", Organization: []string{"Kyma"}}, "local.kyma.dev", true)
+
+	file, err := ioutil.TempFile("", "trust-test")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	require.NoError(t, pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: crt}))
+	require.NoError(t, pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: ca}))
+	require.NoError(t, file.Close())
+
+	t.Run("Certificate chain", func(t *testing.T) {
+		rootFile, trustArgs, err := rootCertificate(file.Name())
+		require.NoError(t, err)
+		defer os.Remove(rootFile)
+		require.Equal(t, "C,,", trustArgs)
+
+		data, err := ioutil.ReadFile(rootFile)
+		require.NoError(t, err)
+		block, _ := pem.Decode(data)
+		require.Equal(t, ca, block.Bytes)
+	})
+
+	t.Run("Self-signed certificate", func(t *testing.T) {
+		selfSigned, err := ioutil.TempFile("", "trust-test")
+		require.NoError(t, err)
+		defer os.Remove(selfSigned.Name())
+		require.NoError(t, pem.Encode(selfSigned, &pem.Block{Type: "CERTIFICATE", Bytes: crt}))
+		require.NoError(t, selfSigned.Close())
+
+		rootFile, trustArgs, err := rootCertificate(selfSigned.Name())
+		require.NoError(t, err)
+		defer os.Remove(rootFile)
+		require.Equal(t, "P,,", trustArgs)
+	})
+}
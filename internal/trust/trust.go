@@ -13,6 +13,12 @@ type Certifier interface {
 	// StoreCertificate imports the given certificate file into the trusted root certificates manager of the OS.
 	StoreCertificate(file string, info Informer) error
 
+	// ListCertificates returns the Kyma certificates in the trusted root certificates manager of the OS.
+	ListCertificates() ([]TrustedCertificate, error)
+
+	// RemoveCertificate removes the Kyma certificate with the given SHA-256 fingerprint from the trusted root certificates manager of the OS.
+	RemoveCertificate(fingerprint string, info Informer) error
+
 	// Instructions provides instructions on how to manually store a certificate.
 	// Use in case it can not be stored by calling StoreCertificate.
 	Instructions() string
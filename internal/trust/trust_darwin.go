@@ -5,7 +5,10 @@ package trust
 import (
 	"context"
 	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"os"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -16,6 +19,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+const systemKeychain = "/Library/Keychains/System.keychain"
+
 type keychain struct {
 	k8s kube.KymaKube
 }
@@ -59,7 +64,7 @@ func (k keychain) StoreCertificate(file string, i Informer) error {
 		}
 	}
 
-	_, err := cli.RunCmd("sudo", "security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", "/Library/Keychains/System.keychain", file)
+	_, err := cli.RunCmd("sudo", "security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", systemKeychain, file)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("\nCould not import the Kyma root certificate. Follow the instructions below to import it manually:\n-----\n%s-----\n", k.Instructions()))
 	}
@@ -67,6 +72,56 @@ func (k keychain) StoreCertificate(file string, i Informer) error {
 	return nil
 }
 
+func (k keychain) ListCertificates() ([]TrustedCertificate, error) {
+	out, err := cli.RunCmd("security", "find-certificate", "-a", "-p", systemKeychain)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read the certificates of the keychain")
+	}
+	certs := dedupCertificates(parseCertificates([]byte(out), systemKeychain))
+	sortCertificates(certs)
+	return certs, nil
+}
+
+func (k keychain) RemoveCertificate(fingerprint string, i Informer) error {
+	certs, err := k.ListCertificates()
+	if err != nil {
+		return err
+	}
+	cert, err := Find(certs, fingerprint)
+	if err != nil {
+		return err
+	}
+	if root.IsWithSudo() {
+		i.LogInfo("You're running CLI with sudo. CLI has to remove the Kyma certificate from the keychain. Type 'y' to allow this action.")
+		if !root.PromptUser() {
+			return fmt.Errorf("Could not remove the Kyma certificate. Remove it manually: sudo security delete-certificate -Z %s %s", sha1Hash(cert.raw), systemKeychain)
+		}
+	}
+
+	// the trust settings refer to the certificate file, so the certificate is written to a temporary file
+	tmpFile, err := ioutil.TempFile("", "kyma-*.crt")
+	if err != nil {
+		return errors.Wrap(err, "Could not create the temporary certificate file")
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := pem.Encode(tmpFile, &pem.Block{Type: "CERTIFICATE", Bytes: cert.raw}); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "Could not write the temporary certificate file")
+	}
+	tmpFile.Close()
+
+	// the certificate has no admin trust settings if it was not imported by the CLI
+	if _, err := cli.RunCmd("sudo", "security", "remove-trusted-cert", "-d", tmpFile.Name()); err != nil {
+		i.LogInfof("Could not remove the trust settings of the Kyma certificate: %s", err)
+	}
+	// the keychain identifies certificates by their SHA-1 hash
+	if _, err := cli.RunCmd("sudo", "security", "delete-certificate", "-Z", sha1Hash(cert.raw), systemKeychain); err != nil {
+		return errors.Wrap(err, "Could not remove the Kyma certificate from the keychain")
+	}
+	i.LogInfof("Removed the Kyma certificate '%s' from the keychain", cert.Subject)
+	return nil
+}
+
 func (keychain) Instructions() string {
 	return "1. Download the certificate: kubectl get configmap net-global-overrides -n kyma-installer -o jsonpath='{.data.global\\.ingress\\.tlsCrt}' | base64 --decode > kyma.crt\n" +
 		"2. Import the certificate: sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain kyma.crt\n"
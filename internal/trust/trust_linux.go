@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -67,30 +69,117 @@ func (c certauth) StoreCertificate(file string, i Informer) error {
 		return err
 	}
 
-	_, err = cli.RunCmd("sudo", "cp", file, fmt.Sprintf("/usr/local/share/ca-certificates/kyma-%s.crt", domain))
+	stores := detectSystemStores()
+	if len(stores) == 0 {
+		return fmt.Errorf("\nCould not find the trusted certificate store of the Linux distribution. Follow the instructions to import the Kyma certificates manually:\n-----\n%s-----\n", c.Instructions())
+	}
+	for _, store := range stores {
+		if err := store.add(file, fmt.Sprintf("kyma-%s.crt", domain)); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("\nCould not import the Kyma certificates. Follow the instructions to import them manually:\n-----\n%s-----\n", c.Instructions()))
+		}
+		i.LogInfof("Imported the Kyma certificate into the %s trusted certificate store '%s'", store.name, store.dir)
+	}
+
+	// browsers like Chrome and Firefox do not use the system store but NSS databases of the user
+	dbs, certutilMissing := userNSSDatabases()
+	if certutilMissing {
+		i.LogInfo("Could not import the Kyma certificate into the certificate databases of the browsers because 'certutil' is missing. Install the 'libnss3-tools' (Debian) or 'nss-tools' (Fedora/RHEL) package and import the certificate again.")
+	}
+	for _, db := range storeInNSS(dbs, file, fmt.Sprintf("kyma-%s", domain), i) {
+		i.LogInfof("Imported the Kyma certificate into the browser certificate database '%s'", db)
+	}
+
+	return nil
+}
+
+func (c certauth) ListCertificates() ([]TrustedCertificate, error) {
+	var certs []TrustedCertificate
+	for _, store := range systemStores {
+		files, err := filepath.Glob(filepath.Join(store.dir, "kyma-*.crt"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Could not read the certificate '%s'", file)
+			}
+			certs = append(certs, parseCertificates(data, file)...)
+		}
+	}
+
+	dbs, _ := userNSSDatabases()
+	for _, db := range dbs {
+		dbCerts, err := listNSS(db)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, dbCerts...)
+	}
+	certs = dedupCertificates(certs)
+	sortCertificates(certs)
+	return certs, nil
+}
+
+func (c certauth) RemoveCertificate(fingerprint string, i Informer) error {
+	certs, err := c.ListCertificates()
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("\nCould not import the Kyma certificates. Follow the instructions to import them manually:\n-----\n%s-----\n", c.Instructions()))
+		return err
 	}
-	_, err = cli.RunCmd("sudo", "update-ca-certificates")
+	cert, err := Find(certs, fingerprint)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("\nCould not import the Kyma certificates. Follow the instructions to import them manually:\n-----\n%s-----\n", c.Instructions()))
+		return err
+	}
+	if root.IsWithSudo() {
+		i.LogInfo("You're running CLI with sudo. CLI has to remove the Kyma certificate from the trusted certificate store. Type 'y' to allow this action.")
+		if !root.PromptUser() {
+			return fmt.Errorf("Could not remove the Kyma certificate. Remove the file '%s' and update the trusted certificate store manually", cert.Location)
+		}
 	}
 
+	changed := make(map[string]systemStore)
+	for _, c := range certs {
+		if c.Fingerprint != cert.Fingerprint {
+			continue
+		}
+		if c.nickname != "" {
+			if _, err := cli.RunCmd("certutil", "-D", "-d", c.Location, "-n", c.nickname); err != nil {
+				return errors.Wrapf(err, "Could not remove the Kyma certificate '%s' from '%s'", c.nickname, c.Location)
+			}
+			i.LogInfof("Removed the Kyma certificate from the browser certificate database '%s'", c.Location)
+			continue
+		}
+		// the certificate files can contain a certificate chain, so the whole files are removed
+		if _, err := cli.RunCmd("sudo", "rm", "-f", c.Location); err != nil {
+			return errors.Wrapf(err, "Could not remove the Kyma certificate '%s'", c.Location)
+		}
+		i.LogInfof("Removed the Kyma certificate '%s' from the trusted certificate store", c.Location)
+		if store, ok := systemStoreOf(c.Location); ok {
+			changed[store.dir] = store
+		}
+	}
+	for _, store := range changed {
+		if err := store.refresh(); err != nil {
+			return errors.Wrapf(err, "Could not update the trusted certificate store. Run 'sudo %s' manually", strings.Join(store.update, " "))
+		}
+	}
 	return nil
 }
 
 func (certauth) Instructions() string {
 	return "1. Download the certificate: kubectl get configmap net-global-overrides -n kyma-installer -o jsonpath='{.data.global\\.ingress\\.tlsCrt}' | base64 --decode > kyma.crt\n" +
 		"2. Rename the certificate file: mv kyma.crt {NEW_CERT_NAME}\n" +
-		"3. Copy the certificate to the CA folder: sudo cp {NEW_CERT_NAME} /usr/local/share/ca-certificates/\n" +
-		"4. Update the certificate registry: sudo update-ca-certificates\n"
+		"3. Copy the certificate to the CA folder: sudo cp {NEW_CERT_NAME} /usr/local/share/ca-certificates/ (Fedora/RHEL: /etc/pki/ca-trust/source/anchors/)\n" +
+		"4. Update the certificate registry: sudo update-ca-certificates (Fedora/RHEL: sudo update-ca-trust extract)\n" +
+		"5. For Chrome and Firefox, import the certificate into their certificate databases: certutil -A -d sql:$HOME/.pki/nssdb -n kyma -t C,, -i {NEW_CERT_NAME}\n"
 }
 
 func (certauth) InstructionsAlpha() string {
 	return "1. Download the certificate: kubectl get secret kyma-gateway-certs -n istio-system -o jsonpath='{.data.tls\\.crt}' > kyma.crt\n" +
 		"2. Rename the certificate file: mv kyma.crt {NEW_CERT_NAME}\n" +
-		"3. Copy the certificate to the CA folder: sudo cp {NEW_CERT_NAME} /usr/local/share/ca-certificates/\n" +
-		"4. Update the certificate registry: sudo update-ca-certificates\n"
+		"3. Copy the certificate to the CA folder: sudo cp {NEW_CERT_NAME} /usr/local/share/ca-certificates/ (Fedora/RHEL: /etc/pki/ca-trust/source/anchors/)\n" +
+		"4. Update the certificate registry: sudo update-ca-certificates (Fedora/RHEL: sudo update-ca-trust extract)\n" +
+		"5. For Chrome and Firefox, import the certificate into their certificate databases: certutil -A -d sql:$HOME/.pki/nssdb -n kyma -t C,, -i {NEW_CERT_NAME}\n"
 }
 
 // certDomain returns the DNS info of the provided root certificate.
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
@@ -14,6 +15,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const rootStore = `Cert:\LocalMachine\Root`
+
 type certutil struct {
 	k8s kube.KymaKube
 }
@@ -63,6 +66,54 @@ func (c certutil) StoreCertificate(file string, i Informer) error {
 	return errors.New(fmt.Sprintf("Could not import the Kyma root certificate. Follow the instructions to import them manually:\n-----\n%s-----\n", c.Instructions()))
 }
 
+func (c certutil) ListCertificates() ([]TrustedCertificate, error) {
+	// certutil has no machine-readable output, so PowerShell provides the raw certificates, one base64 encoded certificate per line
+	out, err := cli.RunCmd("powershell", "-NoProfile", "-Command",
+		fmt.Sprintf("Get-ChildItem %s | ForEach-Object { [Convert]::ToBase64String($_.RawData) }", rootStore))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read the trusted root certificates")
+	}
+	var certs []TrustedCertificate
+	for _, line := range strings.Split(out, "\n") {
+		der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(line))
+		if err != nil || len(der) == 0 {
+			continue
+		}
+		if cert, ok := newTrustedCertificate(der, rootStore); ok {
+			certs = append(certs, cert)
+		}
+	}
+	certs = dedupCertificates(certs)
+	sortCertificates(certs)
+	return certs, nil
+}
+
+func (c certutil) RemoveCertificate(fingerprint string, i Informer) error {
+	certs, err := c.ListCertificates()
+	if err != nil {
+		return err
+	}
+	cert, err := Find(certs, fingerprint)
+	if err != nil {
+		return err
+	}
+	// the Root store identifies certificates by their SHA-1 thumbprint
+	thumbprint := sha1Hash(cert.raw)
+	if root.IsWithSudo() {
+		i.LogInfo("You're running CLI with sudo. CLI has to remove the Kyma root certificate from the trusted certificates. Type 'y' to allow this action.")
+		if !root.PromptUser() {
+			return fmt.Errorf("Could not remove the Kyma root certificate. In a terminal window with administrator rights, run: certutil -delstore Root %s", thumbprint)
+		}
+		// Only automatically remove the cert if already on admin mode, can't ask for admin password from go
+		if _, err := cli.RunCmd("certutil", "-delstore", "Root", thumbprint); err != nil {
+			return errors.Wrap(err, "Could not remove the Kyma root certificate")
+		}
+		i.LogInfof("Removed the Kyma root certificate '%s' from the trusted certificates", cert.Subject)
+		return nil
+	}
+	return fmt.Errorf("Could not remove the Kyma root certificate. In a terminal window with administrator rights, run: certutil -delstore Root %s", thumbprint)
+}
+
 func (certutil) Instructions() string {
 	return "1. Open a terminal window with administrator rights.\n" +
 		"2. Download the certificate: kubectl get configmap net-global-overrides -n kyma-installer -o jsonpath='{.data.global\\.ingress\\.tlsCrt}' > tmp.txt\n" +
@@ -0,0 +1,91 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// addEvents adds the most recent events of the resource and of its pods to the problem.
+// The containers which are not ready are reported first, as they usually explain why a workload is not available.
+func (v *Verifier) addEvents(p *Problem) error {
+	// events of cluster-scoped resources are rarely helpful to explain the problem
+	if p.Namespace == "" {
+		return nil
+	}
+	ctx := context.Background()
+
+	involved := map[string]bool{p.Name: true}
+	if p.selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(p.selector)
+		if err != nil {
+			return errors.Wrapf(err, "Could not parse the pod selector of %s '%s/%s'", p.Kind, p.Namespace, p.Name)
+		}
+		pods, err := v.static.CoreV1().Pods(p.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return errors.Wrapf(err, "Could not list the pods of %s '%s/%s'", p.Kind, p.Namespace, p.Name)
+		}
+		for _, pod := range pods.Items {
+			involved[pod.Name] = true
+			p.Events = append(p.Events, containerStates(pod)...)
+		}
+	}
+
+	events, err := v.static.CoreV1().Events(p.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Could not list the events of namespace '%s'", p.Namespace)
+	}
+	var recent []corev1.Event
+	for _, event := range events.Items {
+		if involved[event.InvolvedObject.Name] {
+			recent = append(recent, event)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return eventTime(recent[i]).Before(eventTime(recent[j]))
+	})
+	if len(recent) > v.EventLimit {
+		recent = recent[len(recent)-v.EventLimit:]
+	}
+	for _, event := range recent {
+		p.Events = append(p.Events, fmt.Sprintf("%s %s %s/%s: %s",
+			event.Type, event.Reason, strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, strings.TrimSpace(event.Message)))
+	}
+	return nil
+}
+
+// containerStates describes the containers of the pod which are not ready
+func containerStates(pod corev1.Pod) []string {
+	var result []string
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if status.Ready {
+			continue
+		}
+		switch {
+		case status.State.Waiting != nil:
+			result = append(result, fmt.Sprintf("Container '%s' of pod '%s' is waiting: %s (%d restarts)",
+				status.Name, pod.Name, status.State.Waiting.Reason, status.RestartCount))
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			result = append(result, fmt.Sprintf("Container '%s' of pod '%s' terminated: %s (exit code %d)",
+				status.Name, pod.Name, status.State.Terminated.Reason, status.State.Terminated.ExitCode))
+		}
+	}
+	return result
+}
+
+// eventTime returns the time an event was last observed
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,197 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// checkWorkloads verifies that all Deployments, StatefulSets, and DaemonSets of the releases are rolled out and ready
+func (v *Verifier) checkWorkloads(releases map[Release]bool, problems map[Release][]Problem) error {
+	ctx := context.Background()
+
+	deployments, err := v.static.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the Deployments of the cluster")
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if release, ok := ownerOf(releases, d); ok {
+			if reason := deploymentReason(d); reason != "" {
+				problems[release] = append(problems[release], Problem{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name, Reason: reason, selector: d.Spec.Selector})
+			}
+		}
+	}
+
+	statefulSets, err := v.static.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the StatefulSets of the cluster")
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if release, ok := ownerOf(releases, s); ok {
+			if reason := statefulSetReason(s); reason != "" {
+				problems[release] = append(problems[release], Problem{Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name, Reason: reason, selector: s.Spec.Selector})
+			}
+		}
+	}
+
+	daemonSets, err := v.static.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the DaemonSets of the cluster")
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		if release, ok := ownerOf(releases, d); ok {
+			if reason := daemonSetReason(d); reason != "" {
+				problems[release] = append(problems[release], Problem{Kind: "DaemonSet", Namespace: d.Namespace, Name: d.Name, Reason: reason, selector: d.Spec.Selector})
+			}
+		}
+	}
+	return nil
+}
+
+func deploymentReason(d *appsv1.Deployment) string {
+	replicas := desiredReplicas(d.Spec.Replicas)
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		return "rollout not yet started"
+	case d.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", d.Status.UpdatedReplicas, replicas)
+	case d.Status.AvailableReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas available", d.Status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+func statefulSetReason(s *appsv1.StatefulSet) string {
+	replicas := desiredReplicas(s.Spec.Replicas)
+	switch {
+	case s.Status.ObservedGeneration < s.Generation:
+		return "rollout not yet started"
+	case s.Status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas ready", s.Status.ReadyReplicas, replicas)
+	}
+	return ""
+}
+
+func daemonSetReason(d *appsv1.DaemonSet) string {
+	desired := d.Status.DesiredNumberScheduled
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		return "rollout not yet started"
+	case d.Status.UpdatedNumberScheduled < desired:
+		return fmt.Sprintf("%d of %d pods updated", d.Status.UpdatedNumberScheduled, desired)
+	case d.Status.NumberReady < desired:
+		return fmt.Sprintf("%d of %d pods ready", d.Status.NumberReady, desired)
+	}
+	return ""
+}
+
+// desiredReplicas returns the replicas of a workload (Kubernetes defaults unset replicas to 1)
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// checkCRDs verifies that all CustomResourceDefinitions of the releases are established
+func (v *Verifier) checkCRDs(releases map[Release]bool, problems map[Release][]Problem) error {
+	crds, err := v.dynamic.Resource(crdGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the CustomResourceDefinitions of the cluster")
+	}
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if release, ok := ownerOf(releases, crd); ok && !established(crd) {
+			problems[release] = append(problems[release], Problem{Kind: "CustomResourceDefinition", Name: crd.GetName(), Reason: "not established"})
+		}
+	}
+	return nil
+}
+
+func established(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWebhooks verifies that the services of all admission webhooks of the releases have ready endpoints, so that the API server can call them
+func (v *Verifier) checkWebhooks(releases map[Release]bool, problems map[Release][]Problem) error {
+	ctx := context.Background()
+
+	mutating, err := v.static.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the MutatingWebhookConfigurations of the cluster")
+	}
+	for i := range mutating.Items {
+		config := &mutating.Items[i]
+		release, ok := ownerOf(releases, config)
+		if !ok {
+			continue
+		}
+		for _, webhook := range config.Webhooks {
+			if err := v.checkWebhook("MutatingWebhookConfiguration", config.Name, webhook.Name, webhook.ClientConfig, release, problems); err != nil {
+				return err
+			}
+		}
+	}
+
+	validating, err := v.static.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list the ValidatingWebhookConfigurations of the cluster")
+	}
+	for i := range validating.Items {
+		config := &validating.Items[i]
+		release, ok := ownerOf(releases, config)
+		if !ok {
+			continue
+		}
+		for _, webhook := range config.Webhooks {
+			if err := v.checkWebhook("ValidatingWebhookConfiguration", config.Name, webhook.Name, webhook.ClientConfig, release, problems); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkWebhook verifies that the service of a webhook has at least one ready endpoint (webhooks called by URL are not verified)
+func (v *Verifier) checkWebhook(kind, config, webhook string, clientConfig admissionv1.WebhookClientConfig, release Release, problems map[Release][]Problem) error {
+	service := clientConfig.Service
+	if service == nil {
+		return nil
+	}
+	endpoints, err := v.static.CoreV1().Endpoints(service.Namespace).Get(context.Background(), service.Name, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.Wrapf(err, "Could not read the endpoints of service '%s/%s'", service.Namespace, service.Name)
+	}
+	if err == nil {
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				return nil
+			}
+		}
+	}
+	problems[release] = append(problems[release], Problem{
+		Kind:   kind,
+		Name:   config,
+		Reason: fmt.Sprintf("webhook '%s' does not respond: service '%s/%s' has no ready endpoints", webhook, service.Namespace, service.Name),
+	})
+	return nil
+}
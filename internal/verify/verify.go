@@ -0,0 +1,155 @@
+// Package verify waits until the workloads, CRDs, and webhooks of deployed Helm releases are healthy.
+package verify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	releaseNameAnnotation      = "meta.helm.sh/release-name"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+
+	defaultInterval   = 5 * time.Second
+	defaultEventLimit = 5
+)
+
+// Release identifies a deployed Helm release.
+type Release struct {
+	Name      string
+	Namespace string
+}
+
+// Problem describes a resource of a release which is not healthy.
+type Problem struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Reason    string
+	// Events are the most recent events of the resource and its pods, the oldest first
+	Events []string
+
+	selector *metav1.LabelSelector
+}
+
+// String returns a human-readable description of the problem, including its recent events.
+func (p Problem) String() string {
+	var sb strings.Builder
+	if p.Namespace == "" {
+		fmt.Fprintf(&sb, "%s '%s': %s", p.Kind, p.Name, p.Reason)
+	} else {
+		fmt.Fprintf(&sb, "%s '%s/%s': %s", p.Kind, p.Namespace, p.Name, p.Reason)
+	}
+	for _, event := range p.Events {
+		fmt.Fprintf(&sb, "\n  %s", event)
+	}
+	return sb.String()
+}
+
+// Result contains the problems of a release which did not become healthy.
+type Result struct {
+	Release  Release
+	Problems []Problem
+}
+
+// Verifier checks the health of the workloads, CRDs, and webhooks of Helm releases.
+// The resources of a release are found by the annotations Helm adds to all resources it manages.
+type Verifier struct {
+	static  kubernetes.Interface
+	dynamic dynamic.Interface
+
+	// Interval is the time between two health checks
+	Interval time.Duration
+	// EventLimit is the maximum number of events reported for each unhealthy resource
+	EventLimit int
+}
+
+// New creates a verifier which uses the given clients.
+func New(static kubernetes.Interface, dynamic dynamic.Interface) *Verifier {
+	return &Verifier{
+		static:     static,
+		dynamic:    dynamic,
+		Interval:   defaultInterval,
+		EventLimit: defaultEventLimit,
+	}
+}
+
+// Wait checks the releases until all of them are healthy or the timeout is reached.
+// The healthy function is called once for each release as soon as it is healthy.
+// The returned results contain the problems of all releases which are not healthy when the timeout is reached.
+func (v *Verifier) Wait(releases []Release, timeout time.Duration, healthy func(Release)) ([]Result, error) {
+	pending := make(map[Release]bool)
+	for _, release := range releases {
+		pending[release] = true
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		problems, err := v.Check(pending)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range releases {
+			if pending[release] && len(problems[release]) == 0 {
+				delete(pending, release)
+				if healthy != nil {
+					healthy(release)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			return nil, nil
+		}
+		if time.Now().Add(v.Interval).After(deadline) {
+			return v.results(releases, problems)
+		}
+		time.Sleep(v.Interval)
+	}
+}
+
+// Check returns the problems of all unhealthy releases. Healthy releases have no entry in the result.
+func (v *Verifier) Check(releases map[Release]bool) (map[Release][]Problem, error) {
+	problems := make(map[Release][]Problem)
+	checks := []func(map[Release]bool, map[Release][]Problem) error{
+		v.checkWorkloads,
+		v.checkCRDs,
+		v.checkWebhooks,
+	}
+	for _, check := range checks {
+		if err := check(releases, problems); err != nil {
+			return nil, err
+		}
+	}
+	return problems, nil
+}
+
+// results collects the problems of the unhealthy releases in the order of the releases and adds the recent events
+func (v *Verifier) results(releases []Release, problems map[Release][]Problem) ([]Result, error) {
+	var results []Result
+	for _, release := range releases {
+		if len(problems[release]) == 0 {
+			continue
+		}
+		for i := range problems[release] {
+			if err := v.addEvents(&problems[release][i]); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, Result{Release: release, Problems: problems[release]})
+	}
+	return results, nil
+}
+
+// ownerOf returns the release which manages the resource and whether the release is verified
+func ownerOf(releases map[Release]bool, obj metav1.Object) (Release, bool) {
+	release := Release{
+		Name:      obj.GetAnnotations()[releaseNameAnnotation],
+		Namespace: obj.GetAnnotations()[releaseNamespaceAnnotation],
+	}
+	return release, releases[release]
+}
@@ -0,0 +1,169 @@
+package verify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	healthyRelease   = Release{Name: "healthy", Namespace: "kyma-system"}
+	unhealthyRelease = Release{Name: "unhealthy", Namespace: "kyma-system"}
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("Healthy release", func(t *testing.T) {
+		v := newVerifier(
+			[]runtime.Object{
+				deployment(healthyRelease, "app", 2, 2),
+				webhook(healthyRelease, "webhook-svc"),
+				endpoints("webhook-svc", true),
+			},
+			crd(healthyRelease, "apps.kyma-project.io", true),
+		)
+		problems, err := v.Check(map[Release]bool{healthyRelease: true})
+		require.NoError(t, err)
+		require.Empty(t, problems)
+	})
+
+	t.Run("Unhealthy release", func(t *testing.T) {
+		v := newVerifier(
+			[]runtime.Object{
+				deployment(unhealthyRelease, "app", 2, 1),
+				webhook(unhealthyRelease, "webhook-svc"),
+				endpoints("webhook-svc", false),
+			},
+			crd(unhealthyRelease, "apps.kyma-project.io", false),
+		)
+		problems, err := v.Check(map[Release]bool{unhealthyRelease: true})
+		require.NoError(t, err)
+		require.Len(t, problems[unhealthyRelease], 3)
+		require.Equal(t, "Deployment 'kyma-system/app': 1 of 2 replicas available", problems[unhealthyRelease][0].String())
+		require.Equal(t, "CustomResourceDefinition 'apps.kyma-project.io': not established", problems[unhealthyRelease][1].String())
+		require.Contains(t, problems[unhealthyRelease][2].String(), "service 'kyma-system/webhook-svc' has no ready endpoints")
+	})
+
+	t.Run("Resources of other releases are ignored", func(t *testing.T) {
+		v := newVerifier([]runtime.Object{deployment(unhealthyRelease, "app", 2, 0)})
+		problems, err := v.Check(map[Release]bool{healthyRelease: true})
+		require.NoError(t, err)
+		require.Empty(t, problems)
+	})
+}
+
+func TestWait(t *testing.T) {
+	t.Run("All releases healthy", func(t *testing.T) {
+		v := newVerifier([]runtime.Object{deployment(healthyRelease, "app", 1, 1)})
+		var healthy []Release
+		results, err := v.Wait([]Release{healthyRelease}, time.Second, func(r Release) { healthy = append(healthy, r) })
+		require.NoError(t, err)
+		require.Empty(t, results)
+		require.Equal(t, []Release{healthyRelease}, healthy)
+	})
+
+	t.Run("Unhealthy release with events", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "kyma-system", Labels: map[string]string{"app": "app"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		}
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "app-1.event", Namespace: "kyma-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "app-1"},
+			Type:           "Warning",
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+		}
+		v := newVerifier([]runtime.Object{
+			deployment(healthyRelease, "healthy-app", 1, 1),
+			deployment(unhealthyRelease, "app", 1, 0),
+			pod,
+			event,
+		})
+		v.Interval = 10 * time.Millisecond
+
+		var healthy []Release
+		results, err := v.Wait([]Release{healthyRelease, unhealthyRelease}, 50*time.Millisecond, func(r Release) { healthy = append(healthy, r) })
+		require.NoError(t, err)
+		require.Equal(t, []Release{healthyRelease}, healthy)
+		require.Len(t, results, 1)
+		require.Equal(t, unhealthyRelease, results[0].Release)
+		require.Equal(t, []string{
+			"Container 'app' of pod 'app-1' is waiting: CrashLoopBackOff (4 restarts)",
+			"Warning BackOff pod/app-1: Back-off restarting failed container",
+		}, results[0].Problems[0].Events)
+	})
+}
+
+func newVerifier(objects []runtime.Object, crds ...runtime.Object) *Verifier {
+	dynamic := dynFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"}, crds...)
+	return New(fake.NewSimpleClientset(objects...), dynamic)
+}
+
+func annotations(release Release) map[string]string {
+	return map[string]string{releaseNameAnnotation: release.Name, releaseNamespaceAnnotation: release.Namespace}
+}
+
+func deployment(release Release, name string, replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: release.Namespace, Annotations: annotations(release)},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: appsv1.DeploymentStatus{UpdatedReplicas: replicas, AvailableReplicas: available},
+	}
+}
+
+func crd(release Release, name string, established bool) *unstructured.Unstructured {
+	status := "False"
+	if established {
+		status = "True"
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": status}},
+		},
+	}}
+	u.SetAnnotations(annotations(release))
+	return u
+}
+
+func webhook(release Release, service string) *admissionv1.ValidatingWebhookConfiguration {
+	return &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: release.Name, Annotations: annotations(release)},
+		Webhooks: []admissionv1.ValidatingWebhook{{
+			Name:         "validation.kyma-project.io",
+			ClientConfig: admissionv1.WebhookClientConfig{Service: &admissionv1.ServiceReference{Namespace: "kyma-system", Name: service}},
+		}},
+	}
+}
+
+func endpoints(service string, ready bool) *corev1.Endpoints {
+	address := []corev1.EndpointAddress{{IP: "10.0.0.1"}}
+	subset := corev1.EndpointSubset{NotReadyAddresses: address}
+	if ready {
+		subset = corev1.EndpointSubset{Addresses: address}
+	}
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: service, Namespace: "kyma-system"},
+		Subsets:    []corev1.EndpointSubset{subset},
+	}
+}
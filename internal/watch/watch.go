@@ -0,0 +1,178 @@
+// Package watch reports the changes of the component charts and the component list in local Kyma sources.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+const defaultQuietPeriod = 500 * time.Millisecond
+
+// Change describes what changed in the sources since the last reported change.
+type Change struct {
+	// Components are the names of the components whose charts changed, sorted by name.
+	Components []string
+	// ComponentList is true if the component list file changed.
+	ComponentList bool
+}
+
+// Watcher watches the chart directories of the components and the component list file.
+type Watcher struct {
+	// QuietPeriod is the time without further changes after which a change is reported,
+	// so that saving several files at once results in a single change.
+	QuietPeriod time.Duration
+
+	resourcesDir   string
+	componentsFile string
+	fs             *fsnotify.Watcher
+}
+
+// New creates a watcher for the component charts in the resources directory (one sub-directory per component)
+// and the component list file.
+func New(resourcesDir, componentsFile string) (*Watcher, error) {
+	resourcesDir, err := filepath.Abs(resourcesDir)
+	if err != nil {
+		return nil, err
+	}
+	componentsFile, err = filepath.Abs(componentsFile)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create the file watcher")
+	}
+	w := &Watcher{
+		QuietPeriod:    defaultQuietPeriod,
+		resourcesDir:   resourcesDir,
+		componentsFile: componentsFile,
+		fs:             fs,
+	}
+	if err := w.addTree(resourcesDir); err != nil {
+		fs.Close()
+		return nil, errors.Wrapf(err, "Could not watch the charts in '%s'", resourcesDir)
+	}
+	// editors often replace a file instead of writing it, so the directory of the file is watched
+	if err := fs.Add(filepath.Dir(componentsFile)); err != nil {
+		fs.Close()
+		return nil, errors.Wrapf(err, "Could not watch the component list '%s'", componentsFile)
+	}
+	return w, nil
+}
+
+// Next blocks until the sources changed and returns the change.
+// It returns the error of the context if the context is done before.
+func (w *Watcher) Next(ctx context.Context) (*Change, error) {
+	components := make(map[string]bool)
+	change := &Change{}
+	changed := false
+
+	quiet := time.NewTimer(w.QuietPeriod)
+	quiet.Stop()
+	defer quiet.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil, errors.New("The file watcher was closed")
+			}
+			return nil, errors.Wrap(err, "Could not watch the sources")
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil, errors.New("The file watcher was closed")
+			}
+			if !w.handle(event, change, components) {
+				continue
+			}
+			changed = true
+			// wait until the sources stay unchanged for the quiet period
+			if !quiet.Stop() {
+				select {
+				case <-quiet.C:
+				default:
+				}
+			}
+			quiet.Reset(w.QuietPeriod)
+		case <-quiet.C:
+			if !changed {
+				continue
+			}
+			for name := range components {
+				change.Components = append(change.Components, name)
+			}
+			sort.Strings(change.Components)
+			return change, nil
+		}
+	}
+}
+
+// Close stops watching the sources.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// handle records the event in the change and returns true if the event is relevant.
+func (w *Watcher) handle(event fsnotify.Event, change *Change, components map[string]bool) bool {
+	if event.Op == fsnotify.Chmod || ignored(event.Name) {
+		return false
+	}
+	path := filepath.Clean(event.Name)
+	if path == w.componentsFile {
+		change.ComponentList = true
+		return true
+	}
+	component := w.componentOf(path)
+	if component == "" {
+		return false
+	}
+	// watch the directories created in the charts (fsnotify does not watch recursively)
+	if event.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			_ = w.addTree(path)
+		}
+	}
+	components[component] = true
+	return true
+}
+
+// componentOf returns the name of the component whose chart contains the path,
+// or an empty string if the path is not part of a chart.
+func (w *Watcher) componentOf(path string) string {
+	rel, err := filepath.Rel(w.resourcesDir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return strings.Split(rel, string(filepath.Separator))[0]
+}
+
+// addTree watches the directory and all its sub-directories.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && ignored(path) {
+			return filepath.SkipDir
+		}
+		return w.fs.Add(path)
+	})
+}
+
+// ignored returns true for hidden files and the backup and swap files of editors.
+func ignored(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") || strings.HasSuffix(name, ".swp")
+}
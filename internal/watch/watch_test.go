@@ -0,0 +1,77 @@
+package watch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	resourcesDir := filepath.Join(dir, "resources")
+	componentsFile := filepath.Join(dir, "installation", "resources", "components.yaml")
+	writeFile(t, filepath.Join(resourcesDir, "istio", "templates", "deployment.yaml"))
+	writeFile(t, filepath.Join(resourcesDir, "eventing", "values.yaml"))
+	writeFile(t, componentsFile)
+
+	w, err := New(resourcesDir, componentsFile)
+	require.NoError(t, err)
+	defer w.Close()
+	w.QuietPeriod = 50 * time.Millisecond
+
+	t.Run("Changed charts", func(t *testing.T) {
+		writeFile(t, filepath.Join(resourcesDir, "istio", "templates", "deployment.yaml"))
+		writeFile(t, filepath.Join(resourcesDir, "eventing", "values.yaml"))
+		writeFile(t, filepath.Join(resourcesDir, "eventing", ".values.yaml.swp"))
+
+		change := next(t, w)
+		require.Equal(t, []string{"eventing", "istio"}, change.Components)
+		require.False(t, change.ComponentList)
+	})
+
+	t.Run("New directory in chart", func(t *testing.T) {
+		writeFile(t, filepath.Join(resourcesDir, "istio", "charts", "gateway", "Chart.yaml"))
+		next(t, w)
+
+		writeFile(t, filepath.Join(resourcesDir, "istio", "charts", "gateway", "Chart.yaml"))
+		change := next(t, w)
+		require.Equal(t, []string{"istio"}, change.Components)
+	})
+
+	t.Run("Changed component list", func(t *testing.T) {
+		writeFile(t, componentsFile)
+		writeFile(t, filepath.Join(filepath.Dir(componentsFile), "values.yaml"))
+
+		change := next(t, w)
+		require.Empty(t, change.Components)
+		require.True(t, change.ComponentList)
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := w.Next(ctx)
+		require.Equal(t, context.Canceled, err)
+	})
+}
+
+func next(t *testing.T, w *Watcher) *Change {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	change, err := w.Next(ctx)
+	require.NoError(t, err)
+	return change
+}
+
+func writeFile(t *testing.T, file string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+	require.NoError(t, ioutil.WriteFile(file, []byte(time.Now().String()), 0600))
+}
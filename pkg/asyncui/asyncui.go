@@ -14,10 +14,16 @@ const (
 	undeployPrerequisitesPhaseMsg string = "Undeploying pre-requisites"
 	deployComponentsPhaseMsg      string = "Deploying Kyma"
 	undeployComponentsPhaseMsg    string = "Undeploying Kyma"
+	verifyComponentsPhaseMsg      string = "Verifying Kyma"
 	deployComponentMsg            string = "Component '%s' deployed"
 	undeployComponentMsg          string = "Component '%s' removed"
+	verifyComponentMsg            string = "Component '%s' is healthy"
 )
 
+//VerifyComponents is the installation phase which verifies that the deployed components are healthy.
+//It is not triggered by the deployment but by the CLI after the deployment finished.
+const VerifyComponents deployment.InstallationPhase = "VerifyComponents"
+
 //AsyncUI renders the CLI ui based on receiving events
 type AsyncUI struct {
 	//used to create UI steps
@@ -67,9 +73,14 @@ func (ui *AsyncUI) renderStartEvent(procUpdEvent deployment.ProcessUpdate, ongoi
 }
 
 func (ui *AsyncUI) majorStepMsg(procUpdEvent deployment.ProcessUpdate) string {
+	return phaseMsg(procUpdEvent.Phase)
+}
+
+//phaseMsg returns the end-user message of an installation phase
+func phaseMsg(phase deployment.InstallationPhase) string {
 	//create a major step
 	var stepMsg string
-	switch procUpdEvent.Phase {
+	switch phase {
 	case deployment.InstallPreRequisites:
 		stepMsg = deployPrerequisitesPhaseMsg
 	case deployment.UninstallPreRequisites:
@@ -78,10 +89,12 @@ func (ui *AsyncUI) majorStepMsg(procUpdEvent deployment.ProcessUpdate) string {
 		stepMsg = deployComponentsPhaseMsg
 	case deployment.UninstallComponents:
 		stepMsg = undeployComponentsPhaseMsg
+	case VerifyComponents:
+		stepMsg = verifyComponentsPhaseMsg
 	default:
 		//non-deployment specific installation phase
 		//e.g. steps triggered by CLI before or after the deployment
-		stepMsg = string(procUpdEvent.Phase)
+		stepMsg = string(phase)
 	}
 	return stepMsg
 }
@@ -133,18 +146,10 @@ func (ui *AsyncUI) renderStopEventComponent(procUpdEvent deployment.ProcessUpdat
 	installPhase := procUpdEvent.Phase
 	cmpErr := comp.Error
 
-	//determine step name
-	var stepName string
-	if installPhase == deployment.InstallComponents || installPhase == deployment.InstallPreRequisites {
-		stepName = fmt.Sprintf(deployComponentMsg, comp.Name)
-	} else {
-		stepName = fmt.Sprintf(undeployComponentMsg, comp.Name)
-	}
-
 	//create step for processed component
-	step := ui.StepFactory.NewStep(stepName)
+	step := ui.StepFactory.NewStep(componentMsg(installPhase, comp.Name))
 	if comp.Status == components.StatusError {
-		errMsg := fmt.Sprintf("Deployment of component '%s' failed", comp.Name)
+		errMsg := componentErrorMsg(installPhase, comp.Name)
 		if cmpErr != nil {
 			errMsg = fmt.Sprintf("%s\n%s", errMsg, cmpErr)
 		}
@@ -156,6 +161,25 @@ func (ui *AsyncUI) renderStopEventComponent(procUpdEvent deployment.ProcessUpdat
 	return nil
 }
 
+//componentMsg returns the end-user message of a component which was processed successfully
+func componentMsg(phase deployment.InstallationPhase, name string) string {
+	switch phase {
+	case deployment.InstallComponents, deployment.InstallPreRequisites:
+		return fmt.Sprintf(deployComponentMsg, name)
+	case VerifyComponents:
+		return fmt.Sprintf(verifyComponentMsg, name)
+	}
+	return fmt.Sprintf(undeployComponentMsg, name)
+}
+
+//componentErrorMsg returns the end-user message of a component which failed
+func componentErrorMsg(phase deployment.InstallationPhase, name string) string {
+	if phase == VerifyComponents {
+		return fmt.Sprintf("Component '%s' is not healthy", name)
+	}
+	return fmt.Sprintf("Deployment of component '%s' failed", name)
+}
+
 //AddStep adds an additional installation step
 func (ui *AsyncUI) AddStep(step string) (step.Step, error) {
 	return ui.StepFactory.NewStep(step), nil
@@ -171,6 +171,47 @@ func TestFailedComponent(t *testing.T) {
 		assert.Contains(t, mockStepFactory.Steps[3].Statuses(), fmt.Sprintf(deployComponentMsg, "comp2"))
 		assert.False(t, mockStepFactory.Steps[3].IsSuccessful()) //comp2 install failed
 	})
+
+	t.Run("Verify components after deployment", func(t *testing.T) {
+		t.Parallel()
+		callback, mockStepFactory := prepareTest()
+
+		callback(deployment.ProcessUpdate{
+			Event:     deployment.ProcessStart,
+			Phase:     VerifyComponents,
+			Component: components.KymaComponent{},
+		})
+		callback(deployment.ProcessUpdate{
+			Event: deployment.ProcessRunning,
+			Phase: VerifyComponents,
+			Component: components.KymaComponent{
+				Name:   "comp1",
+				Status: components.StatusInstalled,
+			},
+		})
+		callback(deployment.ProcessUpdate{
+			Event: deployment.ProcessRunning,
+			Phase: VerifyComponents,
+			Component: components.KymaComponent{
+				Name:   "comp2",
+				Status: components.StatusError,
+				Error:  fmt.Errorf("Deployment 'kyma-system/comp2': 0 of 1 replicas available"),
+			},
+		})
+		callback(deployment.ProcessUpdate{
+			Event:     deployment.ProcessExecutionFailure,
+			Phase:     VerifyComponents,
+			Component: components.KymaComponent{},
+		})
+
+		assert.Len(t, mockStepFactory.Steps, 3)
+		assert.Contains(t, mockStepFactory.Steps[0].Statuses(), verifyComponentsPhaseMsg)
+		assert.False(t, mockStepFactory.Steps[0].IsSuccessful())
+		assert.Contains(t, mockStepFactory.Steps[1].Statuses(), fmt.Sprintf(verifyComponentMsg, "comp1"))
+		assert.True(t, mockStepFactory.Steps[1].IsSuccessful())
+		assert.Contains(t, mockStepFactory.Steps[2].Statuses(), fmt.Sprintf(verifyComponentMsg, "comp2"))
+		assert.False(t, mockStepFactory.Steps[2].IsSuccessful())
+	})
 }
 
 func prepareTest() (func(deployment.ProcessUpdate), *StepFactoryMock) {
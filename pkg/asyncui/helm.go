@@ -0,0 +1,38 @@
+package asyncui
+
+import (
+	"context"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+//helmReleaseSelector selects the release secrets of Helm, which are labeled with the name, version, and status of the release
+const helmReleaseSelector = "owner=helm"
+
+//HelmTracker returns a tracker which reports the status of the latest Helm release of each component, e.g. "pending-install".
+//Each component is deployed as Helm release which is named like the component.
+func HelmTracker(client kubernetes.Interface) Tracker {
+	return func() (map[string]string, error) {
+		secrets, err := client.CoreV1().Secrets("").List(context.Background(), metav1.ListOptions{LabelSelector: helmReleaseSelector})
+		if err != nil {
+			return nil, err
+		}
+		statuses := make(map[string]string)
+		versions := make(map[string]int)
+		for _, secret := range secrets.Items {
+			name := secret.Labels["name"]
+			version, err := strconv.Atoi(secret.Labels["version"])
+			if err != nil {
+				continue
+			}
+			if latest, ok := versions[name]; ok && latest > version {
+				continue
+			}
+			versions[name] = version
+			statuses[name] = secret.Labels["status"]
+		}
+		return statuses, nil
+	}
+}
@@ -0,0 +1,38 @@
+package asyncui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHelmTracker(t *testing.T) {
+	release := func(name, namespace, version, status string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v" + version,
+			Namespace: namespace,
+			Labels:    map[string]string{"owner": "helm", "name": name, "status": status, "version": version},
+		}}
+	}
+	client := fake.NewSimpleClientset(
+		release("istio", "istio-system", "1", "deployed"),
+		release("serverless", "kyma-system", "1", "superseded"),
+		release("serverless", "kyma-system", "2", "pending-upgrade"),
+		// the versions are compared as numbers
+		release("eventing", "kyma-system", "10", "pending-install"),
+		release("eventing", "kyma-system", "9", "failed"),
+		release("monitoring", "kyma-system", "1", "failed"),
+	)
+
+	statuses, err := HelmTracker(client)()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"istio":      "deployed",
+		"serverless": "pending-upgrade",
+		"eventing":   "pending-install",
+		"monitoring": "failed",
+	}, statuses)
+}
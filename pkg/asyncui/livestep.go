@@ -0,0 +1,87 @@
+package asyncui
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/kyma-project/cli/pkg/step"
+)
+
+//NewStep creates a step whose messages are shown as permanent lines above the live rows,
+//so that steps which run during the deployment, e.g. hooks, do not break the live view.
+//The step cannot prompt the user.
+func (ui *LiveUI) NewStep(msg string) step.Step {
+	return &liveStep{ui: ui, msg: msg}
+}
+
+type liveStep struct {
+	ui  *LiveUI
+	msg string
+}
+
+func (s *liveStep) print(line string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.draw(line)
+}
+
+func (s *liveStep) Start() {
+	s.print(s.msg)
+}
+
+func (s *liveStep) Status(msg string) {
+	s.print(fmt.Sprintf("%s: %s", s.msg, msg))
+}
+
+func (s *liveStep) Success() {
+	s.Stop(true)
+}
+
+func (s *liveStep) Successf(format string, args ...interface{}) {
+	s.Stopf(true, format, args...)
+}
+
+func (s *liveStep) Failure() {
+	s.Stop(false)
+}
+
+func (s *liveStep) Failuref(format string, args ...interface{}) {
+	s.Stopf(false, format, args...)
+}
+
+func (s *liveStep) Stopf(success bool, format string, args ...interface{}) {
+	s.msg = fmt.Sprintf(format, args...)
+	s.Stop(success)
+}
+
+func (s *liveStep) Stop(success bool) {
+	if success {
+		s.print(color.GreenString("- ") + s.msg)
+		return
+	}
+	s.print(color.RedString("X ") + s.msg)
+}
+
+func (s *liveStep) LogInfo(msg string) {
+	s.print(msg)
+}
+
+func (s *liveStep) LogInfof(format string, args ...interface{}) {
+	s.LogInfo(fmt.Sprintf(format, args...))
+}
+
+func (s *liveStep) LogError(msg string) {
+	s.print(color.YellowString("! ") + msg)
+}
+
+func (s *liveStep) LogErrorf(format string, args ...interface{}) {
+	s.LogError(fmt.Sprintf(format, args...))
+}
+
+func (s *liveStep) Prompt(msg string) (string, error) {
+	return "", fmt.Errorf("Cannot prompt '%s' while the live view is shown", msg)
+}
+
+func (s *liveStep) PromptYesNo(msg string) bool {
+	return false
+}
@@ -0,0 +1,368 @@
+package asyncui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	defaultRefreshInterval = 500 * time.Millisecond
+	defaultPollInterval    = 2 * time.Second
+
+	//statusFailed is the status of a Helm release whose last operation failed
+	statusFailed = "failed"
+
+	//ANSI escape sequences to move the cursor up and to clear the screen below the cursor
+	cursorUp    = "\033[%dA"
+	clearScreen = "\033[J"
+)
+
+var spinnerFrames = []string{"/", "-", "\\", "|"}
+
+//inProgressStatuses are the statuses of Helm releases which are being installed, upgraded, rolled back, or uninstalled
+var inProgressStatuses = map[string]bool{
+	"pending-install":  true,
+	"pending-upgrade":  true,
+	"pending-rollback": true,
+	"uninstalling":     true,
+}
+
+//IsTerminal returns true if the output is written to an interactive terminal which supports the live view
+func IsTerminal() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+//Tracker returns the status of the Helm release of each component which is deployed, e.g. "pending-install" while it is installed
+type Tracker func() (map[string]string, error)
+
+//LiveUI renders the CLI ui as live view: each running phase shows one row per component which is currently processed,
+//including the elapsed time and the retries. Finished components collapse into a single line.
+//
+//The deployment only reports finished components. Which components are in progress is reported by the tracker,
+//which is polled periodically. A component which failed and is in progress again is retried.
+//Without tracker, only the number of unfinished components is shown.
+type LiveUI struct {
+	//a failure occurred
+	Failed bool
+	//Tracker reports the status of the components (optional)
+	Tracker Tracker
+
+	out        io.Writer
+	queues     map[deployment.InstallationPhase][]installConfig.ComponentDefinition
+	now        func() time.Time
+	refresh    time.Duration
+	poll       time.Duration
+	mu         sync.Mutex
+	phases     []*livePhase
+	drawnLines int
+	frame      int
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+//livePhase is a running phase of the live view
+type livePhase struct {
+	name  deployment.InstallationPhase
+	start time.Time
+	//pending are the components which are not finished yet, in the order of the queue
+	pending []installConfig.ComponentDefinition
+	//tracked are the components which the tracker reported
+	tracked map[string]*liveComponent
+}
+
+//liveComponent is a component of a running phase which the tracker reported
+type liveComponent struct {
+	//start is the time when the tracker reported the component as in progress first, or zero if it is not in progress
+	start time.Time
+	//failed is true if the component failed after it was in progress, so that it is retried once it is in progress again
+	failed  bool
+	retries int
+}
+
+func (c *liveComponent) running() bool {
+	return !c.start.IsZero()
+}
+
+//NewLiveUI creates a live view which writes to the given terminal.
+//The queues define the components of each phase.
+func NewLiveUI(out io.Writer, queues map[deployment.InstallationPhase][]installConfig.ComponentDefinition) *LiveUI {
+	return &LiveUI{
+		out:     out,
+		queues:  queues,
+		now:     time.Now,
+		refresh: defaultRefreshInterval,
+		poll:    defaultPollInterval,
+	}
+}
+
+//Callback renders the live view and provides the function for receiving events
+func (ui *LiveUI) Callback() func(update deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		ui.mu.Lock()
+		defer ui.mu.Unlock()
+
+		switch {
+		case update.IsComponentUpdate():
+			ui.finishComponent(update)
+		case update.Event == deployment.ProcessStart:
+			ui.startPhase(update)
+		case update.Event != deployment.ProcessRunning:
+			ui.finishPhase(update)
+		}
+	}
+}
+
+//Stop stops refreshing the live view. It has to be called when the process finished.
+func (ui *LiveUI) Stop() {
+	ui.mu.Lock()
+	stop, stopped := ui.stop, ui.stopped
+	ui.stop = nil
+	ui.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+func (ui *LiveUI) startPhase(update deployment.ProcessUpdate) {
+	if ui.phase(update.Phase) != nil {
+		ui.Failed = true
+		return
+	}
+	p := &livePhase{
+		name:    update.Phase,
+		start:   ui.now(),
+		pending: append([]installConfig.ComponentDefinition{}, ui.queues[update.Phase]...),
+		tracked: make(map[string]*liveComponent),
+	}
+	ui.phases = append(ui.phases, p)
+	ui.draw()
+
+	if ui.stop == nil {
+		ui.stop = make(chan struct{})
+		ui.stopped = make(chan struct{})
+		go ui.refreshLoop(ui.stop, ui.stopped)
+	}
+}
+
+func (ui *LiveUI) finishComponent(update deployment.ProcessUpdate) {
+	comp := update.Component
+	now := ui.now()
+
+	var elapsed time.Duration
+	if p := ui.phase(update.Phase); p != nil {
+		if c := p.remove(comp.Name); c != nil && c.running() {
+			elapsed = now.Sub(c.start)
+		}
+	}
+
+	var line string
+	if comp.Status == components.StatusError {
+		ui.Failed = true
+		line = color.RedString("X ") + componentErrorMsg(update.Phase, comp.Name)
+	} else {
+		line = color.GreenString("- ") + componentMsg(update.Phase, comp.Name)
+	}
+	if elapsed > 0 {
+		line = fmt.Sprintf("%s (%s)", line, formatElapsed(elapsed))
+	}
+	if comp.Error != nil {
+		line = fmt.Sprintf("%s\n%s", line, comp.Error)
+	}
+	ui.draw(line)
+}
+
+func (ui *LiveUI) finishPhase(update deployment.ProcessUpdate) {
+	p := ui.phase(update.Phase)
+	if p == nil {
+		ui.Failed = true
+		return
+	}
+	ui.removePhase(p)
+
+	msg := phaseMsg(p.name)
+	elapsed := formatElapsed(ui.now().Sub(p.start))
+	var line string
+	if update.Event == deployment.ProcessFinished {
+		line = fmt.Sprintf("%s%s finished successfully (%s)", color.GreenString("- "), msg, elapsed)
+	} else {
+		ui.Failed = true
+		line = fmt.Sprintf("%s%s failed (%s)", color.RedString("X "), msg, elapsed)
+		if update.Error != nil {
+			line = fmt.Sprintf("%s\n%s", line, update.Error)
+		}
+	}
+	ui.draw(line)
+}
+
+//refreshLoop redraws the live view periodically to update the spinner and the elapsed times
+//and polls the tracker for the components in progress
+func (ui *LiveUI) refreshLoop(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(ui.refresh)
+	defer ticker.Stop()
+	var polling <-chan time.Time
+	if ui.Tracker != nil {
+		pollTicker := time.NewTicker(ui.poll)
+		defer pollTicker.Stop()
+		polling = pollTicker.C
+	}
+	for {
+		select {
+		case <-stop:
+			return
+		case <-polling:
+			ui.track()
+		case <-ticker.C:
+			ui.mu.Lock()
+			ui.frame++
+			ui.draw()
+			ui.mu.Unlock()
+		}
+	}
+}
+
+//track updates the components in progress and their retries with the statuses reported by the tracker.
+//If the tracker fails, the components are shown as before until the next poll.
+func (ui *LiveUI) track() {
+	statuses, err := ui.Tracker()
+	if err != nil {
+		return
+	}
+
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	now := ui.now()
+	for _, p := range ui.phases {
+		for _, comp := range p.pending {
+			c, ok := p.tracked[comp.Name]
+			if !ok {
+				c = &liveComponent{}
+				p.tracked[comp.Name] = c
+			}
+			status := statuses[comp.Name]
+			switch {
+			case inProgressStatuses[status] && !c.running():
+				c.start = now
+				if c.failed {
+					c.failed = false
+					c.retries++
+				}
+			case inProgressStatuses[status]:
+				// still in progress
+			case status == statusFailed && c.running():
+				// the component is retried if it is in progress again
+				c.failed = true
+				c.start = time.Time{}
+			default:
+				c.start = time.Time{}
+			}
+		}
+	}
+}
+
+//draw replaces the live rows with the permanent lines followed by the current live rows
+func (ui *LiveUI) draw(permanent ...string) {
+	if ui.drawnLines > 0 {
+		fmt.Fprintf(ui.out, cursorUp+clearScreen, ui.drawnLines)
+	}
+	for _, line := range permanent {
+		fmt.Fprintln(ui.out, line)
+	}
+	rows := ui.rows()
+	for _, row := range rows {
+		fmt.Fprintln(ui.out, row)
+	}
+	ui.drawnLines = len(rows)
+}
+
+//rows returns the live rows of all running phases
+func (ui *LiveUI) rows() []string {
+	now := ui.now()
+	spinner := spinnerFrames[ui.frame%len(spinnerFrames)]
+
+	var rows []string
+	for _, p := range ui.phases {
+		rows = append(rows, fmt.Sprintf("%s %s (%s)", spinner, phaseMsg(p.name), formatElapsed(now.Sub(p.start))))
+
+		// the running components are shown in the order of the queue
+		var running []installConfig.ComponentDefinition
+		nameWidth, namespaceWidth := 0, 0
+		for _, comp := range p.pending {
+			if c, ok := p.tracked[comp.Name]; ok && c.running() {
+				running = append(running, comp)
+				nameWidth = max(nameWidth, len(comp.Name))
+				namespaceWidth = max(namespaceWidth, len(comp.Namespace))
+			}
+		}
+		for _, comp := range running {
+			c := p.tracked[comp.Name]
+			row := fmt.Sprintf("    %-*s  %-*s  %6s", nameWidth, comp.Name, namespaceWidth, comp.Namespace, formatElapsed(now.Sub(c.start)))
+			if c.retries > 0 {
+				row = fmt.Sprintf("%s  %s", row, color.YellowString("%d retries", c.retries))
+			}
+			rows = append(rows, row)
+		}
+		waiting := len(p.pending) - len(running)
+		switch {
+		case waiting <= 0:
+		case ui.Tracker == nil:
+			rows = append(rows, fmt.Sprintf("    %d components in progress or waiting", waiting))
+		default:
+			rows = append(rows, fmt.Sprintf("    %d components waiting", waiting))
+		}
+	}
+	return rows
+}
+
+func (ui *LiveUI) phase(name deployment.InstallationPhase) *livePhase {
+	for _, p := range ui.phases {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (ui *LiveUI) removePhase(phase *livePhase) {
+	for i, p := range ui.phases {
+		if p == phase {
+			ui.phases = append(ui.phases[:i], ui.phases[i+1:]...)
+			return
+		}
+	}
+}
+
+//remove removes the finished component and returns what the tracker reported about it, if anything
+func (p *livePhase) remove(name string) *liveComponent {
+	for i, comp := range p.pending {
+		if comp.Name == name {
+			p.pending = append(p.pending[:i], p.pending[i+1:]...)
+			break
+		}
+	}
+	c := p.tracked[name]
+	delete(p.tracked, name)
+	return c
+}
+
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,166 @@
+package asyncui
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveUI(t *testing.T) {
+	// compare the output without color codes
+	color.NoColor = true
+
+	t.Run("Show running components and collapse finished ones", func(t *testing.T) {
+		t.Parallel()
+		ui, out, now := prepareLiveTest()
+		statuses := map[string]string{"a": "pending-install", "b": "pending-install"}
+		ui.Tracker = func() (map[string]string, error) { return statuses, nil }
+		callback := ui.Callback()
+		defer ui.Stop()
+
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallComponents})
+		ui.track()
+		assert.Equal(t, []string{
+			"/ Deploying Kyma (0s)",
+			"    a  ns-a      0s",
+			"    b  ns-b      0s",
+			"    1 components waiting",
+		}, ui.rows())
+
+		*now = now.Add(3 * time.Second)
+		out.Reset()
+		callback(componentFinished("b", components.StatusInstalled))
+		assert.Contains(t, out.String(), "- Component 'b' deployed (3s)\n")
+		statuses = map[string]string{"a": "pending-install", "b": "deployed", "c": "pending-install"}
+		ui.track()
+		assert.Equal(t, []string{
+			"/ Deploying Kyma (3s)",
+			"    a  ns-a      3s",
+			"    c  ns-c      0s",
+		}, ui.rows())
+
+		*now = now.Add(2 * time.Second)
+		callback(componentFinished("a", components.StatusError))
+		callback(componentFinished("c", components.StatusInstalled))
+		assert.True(t, ui.Failed)
+		out.Reset()
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessExecutionFailure, Phase: deployment.InstallComponents})
+		assert.Equal(t, fmt.Sprintf(cursorUp+clearScreen, 1)+"X Deploying Kyma failed (5s)\n", out.String())
+		assert.Empty(t, ui.rows())
+	})
+
+	t.Run("Components which are not reported anymore are waiting", func(t *testing.T) {
+		t.Parallel()
+		ui, _, _ := prepareLiveTest()
+		statuses := map[string]string{"a": "pending-install"}
+		ui.Tracker = func() (map[string]string, error) { return statuses, nil }
+		callback := ui.Callback()
+		defer ui.Stop()
+
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallComponents})
+		ui.track()
+		assert.Equal(t, "    a  ns-a      0s", ui.rows()[1])
+
+		// e.g. the installation failed and is retried later
+		statuses = map[string]string{"a": "failed"}
+		ui.track()
+		assert.Equal(t, []string{"/ Deploying Kyma (0s)", "    3 components waiting"}, ui.rows())
+	})
+
+	t.Run("Count retries of failed components", func(t *testing.T) {
+		t.Parallel()
+		ui, _, now := prepareLiveTest()
+		statuses := map[string]string{"a": "pending-install", "b": "failed"}
+		ui.Tracker = func() (map[string]string, error) { return statuses, nil }
+		callback := ui.Callback()
+		defer ui.Stop()
+
+		// "b" failed in an earlier deployment, which is no retry
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallComponents})
+		ui.track()
+		statuses = map[string]string{"a": "failed", "b": "pending-upgrade"}
+		ui.track()
+		*now = now.Add(2 * time.Second)
+		statuses = map[string]string{"a": "pending-install", "b": "pending-upgrade"}
+		ui.track()
+		statuses = map[string]string{"a": "failed", "b": "pending-upgrade"}
+		ui.track()
+		statuses = map[string]string{"a": "pending-install", "b": "pending-upgrade"}
+		ui.track()
+		assert.Equal(t, []string{
+			"/ Deploying Kyma (2s)",
+			"    a  ns-a      0s  2 retries",
+			"    b  ns-b      2s",
+			"    1 components waiting",
+		}, ui.rows())
+	})
+
+	t.Run("Show unfinished components without tracker", func(t *testing.T) {
+		t.Parallel()
+		ui, _, _ := prepareLiveTest()
+		callback := ui.Callback()
+		defer ui.Stop()
+
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallPreRequisites})
+		callback(deployment.ProcessUpdate{
+			Event:     deployment.ProcessRunning,
+			Phase:     deployment.InstallPreRequisites,
+			Component: components.KymaComponent{Name: "cluster-essentials", Status: components.StatusInstalled},
+		})
+		assert.Equal(t, []string{"/ Deploying pre-requisites (0s)", "    1 components in progress or waiting"}, ui.rows())
+	})
+
+	t.Run("Show steps above the live rows", func(t *testing.T) {
+		t.Parallel()
+		ui, out, _ := prepareLiveTest()
+		callback := ui.Callback()
+		defer ui.Stop()
+
+		callback(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallComponents})
+		out.Reset()
+		step := ui.NewStep("Running the pre-deploy hook 'seed.sh' of component 'a'")
+		step.Successf("The pre-deploy hook 'seed.sh' of component 'a' succeeded")
+		step.LogInfo("seeded")
+		assert.Equal(t, fmt.Sprintf(cursorUp+clearScreen, 2)+"- The pre-deploy hook 'seed.sh' of component 'a' succeeded\n"+
+			"/ Deploying Kyma (0s)\n    3 components in progress or waiting\n"+
+			fmt.Sprintf(cursorUp+clearScreen, 2)+"seeded\n"+
+			"/ Deploying Kyma (0s)\n    3 components in progress or waiting\n", out.String())
+		assert.False(t, step.PromptYesNo("Continue? "))
+	})
+}
+
+func prepareLiveTest() (*LiveUI, *bytes.Buffer, *time.Time) {
+	out := &bytes.Buffer{}
+	ui := NewLiveUI(out, map[deployment.InstallationPhase][]installConfig.ComponentDefinition{
+		deployment.InstallPreRequisites: {
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+			{Name: "istio", Namespace: "istio-system"},
+		},
+		deployment.InstallComponents: {
+			{Name: "a", Namespace: "ns-a"},
+			{Name: "b", Namespace: "ns-b"},
+			{Name: "c", Namespace: "ns-c"},
+		},
+	})
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	ui.now = func() time.Time { return now }
+	// the tests draw and poll explicitly
+	ui.refresh = time.Hour
+	ui.poll = time.Hour
+	return ui, out, &now
+}
+
+func componentFinished(name, status string) deployment.ProcessUpdate {
+	return deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallComponents,
+		Component: components.KymaComponent{Name: name, Status: status},
+	}
+}
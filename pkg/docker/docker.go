@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"strings"
@@ -39,6 +40,10 @@ type Client interface {
 	NegotiateAPIVersion(ctx context.Context)
 	ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error)
+	ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
+	ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error)
+	ImageTag(ctx context.Context, source, target string) error
 }
 
 type KymaClient interface {
@@ -167,6 +172,111 @@ func (k *kymaDockerClient) PushKymaInstaller(image string, currentStep step.Step
 	return nil
 }
 
+//PullImage pulls an image using the Docker credentials of the image registry
+func PullImage(ctx context.Context, c Client, image string) error {
+	authStr, err := registryAuth(image)
+	if err != nil {
+		return err
+	}
+
+	puller, err := c.ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: authStr})
+	if err != nil {
+		return err
+	}
+	defer puller.Close()
+
+	msg, err := streamError(puller)
+	if err != nil || msg == "" {
+		return err
+	}
+	return fmt.Errorf("failed to pull Docker image '%s': %s", image, msg)
+}
+
+//PushImage pushes an image using the Docker credentials of the image registry
+func PushImage(ctx context.Context, c Client, image string) error {
+	authStr, err := registryAuth(image)
+	if err != nil {
+		return err
+	}
+
+	pusher, err := c.ImagePush(ctx, image, types.ImagePushOptions{RegistryAuth: authStr})
+	if err != nil {
+		return err
+	}
+	defer pusher.Close()
+
+	msg, err := streamError(pusher)
+	if err != nil || msg == "" {
+		return err
+	}
+	if strings.Contains(msg, "unauthorized") || strings.Contains(msg, "requested access to the resource is denied") {
+		return fmt.Errorf("missing permissions to push Docker image '%s': %s\nPlease run `docker login` to authenticate", image, msg)
+	}
+	return fmt.Errorf("failed to push Docker image '%s': %s", image, msg)
+}
+
+//SaveImages writes the given images as one tarball (as created by 'docker save') to the writer
+func SaveImages(ctx context.Context, c Client, images []string, w io.Writer) error {
+	reader, err := c.ImageSave(ctx, images)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+//LoadImages loads the images of a tarball (as created by 'docker save') into the Docker daemon
+func LoadImages(ctx context.Context, c Client, r io.Reader) error {
+	resp, err := c.ImageLoad(ctx, r, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if !resp.JSON {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+
+	msg, err := streamError(resp.Body)
+	if err != nil || msg == "" {
+		return err
+	}
+	return fmt.Errorf("failed to load Docker images: %s", msg)
+}
+
+// registryAuth returns the encoded Docker credentials of the registry of an image.
+func registryAuth(image string) (string, error) {
+	domain, _ := splitDockerDomain(image)
+	auth, err := resolve(domain)
+	if err != nil {
+		return "", err
+	}
+	encodedJSON, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(encodedJSON), nil
+}
+
+// streamError reads the JSON message stream of the Docker daemon and returns the first reported error message.
+func streamError(r io.Reader) (string, error) {
+	var errorMessage ErrorMessage
+	buffIOReader := bufio.NewReader(r)
+	for {
+		streamBytes, err := buffIOReader.ReadBytes('\n')
+		if err == io.EOF {
+			return "", nil
+		}
+		if err := json.Unmarshal(streamBytes, &errorMessage); err != nil {
+			return "", err
+		}
+		if errorMessage.Error != "" {
+			return errorMessage.Error, nil
+		}
+	}
+}
+
 func splitDockerDomain(name string) (domain, remainder string) {
 	i := strings.IndexRune(name, '/')
 	if i == -1 || (!strings.ContainsAny(name[:i], ".:") && name[:i] != "localhost") {
@@ -1,6 +1,8 @@
 package docker
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -167,3 +169,88 @@ func Test_PushKymaInstaller(t *testing.T) {
 	assert.NilError(t, err)
 
 }
+
+func Test_PullImage(t *testing.T) {
+	tmpHome, err := ioutil.TempDir("/tmp", "config-pull-image-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpHome)
+	os.Setenv("DOCKER_CONFIG", tmpHome)
+
+	image := "example.com/foo:1.0.0"
+	encodedJSON, _ := json.Marshal(types.AuthConfig{})
+	imagePullOptions := imageTypes.ImagePullOptions{RegistryAuth: base64.URLEncoding.EncodeToString(encodedJSON)}
+
+	t.Run("Pull succeeds", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		stream := ioutil.NopCloser(strings.NewReader("{\"status\":\"Pulling from foo\"}\n{\"status\":\"Downloaded newer image\"}\n"))
+		mockDocker.On("ImagePull", mock.Anything, image, imagePullOptions).Return(stream, nil)
+
+		require.NoError(t, PullImage(context.Background(), mockDocker, image))
+	})
+	t.Run("Pull fails", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		stream := ioutil.NopCloser(strings.NewReader("{\"error\":\"manifest unknown\"}\n"))
+		mockDocker.On("ImagePull", mock.Anything, image, imagePullOptions).Return(stream, nil)
+
+		err := PullImage(context.Background(), mockDocker, image)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "manifest unknown")
+	})
+}
+
+func Test_PushImage(t *testing.T) {
+	tmpHome, err := ioutil.TempDir("/tmp", "config-push-image-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpHome)
+	os.Setenv("DOCKER_CONFIG", tmpHome)
+
+	image := "registry.corp.local/kyma/foo:1.0.0"
+	encodedJSON, _ := json.Marshal(types.AuthConfig{})
+	imagePushOptions := imageTypes.ImagePushOptions{RegistryAuth: base64.URLEncoding.EncodeToString(encodedJSON)}
+
+	t.Run("Push succeeds", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		stream := ioutil.NopCloser(strings.NewReader("{\"status\":\"Pushed\"}\n"))
+		mockDocker.On("ImagePush", mock.Anything, image, imagePushOptions).Return(stream, nil)
+
+		require.NoError(t, PushImage(context.Background(), mockDocker, image))
+	})
+	t.Run("Push is not authorized", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		stream := ioutil.NopCloser(strings.NewReader("{\"error\":\"unauthorized: authentication required\"}\n"))
+		mockDocker.On("ImagePush", mock.Anything, image, imagePushOptions).Return(stream, nil)
+
+		err := PushImage(context.Background(), mockDocker, image)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "docker login")
+	})
+}
+
+func Test_SaveImages(t *testing.T) {
+	images := []string{"example.com/foo:1.0.0", "example.com/bar:1.0.0"}
+	mockDocker := &mocks.Client{}
+	mockDocker.On("ImageSave", mock.Anything, images).Return(ioutil.NopCloser(strings.NewReader("tarball")), nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, SaveImages(context.Background(), mockDocker, images, &buf))
+	require.Equal(t, "tarball", buf.String())
+}
+
+func Test_LoadImages(t *testing.T) {
+	t.Run("Images loaded", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		mockDocker.On("ImageLoad", mock.Anything, mock.Anything, true).Return(imageTypes.ImageLoadResponse{
+			Body: ioutil.NopCloser(strings.NewReader(`{"stream":"Loaded image: example.com/foo:1.0.0"}` + "\n")),
+			JSON: true,
+		}, nil)
+		require.NoError(t, LoadImages(context.Background(), mockDocker, strings.NewReader("tarball")))
+	})
+	t.Run("Invalid tarball", func(t *testing.T) {
+		mockDocker := &mocks.Client{}
+		mockDocker.On("ImageLoad", mock.Anything, mock.Anything, true).Return(imageTypes.ImageLoadResponse{
+			Body: ioutil.NopCloser(strings.NewReader(`{"error":"unexpected EOF"}` + "\n")),
+			JSON: true,
+		}, nil)
+		require.EqualError(t, LoadImages(context.Background(), mockDocker, strings.NewReader("tarball")), "failed to load Docker images: unexpected EOF")
+	})
+}
@@ -63,6 +63,27 @@ func (_m *Client) ImageBuild(ctx context.Context, buildContext io.Reader, option
 	return r0, r1
 }
 
+// ImageLoad provides a mock function with given fields: ctx, input, quiet
+func (_m *Client) ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+	ret := _m.Called(ctx, input, quiet)
+
+	var r0 types.ImageLoadResponse
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, bool) types.ImageLoadResponse); ok {
+		r0 = rf(ctx, input, quiet)
+	} else {
+		r0 = ret.Get(0).(types.ImageLoadResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, bool) error); ok {
+		r1 = rf(ctx, input, quiet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImagePush provides a mock function with given fields: ctx, image, options
 func (_m *Client) ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error) {
 	ret := _m.Called(ctx, image, options)
@@ -86,6 +107,66 @@ func (_m *Client) ImagePush(ctx context.Context, image string, options types.Ima
 	return r0, r1
 }
 
+// ImagePull provides a mock function with given fields: ctx, ref, options
+func (_m *Client) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, ref, options)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ImagePullOptions) io.ReadCloser); ok {
+		r0 = rf(ctx, ref, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.ImagePullOptions) error); ok {
+		r1 = rf(ctx, ref, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageSave provides a mock function with given fields: ctx, imageIDs
+func (_m *Client) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, imageIDs)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, []string) io.ReadCloser); ok {
+		r0 = rf(ctx, imageIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, imageIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageTag provides a mock function with given fields: ctx, source, target
+func (_m *Client) ImageTag(ctx context.Context, source string, target string) error {
+	ret := _m.Called(ctx, source, target)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, source, target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NegotiateAPIVersion provides a mock function with given fields: ctx
 func (_m *Client) NegotiateAPIVersion(ctx context.Context) {
 	_m.Called(ctx)
@@ -0,0 +1,69 @@
+package jsonevents
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+)
+
+//Event is the machine-readable representation of a deployment process update
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Phase     string    `json:"phase"`
+	Event     string    `json:"event"`
+	Component string    `json:"component,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+//Stream writes each received process update as one JSON object per line
+type Stream struct {
+	encoder *json.Encoder
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+//NewStream creates a stream which writes the events to the writer
+func NewStream(w io.Writer) *Stream {
+	return &Stream{
+		encoder: json.NewEncoder(w),
+		now:     time.Now,
+	}
+}
+
+//Callback provides the function which receives the process updates of a deployment or deletion
+func (s *Stream) Callback() func(update deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// an event which cannot be written must not break the deployment
+		_ = s.encoder.Encode(s.event(update))
+	}
+}
+
+//event converts a process update into an event
+func (s *Stream) event(update deployment.ProcessUpdate) Event {
+	event := Event{
+		Timestamp: s.now().UTC(),
+		Phase:     string(update.Phase),
+		Event:     string(update.Event),
+	}
+	if update.Error != nil {
+		event.Error = update.Error.Error()
+	}
+	if update.IsComponentUpdate() {
+		comp := update.Component
+		event.Component = comp.Name
+		event.Namespace = comp.Namespace
+		event.Status = comp.Status
+		if comp.Error != nil {
+			event.Error = comp.Error.Error()
+		}
+	}
+	return event
+}
@@ -0,0 +1,58 @@
+package jsonevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewStream(&buf)
+	stream.now = func() time.Time { return time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC) }
+	callback := stream.Callback()
+
+	callback(deployment.ProcessUpdate{
+		Event: deployment.ProcessStart,
+		Phase: deployment.InstallComponents,
+	})
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallComponents,
+		Component: components.KymaComponent{Name: "comp1", Namespace: "kyma-system", Status: components.StatusInstalled},
+	})
+	callback(deployment.ProcessUpdate{
+		Event:     deployment.ProcessRunning,
+		Phase:     deployment.InstallComponents,
+		Component: components.KymaComponent{Name: "comp2", Namespace: "kyma-system", Status: components.StatusError, Error: errors.New("timeout")},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var events []Event
+	for _, line := range lines {
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	require.Equal(t, Event{
+		Timestamp: time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC),
+		Phase:     string(deployment.InstallComponents),
+		Event:     string(deployment.ProcessStart),
+	}, events[0])
+	require.Equal(t, "comp1", events[1].Component)
+	require.Equal(t, "kyma-system", events[1].Namespace)
+	require.Equal(t, components.StatusInstalled, events[1].Status)
+	require.Empty(t, events[1].Error)
+	require.Equal(t, "comp2", events[2].Component)
+	require.Equal(t, components.StatusError, events[2].Status)
+	require.Equal(t, "timeout", events[2].Error)
+}
@@ -13,10 +13,19 @@ type FactoryInterface interface {
 type Factory struct {
 	NonInteractive bool
 	UseLogger      bool
+	// Prefix is prepended to all messages of the steps, e.g. to tell apart the steps of processes running in parallel.
+	Prefix string
 }
 
 // NewStep creates a new Step to print out the current status with or without a spinner.
 func (f *Factory) NewStep(msg string) Step {
+	if f.Prefix != "" {
+		return newPrefixedStep(f.Prefix, f.newStep(f.Prefix+msg))
+	}
+	return f.newStep(msg)
+}
+
+func (f *Factory) newStep(msg string) Step {
 	if f.UseLogger {
 		return newLogStep(msg)
 	}
@@ -0,0 +1,43 @@
+package step
+
+import (
+	"fmt"
+)
+
+//prefixedStep prepends a prefix to all messages of a step
+type prefixedStep struct {
+	Step
+	prefix string
+}
+
+func newPrefixedStep(prefix string, s Step) Step {
+	return &prefixedStep{Step: s, prefix: prefix}
+}
+
+func (s *prefixedStep) Successf(format string, args ...interface{}) {
+	s.Step.Successf("%s%s", s.prefix, fmt.Sprintf(format, args...))
+}
+
+func (s *prefixedStep) Failuref(format string, args ...interface{}) {
+	s.Step.Failuref("%s%s", s.prefix, fmt.Sprintf(format, args...))
+}
+
+func (s *prefixedStep) Stopf(success bool, format string, args ...interface{}) {
+	s.Step.Stopf(success, "%s%s", s.prefix, fmt.Sprintf(format, args...))
+}
+
+func (s *prefixedStep) LogInfo(msg string) {
+	s.Step.LogInfo(s.prefix + msg)
+}
+
+func (s *prefixedStep) LogInfof(format string, args ...interface{}) {
+	s.LogInfo(fmt.Sprintf(format, args...))
+}
+
+func (s *prefixedStep) LogError(msg string) {
+	s.Step.LogError(s.prefix + msg)
+}
+
+func (s *prefixedStep) LogErrorf(format string, args ...interface{}) {
+	s.LogError(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,151 @@
+package timings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+)
+
+//traceEvent is a complete or metadata event of the Chrome trace event format
+type traceEvent struct {
+	Name     string            `json:"name"`
+	Category string            `json:"cat,omitempty"`
+	Phase    string            `json:"ph"`
+	TS       int64             `json:"ts"`
+	Duration int64             `json:"dur,omitempty"`
+	PID      int               `json:"pid"`
+	TID      int               `json:"tid"`
+	Args     map[string]string `json:"args,omitempty"`
+}
+
+//estimateMarker marks estimated values in the printed report
+const estimateMarker = "~"
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+//Print writes the components sorted by their duration, followed by the duration and worker utilisation of each phase.
+//Estimated start times and durations are marked with "~".
+func (r *Report) Print(w io.Writer) {
+	var comps []Span
+	for _, phase := range r.Phases {
+		comps = append(comps, phase.Components...)
+	}
+	sort.SliceStable(comps, func(i, j int) bool { return comps[i].Duration > comps[j].Duration })
+
+	fmt.Fprintln(w)
+	writer := tablewriter.NewWriter(w)
+	writer.SetBorder(false)
+	writer.SetHeader([]string{"COMPONENT", "PHASE", "STARTED", "DURATION", "STATUS"})
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetHeaderLine(false)
+	writer.SetRowSeparator("")
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+	var estimated bool
+	for _, comp := range comps {
+		var marker string
+		if comp.Estimated {
+			marker = estimateMarker
+			estimated = true
+		}
+		writer.Append([]string{
+			comp.Component,
+			comp.Phase,
+			marker + "+" + round(comp.Start.Sub(r.Start)).String(),
+			marker + round(comp.Duration).String(),
+			comp.Status,
+		})
+	}
+	writer.Render()
+	if estimated {
+		fmt.Fprintf(w, "%s estimated: the deployment only reports when a component is finished, its start is derived from the order in which the workers take the components\n", estimateMarker)
+	}
+
+	fmt.Fprintln(w)
+	for _, phase := range r.Phases {
+		fmt.Fprintf(w, "%s: %s, %d components, %d workers (%.0f%% utilised)\n",
+			phase.Phase, round(phase.Duration), len(phase.Components), phase.Workers, phase.Utilisation()*100)
+	}
+}
+
+//WriteTrace writes the timings in the Chrome trace event format, which can be opened in chrome://tracing or https://ui.perfetto.dev.
+//Each phase is shown as process whose threads are the workers processing the components.
+func (r *Report) WriteTrace(w io.Writer) error {
+	t := trace{TraceEvents: []traceEvent{}, DisplayTimeUnit: "ms"}
+	for i, phase := range r.Phases {
+		pid := i + 1
+		t.TraceEvents = append(t.TraceEvents,
+			traceEvent{Name: "process_name", Phase: "M", PID: pid, Args: map[string]string{"name": phase.Phase}},
+			traceEvent{Name: "thread_name", Phase: "M", PID: pid, TID: 0, Args: map[string]string{"name": "phase"}},
+			traceEvent{Name: phase.Phase, Category: "phase", Phase: "X", TS: r.micros(phase.Start), Duration: micros(phase.Duration), PID: pid, TID: 0},
+		)
+		for worker := 1; worker <= phase.Workers; worker++ {
+			t.TraceEvents = append(t.TraceEvents,
+				traceEvent{Name: "thread_name", Phase: "M", PID: pid, TID: worker, Args: map[string]string{"name": fmt.Sprintf("worker %d", worker)}})
+		}
+		for _, comp := range phase.Components {
+			t.TraceEvents = append(t.TraceEvents, traceEvent{
+				Name:     comp.Component,
+				Category: "component",
+				Phase:    "X",
+				TS:       r.micros(comp.Start),
+				Duration: micros(comp.Duration),
+				PID:      pid,
+				TID:      comp.Worker + 1,
+				Args:     componentArgs(comp),
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Could not marshal the timings")
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+//WriteTraceFile writes the timings in the Chrome trace event format to the file
+func (r *Report) WriteTraceFile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.Wrapf(err, "Could not create timings file '%s'", file)
+	}
+	defer f.Close()
+	if err := r.WriteTrace(f); err != nil {
+		return errors.Wrapf(err, "Could not write timings file '%s'", file)
+	}
+	return nil
+}
+
+//componentArgs returns the arguments of the trace event of a component
+func componentArgs(comp Span) map[string]string {
+	args := map[string]string{"status": comp.Status}
+	if comp.Estimated {
+		args["start"] = "estimated"
+	}
+	return args
+}
+
+//micros returns the microseconds between the start of the report and the given time
+func (r *Report) micros(t time.Time) int64 {
+	return micros(t.Sub(r.Start))
+}
+
+func micros(d time.Duration) int64 {
+	return int64(d / time.Microsecond)
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Second)
+}
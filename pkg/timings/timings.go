@@ -0,0 +1,256 @@
+package timings
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+)
+
+//Span is the time range in which a phase or a component was processed
+type Span struct {
+	Phase string `json:"phase"`
+	//Component is empty for phases
+	Component string        `json:"component,omitempty"`
+	Status    string        `json:"status,omitempty"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Duration  time.Duration `json:"duration"`
+	//Worker is the index of the worker which processed the component
+	Worker int `json:"worker"`
+	//Estimated is true if the start and the worker were derived from the order of the component queue instead of being reported
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+//PhaseReport contains the timings of a phase and of the components processed in it
+type PhaseReport struct {
+	Span
+	Workers    int    `json:"workers"`
+	Components []Span `json:"components"`
+}
+
+//Utilisation returns the share of the phase duration in which the workers processed components
+func (p PhaseReport) Utilisation() float64 {
+	capacity := p.Duration * time.Duration(p.Workers)
+	if capacity <= 0 {
+		return 0
+	}
+	var busy time.Duration
+	for _, comp := range p.Components {
+		busy += comp.Duration
+	}
+	return float64(busy) / float64(capacity)
+}
+
+//Report contains the timings of all phases in the order they started
+type Report struct {
+	Start  time.Time     `json:"start"`
+	Phases []PhaseReport `json:"phases"`
+}
+
+//Recorder records the start and end time of each phase and component from the process updates of a deployment or deletion
+type Recorder struct {
+	workers int
+	queues  map[deployment.InstallationPhase][]string
+	now     func() time.Time
+
+	mu     sync.Mutex
+	phases []*phase
+}
+
+//phase contains the recorded events of a phase
+type phase struct {
+	name       deployment.InstallationPhase
+	start      time.Time
+	end        time.Time
+	components []Span
+}
+
+//NewRecorder creates a recorder for a process which runs the given number of workers in parallel.
+//The queues define the order in which the components of each phase are passed to the workers.
+func NewRecorder(workers int, queues map[deployment.InstallationPhase][]string) *Recorder {
+	return &Recorder{
+		workers: workers,
+		queues:  queues,
+		now:     time.Now,
+	}
+}
+
+//Callback records each received process update and passes it on to the next callback (if any)
+func (r *Recorder) Callback(next func(deployment.ProcessUpdate)) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		r.record(update)
+		if next != nil {
+			next(update)
+		}
+	}
+}
+
+func (r *Recorder) record(update deployment.ProcessUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	p := r.phase(update.Phase)
+	switch {
+	case update.IsComponentUpdate():
+		if p == nil {
+			p = &phase{name: update.Phase, start: now}
+			r.phases = append(r.phases, p)
+		}
+		p.components = append(p.components, Span{
+			Phase:     string(update.Phase),
+			Component: update.Component.Name,
+			Status:    update.Component.Status,
+			End:       now,
+		})
+	case update.Event == deployment.ProcessStart:
+		if p == nil {
+			r.phases = append(r.phases, &phase{name: update.Phase, start: now})
+		}
+	case update.Event != deployment.ProcessRunning:
+		if p != nil {
+			p.end = now
+		}
+	}
+}
+
+func (r *Recorder) phase(name deployment.InstallationPhase) *phase {
+	for _, p := range r.phases {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+//Report returns the timings of all phases and components recorded so far.
+//The process updates only report when a component is finished. The start of a component is the time its worker became available,
+//which is derived from the order of the component queue: the first components start with the phase, each further component starts
+//when a worker finished its previous component.
+func (r *Recorder) Report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{}
+	for _, p := range r.phases {
+		end := p.end
+		if end.IsZero() {
+			// phase did not finish (e.g. the process was aborted)
+			end = r.now()
+		}
+		workers := r.workersOf(p)
+		report.Phases = append(report.Phases, PhaseReport{
+			Span:       Span{Phase: string(p.name), Start: p.start, End: end, Duration: end.Sub(p.start)},
+			Workers:    workers,
+			Components: schedule(p.start, workers, r.queues[p.name], p.components),
+		})
+	}
+	if len(report.Phases) > 0 {
+		report.Start = report.Phases[0].Start
+	}
+	return report
+}
+
+//workersOf returns the number of parallel workers of a phase.
+//Pre-requisites are processed one after another, the components of phases which are not run by the workers
+//(e.g. triggered by the CLI after the deployment) are processed all at once.
+func (r *Recorder) workersOf(p *phase) int {
+	switch p.name {
+	case deployment.InstallPreRequisites, deployment.UninstallPreRequisites:
+		return 1
+	case deployment.InstallComponents, deployment.UninstallComponents:
+		if r.workers > 0 {
+			return r.workers
+		}
+		return 1
+	}
+	if len(p.components) > 0 {
+		return len(p.components)
+	}
+	return 1
+}
+
+//schedule derives the start time and worker of each finished component
+func schedule(start time.Time, workers int, queue []string, finished []Span) []Span {
+	ends := make([]time.Time, len(finished))
+	for i, comp := range finished {
+		ends[i] = comp.End
+	}
+	sort.Slice(ends, func(i, j int) bool { return ends[i].Before(ends[j]) })
+
+	// the first components start with the phase, all others when a worker finished a component
+	starts := make([]time.Time, 0, len(finished))
+	for i := 0; i < len(finished); i++ {
+		if i < workers {
+			starts = append(starts, start)
+		} else {
+			starts = append(starts, ends[i-workers])
+		}
+	}
+
+	result := queued(queue, finished)
+	for i := range result {
+		result[i].Start = starts[i]
+	}
+	if !consistent(result) {
+		// the components were not processed in the order of the queue: fall back to the order in which they finished
+		result = queued(nil, finished)
+		for i := range result {
+			result[i].Start = starts[i]
+		}
+	}
+
+	// assign each component to the worker which was available first
+	available := make([]time.Time, workers)
+	for i := range available {
+		available[i] = start
+	}
+	for i := range result {
+		worker := 0
+		for w := range available {
+			if !available[w].After(result[i].Start) {
+				worker = w
+				break
+			}
+		}
+		result[i].Worker = worker
+		result[i].Duration = result[i].End.Sub(result[i].Start)
+		result[i].Estimated = true
+		available[worker] = result[i].End
+	}
+	return result
+}
+
+//queued sorts the finished components in the order of the queue. Components missing in the queue follow in the order they finished.
+func queued(queue []string, finished []Span) []Span {
+	position := make(map[string]int)
+	for i, name := range queue {
+		if _, ok := position[name]; !ok {
+			position[name] = i
+		}
+	}
+	result := append([]Span{}, finished...)
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, iQueued := position[result[i].Component]
+		pj, jQueued := position[result[j].Component]
+		switch {
+		case iQueued && jQueued:
+			return pi < pj
+		case iQueued != jQueued:
+			return iQueued
+		}
+		return result[i].End.Before(result[j].End)
+	})
+	return result
+}
+
+func consistent(spans []Span) bool {
+	for _, span := range spans {
+		if span.Start.After(span.End) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package timings
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestReport(t *testing.T) {
+	t.Run("Components are scheduled in the order of the queue", func(t *testing.T) {
+		report := record([]string{"a", "b", "c", "d"})
+		require.Len(t, report.Phases, 1)
+
+		phase := report.Phases[0]
+		require.Equal(t, string(deployment.InstallComponents), phase.Phase)
+		require.Equal(t, 10*time.Second, phase.Duration)
+		require.Equal(t, 2, phase.Workers)
+		require.InDelta(t, 0.65, phase.Utilisation(), 0.001)
+
+		expected := []struct {
+			name     string
+			start    time.Duration
+			duration time.Duration
+			worker   int
+		}{
+			{"a", 0, 10 * time.Second, 0},
+			{"b", 0, time.Second, 1},
+			{"c", time.Second, time.Second, 1},
+			{"d", 2 * time.Second, time.Second, 1},
+		}
+		require.Len(t, phase.Components, len(expected))
+		for i, exp := range expected {
+			comp := phase.Components[i]
+			require.Equal(t, exp.name, comp.Component)
+			require.Equal(t, exp.start, comp.Start.Sub(start), comp.Component)
+			require.Equal(t, exp.duration, comp.Duration, comp.Component)
+			require.Equal(t, exp.worker, comp.Worker, comp.Component)
+			require.True(t, comp.Estimated, comp.Component)
+		}
+	})
+
+	t.Run("Components without queue are scheduled in the order they finished", func(t *testing.T) {
+		report := record(nil)
+		comps := report.Phases[0].Components
+		require.Equal(t, "b", comps[0].Component)
+		require.Equal(t, "a", comps[3].Component)
+		for _, comp := range comps {
+			require.False(t, comp.Start.After(comp.End), comp.Component)
+		}
+	})
+}
+
+func TestOutput(t *testing.T) {
+	report := record([]string{"a", "b", "c", "d"})
+
+	t.Run("Table", func(t *testing.T) {
+		var buf bytes.Buffer
+		report.Print(&buf)
+		require.Contains(t, buf.String(), "COMPONENT")
+		require.Regexp(t, `a\s+InstallComponents\s+~\+0s\s+~10s\s+Installed`, buf.String())
+		require.Contains(t, buf.String(), "~ estimated: ")
+		require.Contains(t, buf.String(), "InstallComponents: 10s, 4 components, 2 workers (65% utilised)")
+	})
+
+	t.Run("Trace", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteTrace(&buf))
+
+		var result trace
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		var complete []traceEvent
+		for _, event := range result.TraceEvents {
+			if event.Phase == "X" {
+				complete = append(complete, event)
+			}
+		}
+		require.Len(t, complete, 5)
+		require.Equal(t, traceEvent{
+			Name: "c", Category: "component", Phase: "X", TS: 1000000, Duration: 1000000, PID: 1, TID: 2,
+			Args: map[string]string{"status": components.StatusInstalled, "start": "estimated"},
+		}, complete[3])
+	})
+}
+
+//record sends the updates of a phase with two workers: "a" takes 10 seconds, "b", "c", and "d" one second each
+func record(queue []string) *Report {
+	recorder := NewRecorder(2, map[deployment.InstallationPhase][]string{deployment.InstallComponents: queue})
+	var now time.Time
+	recorder.now = func() time.Time { return now }
+	callback := recorder.Callback(nil)
+
+	send := func(offset time.Duration, update deployment.ProcessUpdate) {
+		now = start.Add(offset)
+		update.Phase = deployment.InstallComponents
+		callback(update)
+	}
+	finished := func(name string) deployment.ProcessUpdate {
+		return deployment.ProcessUpdate{
+			Event:     deployment.ProcessRunning,
+			Component: components.KymaComponent{Name: name, Status: components.StatusInstalled},
+		}
+	}
+	send(0, deployment.ProcessUpdate{Event: deployment.ProcessStart})
+	send(time.Second, finished("b"))
+	send(2*time.Second, finished("c"))
+	send(3*time.Second, finished("d"))
+	send(10*time.Second, finished("a"))
+	send(10*time.Second, deployment.ProcessUpdate{Event: deployment.ProcessFinished})
+
+	return recorder.Report()
+}